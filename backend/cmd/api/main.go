@@ -1,19 +1,28 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
+	"privo-club-backend/internal/access"
 	"privo-club-backend/internal/auth"
 	"privo-club-backend/internal/config"
 	"privo-club-backend/internal/db"
+	"privo-club-backend/internal/events"
+	"privo-club-backend/internal/geo"
 	"privo-club-backend/internal/handlers"
 	customMiddleware "privo-club-backend/internal/middleware"
+	"privo-club-backend/internal/notify"
 	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/tokens"
+	"privo-club-backend/internal/worker"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/lib/pq"
 )
 
 func main() {
@@ -24,6 +33,45 @@ func main() {
 	// Initialize Repository
 	repo := repository.NewRepository(database)
 
+	// Initialize Notifier
+	var notifier notify.Notifier = notify.NewLogNotifier()
+	if cfg.NotifyWebhookURL != "" {
+		notifier = notify.NewWebhookNotifier(cfg.NotifyWebhookURL)
+	}
+
+	// Initialize Geocoder
+	var geocoder geo.Geocoder = geo.NoGeocoder{}
+	if cfg.GeocoderURL != "" {
+		geocoder = geo.NewNominatimGeocoder(cfg.GeocoderURL, "privo.club (+"+cfg.AppURL+")")
+	}
+
+	// Background Workers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scheduler := worker.NewScheduler()
+	scheduler.Every(cfg.ReminderInterval, worker.NewReminderJob(repo.Reminders, notifier, cfg.ReminderOffsets))
+	scheduler.Every(cfg.ReminderInterval, worker.NewSignupSummaryJob(repo.Signups, cfg.SignupSummaryLead))
+	scheduler.Every(cfg.ReminderInterval, worker.NewVaultUnlockJob(repo.Vaults, notifier))
+	scheduler.Every(cfg.ReminderInterval, worker.NewRecapJob(repo.Recaps, repo.Invites, repo.CheckIns, repo.Expenses))
+	scheduler.Every(cfg.ReminderInterval, worker.NewMentionJob(repo.Feed, notifier))
+	scheduler.Start(ctx)
+
+	// Invite events come from Postgres triggers, so streams on every
+	// instance see writes made by the others
+	hub := events.NewHub()
+	listener := pq.NewListener(cfg.DatabaseURL, 10*time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("Invite events listener", "error", err)
+		}
+	})
+	if err := listener.Listen(events.Channel); err != nil {
+		slog.Error("Failed to listen for invite events", "error", err)
+		os.Exit(1)
+	}
+	defer listener.Close()
+	go hub.Listen(ctx, listener.Notify)
+
 	r := chi.NewRouter()
 	r.Use(customMiddleware.NewLogger(cfg.LogFilePath))
 	r.Use(middleware.Recoverer)
@@ -40,10 +88,24 @@ func main() {
 
 	authHandler := handlers.NewAuthHandler(repo.Auth)
 	circlesHandler := handlers.NewCirclesHandler(repo.Circles)
-	invitesHandler := handlers.NewInvitesHandler(repo.Invites)
-	feedHandler := handlers.NewFeedHandler(repo.Feed)
+	venuesHandler := handlers.NewVenuesHandler(repo.Venues)
+	accessPolicy := access.NewPolicy(repo.Access)
+
+	invitesHandler := handlers.NewInvitesHandler(repo.Invites, repo.Venues, accessPolicy, geocoder)
+	feedHandler := handlers.NewFeedHandler(repo.Feed, accessPolicy)
 	userHandler := handlers.NewUserHandler(repo.User)
-	mediaHandler := handlers.NewMediaHandler(repo.Media)
+	mediaHandler := handlers.NewMediaHandler(repo.Media, accessPolicy)
+	pollsHandler := handlers.NewPollsHandler(repo.Polls, repo.Invites, accessPolicy)
+	signupsHandler := handlers.NewSignupsHandler(repo.Signups, accessPolicy)
+	expensesHandler := handlers.NewExpensesHandler(repo.Expenses, accessPolicy)
+	ridesHandler := handlers.NewRidesHandler(repo.Rides, accessPolicy)
+	reportHandler := handlers.NewReportHandler(repo.Reports, accessPolicy)
+	templatesHandler := handlers.NewTemplatesHandler(repo.Templates, repo.Invites, accessPolicy, geocoder)
+	signer := tokens.NewSigner(cfg.NextAuthSecret)
+	checkInHandler := handlers.NewCheckInHandler(repo.CheckIns, accessPolicy, signer)
+	shareHandler := handlers.NewShareHandler(repo.Share, accessPolicy, signer, cfg.AppURL)
+	recapHandler := handlers.NewRecapHandler(repo.Recaps, accessPolicy)
+	eventsHandler := handlers.NewEventsHandler(hub, accessPolicy)
 
 	// Circles Routes (Mixed Public/Protected)
 	r.Route("/api/circles", func(r chi.Router) {
@@ -54,7 +116,18 @@ func main() {
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware)
 			circlesHandler.RegisterProtectedRoutes(r)
+			venuesHandler.RegisterRoutes(r)
+		})
+	})
+
+	// Public Share Links (guests without a NextAuth session)
+	r.Route("/api/public", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(customMiddleware.RateLimit(30, time.Minute, cfg.TrustedProxies))
+			shareHandler.RegisterPublicRoutes(r)
+			recapHandler.RegisterPublicRoutes(r)
 		})
+		recapHandler.RegisterPublicMediaRoutes(r)
 	})
 
 	// Protected Routes Group (Other resources)
@@ -62,14 +135,33 @@ func main() {
 		r.Use(authMiddleware)
 
 		r.Route("/api/auth", authHandler.RegisterRoutes)
-		r.Route("/api/invites", invitesHandler.RegisterRoutes)
-		r.Route("/api/feed", feedHandler.RegisterRoutes)
-		r.Route("/api/users", userHandler.RegisterRoutes)
+		r.Route("/api/invites", func(r chi.Router) {
+			invitesHandler.RegisterRoutes(r)
+			pollsHandler.RegisterRoutes(r)
+			signupsHandler.RegisterRoutes(r)
+			expensesHandler.RegisterRoutes(r)
+			ridesHandler.RegisterRoutes(r)
+			checkInHandler.RegisterRoutes(r)
+			reportHandler.RegisterRoutes(r)
+			shareHandler.RegisterHostRoutes(r)
+			feedHandler.RegisterRoutes(r)
+			feedHandler.RegisterCapsuleRoutes(r)
+			recapHandler.RegisterRoutes(r)
+			eventsHandler.RegisterRoutes(r)
+		})
+		r.Route("/api/templates", templatesHandler.RegisterRoutes)
+		r.Route("/api/users", func(r chi.Router) {
+			userHandler.RegisterRoutes(r)
+			feedHandler.RegisterMentionRoutes(r)
+		})
 		r.Route("/api/media", mediaHandler.RegisterRoutes)
 	})
 
-	// Static File Server
-	fileServer(r, "/uploads", "./uploads")
+	// Uploaded media, sealed until the vault unlocks
+	r.Route("/uploads", func(r chi.Router) {
+		r.Use(auth.OptionalMiddleware(cfg))
+		mediaHandler.RegisterFileRoutes(r)
+	})
 
 	slog.Info("Starting server", "port", cfg.Port)
 	if err := http.ListenAndServe(":"+cfg.Port, r); err != nil {
@@ -77,13 +169,3 @@ func main() {
 		os.Exit(1)
 	}
 }
-
-func fileServer(r chi.Router, path string, root string) {
-	if _, err := os.Stat(root); os.IsNotExist(err) {
-		os.Mkdir(root, 0755)
-	}
-	fs := http.StripPrefix(path, http.FileServer(http.Dir(root)))
-	r.Get(path+"/*", func(w http.ResponseWriter, r *http.Request) {
-		fs.ServeHTTP(w, r)
-	})
-}
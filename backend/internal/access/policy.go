@@ -0,0 +1,150 @@
+package access
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/repository"
+)
+
+// Role describes how a user is related to an invite.
+type Role string
+
+const (
+	RoleNone    Role = ""
+	RoleHost    Role = "HOST"    // The invite's sender
+	RoleMember  Role = "MEMBER"  // ACTIVE member of the invite's circle
+	RoleInvitee Role = "INVITEE" // Invited directly, by account or resolved email
+)
+
+// Access is the outcome of a policy check for one user on one invite.
+type Access struct {
+	InviteID string
+	SenderID string
+	CircleID *string
+	UserID   string
+	Role     Role
+	// CircleRole is the user's role in the invite's circle (OWNER, ADMIN or
+	// MEMBER) while their membership is ACTIVE
+	CircleRole string
+}
+
+func (a *Access) IsHost() bool {
+	return a.Role == RoleHost
+}
+
+// CanView reports whether the user may read the invite's details, feed and media.
+func (a *Access) CanView() bool {
+	return a.Role != RoleNone
+}
+
+// CanModerate reports whether the user may hide or remove anyone's posts:
+// the host and the admins of the invite's circle.
+func (a *Access) CanModerate() bool {
+	return a.IsHost() || (a.Role != RoleNone && (a.CircleRole == "OWNER" || a.CircleRole == "ADMIN"))
+}
+
+// Policy is the single place that decides who may see or contribute to an invite.
+// Membership is read from the database on every check, so removed or PENDING
+// members lose access immediately.
+type Policy struct {
+	Repo repository.AccessRepository
+}
+
+func NewPolicy(repo repository.AccessRepository) *Policy {
+	return &Policy{Repo: repo}
+}
+
+// Check resolves the user's role on the invite. It returns sql.ErrNoRows if the
+// invite does not exist.
+func (p *Policy) Check(ctx context.Context, inviteID, userID string) (*Access, error) {
+	row, err := p.Repo.GetInviteAccess(ctx, inviteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Access{
+		InviteID: row.InviteID,
+		SenderID: row.SenderID,
+		CircleID: row.CircleID,
+		UserID:   userID,
+	}
+	if row.CircleRole != nil {
+		a.CircleRole = *row.CircleRole
+	}
+	switch {
+	case row.SenderID == userID:
+		a.Role = RoleHost
+	case row.IsActiveMember:
+		a.Role = RoleMember
+	case row.IsInvitee:
+		a.Role = RoleInvitee
+	}
+	return a, nil
+}
+
+// RequireView returns the user's access or an API error suitable for returning
+// from a handler. Users without access get a 404 so invite IDs can't be probed.
+func (p *Policy) RequireView(ctx context.Context, inviteID, userID string) (*Access, error) {
+	a, err := p.check(ctx, inviteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.CanView() {
+		return nil, api.ErrNotFound("Invite not found")
+	}
+	return a, nil
+}
+
+// RequirePost guards contributions (RSVPs, posts, uploads). Everyone who can
+// view an invite may contribute to it, so it is the same check as RequireView.
+func (p *Policy) RequirePost(ctx context.Context, inviteID, userID string) (*Access, error) {
+	return p.RequireView(ctx, inviteID, userID)
+}
+
+// RequireHost only lets the invite's sender through; message explains the refusal.
+func (p *Policy) RequireHost(ctx context.Context, inviteID, userID, message string) (*Access, error) {
+	a, err := p.check(ctx, inviteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.CanView() {
+		return nil, api.ErrNotFound("Invite not found")
+	}
+	if !a.IsHost() {
+		return nil, api.ErrForbidden(message)
+	}
+	return a, nil
+}
+
+// RequireModerator only lets the host and circle admins through; message
+// explains the refusal.
+func (p *Policy) RequireModerator(ctx context.Context, inviteID, userID, message string) (*Access, error) {
+	a, err := p.check(ctx, inviteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.CanView() {
+		return nil, api.ErrNotFound("Invite not found")
+	}
+	if !a.CanModerate() {
+		return nil, api.ErrForbidden(message)
+	}
+	return a, nil
+}
+
+func (p *Policy) check(ctx context.Context, inviteID, userID string) (*Access, error) {
+	if inviteID == "" {
+		return nil, api.ErrBadRequest("Invite ID required")
+	}
+	a, err := p.Check(ctx, inviteID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound("Invite not found")
+	}
+	if err != nil {
+		return nil, api.ErrInternal(err)
+	}
+	return a, nil
+}
@@ -0,0 +1,158 @@
+package access
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	policy := NewPolicy(repository.NewAccessRepository(sqlx.NewDb(mockDB, "sqlmock")))
+
+	// accessRow answers the access query the way Postgres would for one user
+	accessRow := func(userID, senderID string, isActiveMember, isInvitee bool, circleRole interface{}) func() {
+		return func() {
+			mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+				WithArgs("invite-1", userID).
+				WillReturnRows(sqlmock.NewRows([]string{"inviteId", "senderId", "circleId", "isActiveMember", "isInvitee", "circleRole"}).
+					AddRow("invite-1", senderID, "circle-1", isActiveMember, isInvitee, circleRole))
+		}
+	}
+
+	checks := map[string]func(ctx context.Context, inviteID, userID string) (*Access, error){
+		"RequireView": policy.RequireView,
+		"RequirePost": policy.RequirePost,
+		"RequireHost": func(ctx context.Context, inviteID, userID string) (*Access, error) {
+			return policy.RequireHost(ctx, inviteID, userID, "Only the host can do that")
+		},
+		"RequireModerator": func(ctx context.Context, inviteID, userID string) (*Access, error) {
+			return policy.RequireModerator(ctx, inviteID, userID, "Only moderators can do that")
+		},
+	}
+
+	tests := []struct {
+		name         string
+		inviteID     string
+		userID       string
+		mockBehavior func()
+		expectedRole Role
+		// Status per check; 200 means the check let the user through
+		expectedStatus map[string]int
+	}{
+		{
+			name:           "Host",
+			inviteID:       "invite-1",
+			userID:         "user-host",
+			mockBehavior:   accessRow("user-host", "user-host", true, false, "OWNER"),
+			expectedRole:   RoleHost,
+			expectedStatus: map[string]int{"RequireView": 200, "RequirePost": 200, "RequireHost": 200, "RequireModerator": 200},
+		},
+		{
+			name:           "Circle member",
+			inviteID:       "invite-1",
+			userID:         "user-123",
+			mockBehavior:   accessRow("user-123", "user-host", true, false, "MEMBER"),
+			expectedRole:   RoleMember,
+			expectedStatus: map[string]int{"RequireView": 200, "RequirePost": 200, "RequireHost": 403, "RequireModerator": 403},
+		},
+		{
+			name:           "Circle admin",
+			inviteID:       "invite-1",
+			userID:         "user-123",
+			mockBehavior:   accessRow("user-123", "user-host", true, false, "ADMIN"),
+			expectedRole:   RoleMember,
+			expectedStatus: map[string]int{"RequireView": 200, "RequirePost": 200, "RequireHost": 403, "RequireModerator": 200},
+		},
+		{
+			name:           "Direct invitee",
+			inviteID:       "invite-1",
+			userID:         "user-123",
+			mockBehavior:   accessRow("user-123", "user-host", false, true, nil),
+			expectedRole:   RoleInvitee,
+			expectedStatus: map[string]int{"RequireView": 200, "RequirePost": 200, "RequireHost": 403, "RequireModerator": 403},
+		},
+		{
+			// A PENDING or removed membership neither counts as active nor has a circle role
+			name:           "Inactive member",
+			inviteID:       "invite-1",
+			userID:         "user-123",
+			mockBehavior:   accessRow("user-123", "user-host", false, false, nil),
+			expectedStatus: map[string]int{"RequireView": 404, "RequirePost": 404, "RequireHost": 404, "RequireModerator": 404},
+		},
+		{
+			name:           "Removed invitee",
+			inviteID:       "invite-1",
+			userID:         "user-456",
+			mockBehavior:   accessRow("user-456", "user-host", false, false, nil),
+			expectedStatus: map[string]int{"RequireView": 404, "RequirePost": 404, "RequireHost": 404, "RequireModerator": 404},
+		},
+		{
+			name:     "Missing invite",
+			inviteID: "invite-1",
+			userID:   "user-123",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+					WithArgs("invite-1", "user-123").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: map[string]int{"RequireView": 404, "RequirePost": 404, "RequireHost": 404, "RequireModerator": 404},
+		},
+		{
+			name:     "Database error",
+			inviteID: "invite-1",
+			userID:   "user-123",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+					WithArgs("invite-1", "user-123").
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedStatus: map[string]int{"RequireView": 500, "RequirePost": 500, "RequireHost": 500, "RequireModerator": 500},
+		},
+		{
+			name:           "Missing invite ID",
+			inviteID:       "",
+			userID:         "user-123",
+			mockBehavior:   func() {},
+			expectedStatus: map[string]int{"RequireView": 400, "RequirePost": 400, "RequireHost": 400, "RequireModerator": 400},
+		},
+	}
+
+	for _, tt := range tests {
+		for check, expectedStatus := range tt.expectedStatus {
+			t.Run(tt.name+"/"+check, func(t *testing.T) {
+				tt.mockBehavior()
+				a, err := checks[check](context.Background(), tt.inviteID, tt.userID)
+
+				if expectedStatus == http.StatusOK {
+					assert.NoError(t, err)
+					if assert.NotNil(t, a) {
+						assert.Equal(t, tt.expectedRole, a.Role)
+					}
+				} else {
+					var appErr *api.AppError
+					if assert.True(t, errors.As(err, &appErr)) {
+						assert.Equal(t, expectedStatus, appErr.Code)
+					}
+					assert.Nil(t, a)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("unfulfilled expectations: %s", err)
+				}
+			})
+		}
+	}
+}
@@ -14,93 +14,125 @@ import (
 
 type contextKey string
 
-const UserIDKey contextKey = "userID"
+const (
+	UserIDKey        contextKey = "userID"
+	VerifiedEmailKey contextKey = "verifiedEmail"
+)
 
 // Middleware verifies the NextAuth session token (JWS)
 func Middleware(cfg *config.Config) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// 1. Get Token from Header or Cookie
-			authHeader := r.Header.Get("Authorization")
-			tokenString := ""
-
-			if authHeader != "" {
-				parts := strings.Split(authHeader, " ")
-				if len(parts) == 2 && parts[0] == "Bearer" {
-					tokenString = parts[1]
-				}
-			} else {
-				// Try reading cookie from a list of possible names
-				possibleCookieNames := []string{
-					"__Secure-authjs.session-token",
-					"authjs.session-token",
-					"__Secure-next-auth.session-token",
-					"next-auth.session-token",
-				}
-
-				for _, name := range possibleCookieNames {
-					c, err := r.Cookie(name)
-					if err == nil && c.Value != "" {
-						tokenString = c.Value
-						break
-					}
-				}
-			}
-
-			if tokenString == "" {
-				http.Error(w, "Unauthorized: No token provided", http.StatusUnauthorized)
+			userID, claims, reason := authenticate(cfg, r)
+			if userID == "" {
+				http.Error(w, "Unauthorized: "+reason, http.StatusUnauthorized)
 				return
 			}
 
-			// 2. Dev Token Bypass
-			if cfg.Environment == "development" && tokenString == "dev-token" {
-				log.Println("Auth: Using Dev Token Bypass")
-				// Set a fixed dev user ID
-				ctx := context.WithValue(r.Context(), UserIDKey, "dev-user-id")
-				next.ServeHTTP(w, r.WithContext(ctx))
-				return
-			}
+			next.ServeHTTP(w, r.WithContext(withSession(r.Context(), userID, claims)))
+		})
+	}
+}
 
-			// 3. Parse Signed Token (JWS)
-			tok, err := jwt.ParseSigned(tokenString)
-			if err != nil {
-				log.Printf("Auth Error: Invalid token format: %v", err)
-				http.Error(w, "Unauthorized: Invalid token format", http.StatusUnauthorized)
-				return
+// OptionalMiddleware sets the user ID when the request carries a valid
+// session and lets anonymous requests through, for resources like uploads
+// that browsers fetch without an Authorization header.
+func OptionalMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if userID, claims, _ := authenticate(cfg, r); userID != "" {
+				r = r.WithContext(withSession(r.Context(), userID, claims))
 			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
 
-			// 4. Verify Signature & Extract Claims
-			var claims map[string]interface{}
-			// We use the NextAuth Secret as the HMAC key
-			if err := tok.Claims([]byte(cfg.NextAuthSecret), &claims); err != nil {
-				log.Printf("Auth Error: Invalid signature: %v", err)
-				http.Error(w, "Unauthorized: Invalid token signature", http.StatusUnauthorized)
-				return
-			}
+// withSession stores the user ID, and the email address when the token says
+// the identity provider verified it.
+func withSession(ctx context.Context, userID string, claims map[string]interface{}) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, userID)
+	if verified, _ := claims["email_verified"].(bool); verified {
+		if email, _ := claims["email"].(string); email != "" {
+			ctx = context.WithValue(ctx, VerifiedEmailKey, email)
+		}
+	}
+	return ctx
+}
 
-			// 5. Extract User ID
-			// Standard JWT 'sub' claim
-			sub, ok := claims["sub"].(string)
-			if !ok || sub == "" {
-				log.Printf("Auth Error: No 'sub' claim in token")
-				http.Error(w, "Unauthorized: Invalid token content", http.StatusUnauthorized)
-				return
+// authenticate returns the user ID and claims from the request's session
+// token, or an empty ID and the reason it was rejected.
+func authenticate(cfg *config.Config, r *http.Request) (string, map[string]interface{}, string) {
+	// 1. Get Token from Header or Cookie
+	authHeader := r.Header.Get("Authorization")
+	tokenString := ""
+
+	if authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			tokenString = parts[1]
+		}
+	} else {
+		// Try reading cookie from a list of possible names
+		possibleCookieNames := []string{
+			"__Secure-authjs.session-token",
+			"authjs.session-token",
+			"__Secure-next-auth.session-token",
+			"next-auth.session-token",
+		}
+
+		for _, name := range possibleCookieNames {
+			c, err := r.Cookie(name)
+			if err == nil && c.Value != "" {
+				tokenString = c.Value
+				break
 			}
+		}
+	}
 
-			// 6. Check Expiration
-			if exp, ok := claims["exp"].(float64); ok {
-				if int64(exp) < time.Now().Unix() {
-					log.Printf("Auth Error: Token expired")
-					http.Error(w, "Unauthorized: Token expired", http.StatusUnauthorized)
-					return
-				}
-			}
+	if tokenString == "" {
+		return "", nil, "No token provided"
+	}
 
-			// Success
-			ctx := context.WithValue(r.Context(), UserIDKey, sub)
-			next.ServeHTTP(w, r.WithContext(ctx))
-		})
+	// 2. Dev Token Bypass
+	if cfg.Environment == "development" && tokenString == "dev-token" {
+		log.Println("Auth: Using Dev Token Bypass")
+		// Fixed dev user ID
+		return "dev-user-id", nil, ""
+	}
+
+	// 3. Parse Signed Token (JWS)
+	tok, err := jwt.ParseSigned(tokenString)
+	if err != nil {
+		log.Printf("Auth Error: Invalid token format: %v", err)
+		return "", nil, "Invalid token format"
 	}
+
+	// 4. Verify Signature & Extract Claims
+	var claims map[string]interface{}
+	// We use the NextAuth Secret as the HMAC key
+	if err := tok.Claims([]byte(cfg.NextAuthSecret), &claims); err != nil {
+		log.Printf("Auth Error: Invalid signature: %v", err)
+		return "", nil, "Invalid token signature"
+	}
+
+	// 5. Extract User ID
+	// Standard JWT 'sub' claim
+	sub, ok := claims["sub"].(string)
+	if !ok || sub == "" {
+		log.Printf("Auth Error: No 'sub' claim in token")
+		return "", nil, "Invalid token content"
+	}
+
+	// 6. Check Expiration
+	if exp, ok := claims["exp"].(float64); ok {
+		if int64(exp) < time.Now().Unix() {
+			log.Printf("Auth Error: Token expired")
+			return "", nil, "Token expired"
+		}
+	}
+
+	return sub, claims, ""
 }
 
 // UserIDFromContext helper
@@ -108,3 +140,10 @@ func UserIDFromContext(ctx context.Context) (string, bool) {
 	id, ok := ctx.Value(UserIDKey).(string)
 	return id, ok
 }
+
+// VerifiedEmailFromContext returns the email address the identity provider
+// verified for this session, if the token carries one.
+func VerifiedEmailFromContext(ctx context.Context) (string, bool) {
+	email, ok := ctx.Value(VerifiedEmailKey).(string)
+	return email, ok
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +17,19 @@ type Config struct {
 	Environment    string
 	LogFilePath    string
 	AllowedOrigin  string
+	AppURL         string
+	// TrustedProxies is how many reverse proxies in front of the API append
+	// to X-Forwarded-For. With none, clients are told apart by RemoteAddr.
+	TrustedProxies int
+
+	// Background workers
+	NotifyWebhookURL  string
+	ReminderOffsets   []time.Duration
+	ReminderInterval  time.Duration
+	SignupSummaryLead time.Duration
+
+	// Geocoding (Nominatim compatible search endpoint); disabled when empty
+	GeocoderURL string
 }
 
 func Load() *Config {
@@ -49,6 +65,24 @@ func Load() *Config {
 		allowedOrigin = "http://localhost:3000"
 	}
 
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = allowedOrigin
+	}
+
+	trustedProxies := 0
+	if v := os.Getenv("TRUSTED_PROXY_COUNT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			log.Fatalf("Invalid TRUSTED_PROXY_COUNT %q", v)
+		}
+		trustedProxies = n
+	}
+
+	reminderOffsets := parseDurations(os.Getenv("REMINDER_OFFSETS"), "168h,24h,2h")
+	reminderInterval := parseDuration(os.Getenv("REMINDER_INTERVAL"), "5m")
+	signupSummaryLead := parseDuration(os.Getenv("SIGNUP_SUMMARY_LEAD"), "24h")
+
 	return &Config{
 		DatabaseURL:    dbURL,
 		NextAuthSecret: secret,
@@ -56,5 +90,40 @@ func Load() *Config {
 		Environment:    env,
 		LogFilePath:    os.Getenv("LOG_FILE_PATH"),
 		AllowedOrigin:  allowedOrigin,
+		AppURL:         appURL,
+		TrustedProxies: trustedProxies,
+
+		NotifyWebhookURL:  os.Getenv("NOTIFY_WEBHOOK_URL"),
+		ReminderOffsets:   reminderOffsets,
+		ReminderInterval:  reminderInterval,
+		SignupSummaryLead: signupSummaryLead,
+
+		GeocoderURL: os.Getenv("GEOCODER_URL"),
+	}
+}
+
+// parseDuration parses a Go duration string such as "5m", falling back to def when unset.
+func parseDuration(value, def string) time.Duration {
+	if value == "" {
+		value = def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid duration %q: %v", value, err)
+	}
+	return d
+}
+
+// parseDurations parses a comma separated list of durations such as "168h,24h,2h".
+func parseDurations(value, def string) []time.Duration {
+	if value == "" {
+		value = def
+	}
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			durations = append(durations, parseDuration(part, ""))
+		}
 	}
+	return durations
 }
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres NOTIFY channel the invite triggers publish on
+const Channel = "invite_events"
+
+// Event types
+const (
+	TypeFeedPost      = "feed.post"
+	TypeFeedUpdated   = "feed.updated" // Edited, hidden or removed
+	TypeRSVP          = "rsvp"
+	TypeMedia         = "media"
+	TypeVaultUnlocked = "vault.unlocked"
+	// TypeResync tells subscribers events may have been missed, for example
+	// while the database connection was down, and they should refetch.
+	TypeResync = "resync"
+)
+
+// subscriberBuffer is how many events a slow stream may fall behind before
+// it starts missing them
+const subscriberBuffer = 16
+
+// Event announces that something changed on an invite. It only carries IDs;
+// subscribers fetch the data through the usual access checks.
+type Event struct {
+	Type     string `json:"type"`
+	InviteID string `json:"inviteId"`
+	ID       string `json:"id,omitempty"`
+}
+
+// Hub fans events out to the streams open on this instance. Events come
+// from Postgres, so every instance sees the writes made by the others.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan Event]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[string]map[chan Event]struct{})}
+}
+
+// Subscribe returns the events of one invite and a func to stop receiving them
+func (h *Hub) Subscribe(inviteID string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[inviteID] == nil {
+		h.subs[inviteID] = make(map[chan Event]struct{})
+	}
+	h.subs[inviteID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subs[inviteID], ch)
+			if len(h.subs[inviteID]) == 0 {
+				delete(h.subs, inviteID)
+			}
+		})
+	}
+}
+
+// Publish delivers an event to the invite's subscribers. It never blocks:
+// a subscriber whose buffer is full misses the event.
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[e.InviteID] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Listen publishes the notifications of a pq.Listener on Channel until ctx
+// is done. The listener sends nil after reconnecting, which is passed on to
+// every subscriber as a resync.
+func (h *Hub) Listen(ctx context.Context, notifications <-chan *pq.Notification) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-notifications:
+			if n == nil {
+				h.resync()
+				continue
+			}
+			var e Event
+			if err := json.Unmarshal([]byte(n.Extra), &e); err != nil || e.InviteID == "" {
+				slog.Warn("ignoring invite event", "payload", n.Extra, "error", err)
+				continue
+			}
+			h.Publish(e)
+		}
+	}
+}
+
+func (h *Hub) resync() {
+	h.mu.Lock()
+	inviteIDs := make([]string, 0, len(h.subs))
+	for inviteID := range h.subs {
+		inviteIDs = append(inviteIDs, inviteID)
+	}
+	h.mu.Unlock()
+
+	for _, inviteID := range inviteIDs {
+		h.Publish(Event{Type: TypeResync, InviteID: inviteID})
+	}
+}
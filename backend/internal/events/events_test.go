@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func assertNoEvent(t *testing.T, ch <-chan Event) {
+	t.Helper()
+	select {
+	case e := <-ch:
+		t.Fatalf("unexpected event %+v", e)
+	default:
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	hub := NewHub()
+	a1, stopA1 := hub.Subscribe("invite-a")
+	a2, stopA2 := hub.Subscribe("invite-a")
+	b, stopB := hub.Subscribe("invite-b")
+	defer stopA1()
+	defer stopB()
+
+	hub.Publish(Event{Type: TypeRSVP, InviteID: "invite-a", ID: "rsvp-1"})
+	assert.Equal(t, Event{Type: TypeRSVP, InviteID: "invite-a", ID: "rsvp-1"}, receive(t, a1))
+	assert.Equal(t, "rsvp-1", receive(t, a2).ID)
+	assertNoEvent(t, b)
+
+	// Stopping twice is harmless
+	stopA2()
+	stopA2()
+	hub.Publish(Event{Type: TypeMedia, InviteID: "invite-a", ID: "media-1"})
+	assert.Equal(t, "media-1", receive(t, a1).ID)
+	assertNoEvent(t, a2)
+}
+
+func TestHubPublishDoesNotBlock(t *testing.T) {
+	hub := NewHub()
+	ch, stop := hub.Subscribe("invite-a")
+	defer stop()
+
+	for i := 0; i < subscriberBuffer+5; i++ {
+		hub.Publish(Event{Type: TypeFeedPost, InviteID: "invite-a"})
+	}
+	assert.Len(t, ch, subscriberBuffer)
+}
+
+func TestHubListen(t *testing.T) {
+	hub := NewHub()
+	a, stopA := hub.Subscribe("invite-a")
+	b, stopB := hub.Subscribe("invite-b")
+	defer stopA()
+	defer stopB()
+
+	notifications := make(chan *pq.Notification)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		hub.Listen(ctx, notifications)
+		close(done)
+	}()
+
+	notifications <- &pq.Notification{Channel: Channel, Extra: `{"type":"feed.post","inviteId":"invite-a","id":"feed-1"}`}
+	assert.Equal(t, Event{Type: TypeFeedPost, InviteID: "invite-a", ID: "feed-1"}, receive(t, a))
+
+	// Malformed payloads are skipped
+	notifications <- &pq.Notification{Channel: Channel, Extra: `not json`}
+	notifications <- &pq.Notification{Channel: Channel, Extra: `{"type":"rsvp"}`}
+
+	// A reconnect asks everyone to refetch
+	notifications <- nil
+	assert.Equal(t, Event{Type: TypeResync, InviteID: "invite-a"}, receive(t, a))
+	assert.Equal(t, Event{Type: TypeResync, InviteID: "invite-b"}, receive(t, b))
+
+	cancel()
+	<-done
+	assertNoEvent(t, a)
+	assertNoEvent(t, b)
+}
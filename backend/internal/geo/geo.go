@@ -0,0 +1,76 @@
+// Package geo handles coordinates for invite locations: extracting them from
+// map links, measuring distances and geocoding free-text addresses.
+package geo
+
+import (
+	"math"
+	"strconv"
+)
+
+const earthRadiusKm = 6371.0
+
+// Point is a WGS84 latitude/longitude pair in degrees.
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Valid reports whether the point lies within the latitude/longitude ranges.
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180 &&
+		!math.IsNaN(p.Lat) && !math.IsNaN(p.Lng)
+}
+
+// DistanceKm returns the great-circle distance between two points.
+func DistanceKm(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Bounds is a latitude/longitude box used to prefilter rows in SQL before
+// the exact distance is computed.
+type Bounds struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// BoundsAround returns a box that contains every point within radiusKm of
+// center. Near the poles or the antimeridian it widens to the full longitude
+// range rather than wrapping.
+func BoundsAround(center Point, radiusKm float64) Bounds {
+	dLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	b := Bounds{
+		MinLat: math.Max(-90, center.Lat-dLat),
+		MaxLat: math.Min(90, center.Lat+dLat),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+
+	if b.MinLat > -90 && b.MaxLat < 90 {
+		dLng := dLat / math.Cos(radians(center.Lat))
+		if center.Lng-dLng >= -180 && center.Lng+dLng <= 180 {
+			b.MinLng = center.Lng - dLng
+			b.MaxLng = center.Lng + dLng
+		}
+	}
+	return b
+}
+
+// FormatDistance renders a distance for display, e.g. "850 m" or "12.3 km".
+func FormatDistance(km float64) string {
+	if km < 1 {
+		return strconv.Itoa(int(km*1000/10)*10) + " m"
+	}
+	if km < 100 {
+		return strconv.FormatFloat(km, 'f', 1, 64) + " km"
+	}
+	return strconv.Itoa(int(km+0.5)) + " km"
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
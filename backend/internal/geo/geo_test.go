@@ -0,0 +1,108 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMapLink(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want Point
+		ok   bool
+	}{
+		{
+			name: "Google place pin beats viewport",
+			url:  "https://www.google.com/maps/place/Central+Park/@40.7812,-73.9665,15z/data=!3m1!4b1!4m6!3m5!1s0x0:0x0!8m2!3d40.7829!4d-73.9654",
+			want: Point{Lat: 40.7829, Lng: -73.9654},
+			ok:   true,
+		},
+		{
+			name: "Google viewport",
+			url:  "https://www.google.com/maps/@51.5007,-0.1246,17z",
+			want: Point{Lat: 51.5007, Lng: -0.1246},
+			ok:   true,
+		},
+		{
+			name: "Google search API",
+			url:  "https://www.google.com/maps/search/?api=1&query=48.8584,2.2945",
+			want: Point{Lat: 48.8584, Lng: 2.2945},
+			ok:   true,
+		},
+		{
+			name: "Google q parameter",
+			url:  "https://maps.google.com/?q=-33.8568,151.2153",
+			want: Point{Lat: -33.8568, Lng: 151.2153},
+			ok:   true,
+		},
+		{
+			name: "Apple ll with place name",
+			url:  "https://maps.apple.com/?q=Golden+Gate+Bridge&ll=37.8199,-122.4783",
+			want: Point{Lat: 37.8199, Lng: -122.4783},
+			ok:   true,
+		},
+		{
+			name: "Apple coordinate",
+			url:  "https://maps.apple.com/place?coordinate=35.6586,139.7454&name=Tokyo+Tower",
+			want: Point{Lat: 35.6586, Lng: 139.7454},
+			ok:   true,
+		},
+		{
+			name: "OpenStreetMap marker",
+			url:  "https://www.openstreetmap.org/?mlat=52.5163&mlon=13.3777#map=17/52.5160/13.3780",
+			want: Point{Lat: 52.5163, Lng: 13.3777},
+			ok:   true,
+		},
+		{
+			name: "OpenStreetMap viewport",
+			url:  "https://www.openstreetmap.org/#map=15/41.8902/12.4922",
+			want: Point{Lat: 41.8902, Lng: 12.4922},
+			ok:   true,
+		},
+		{name: "Search text only", url: "https://maps.apple.com/?q=Coffee+shop"},
+		{name: "Short link", url: "https://maps.app.goo.gl/abc123"},
+		{name: "Out of range", url: "https://maps.google.com/?q=95.0,10.0"},
+		{name: "Not a URL", url: "Joe's place"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseMapLink(tt.url)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.InDelta(t, tt.want.Lat, got.Lat, 1e-9)
+				assert.InDelta(t, tt.want.Lng, got.Lng, 1e-9)
+			}
+		})
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	paris := Point{Lat: 48.8566, Lng: 2.3522}
+	london := Point{Lat: 51.5074, Lng: -0.1278}
+
+	assert.InDelta(t, 343.5, DistanceKm(paris, london), 1)
+	assert.InDelta(t, 0, DistanceKm(paris, paris), 1e-9)
+}
+
+func TestBoundsAround(t *testing.T) {
+	center := Point{Lat: 40.7128, Lng: -74.0060}
+	b := BoundsAround(center, 10)
+
+	// The box edges due north and due east are about radius away
+	assert.InDelta(t, 10, DistanceKm(center, Point{Lat: b.MaxLat, Lng: center.Lng}), 0.01)
+	assert.InDelta(t, 10, DistanceKm(center, Point{Lat: center.Lat, Lng: b.MaxLng}), 0.1)
+
+	// Near the antimeridian the longitude range is not wrapped
+	fiji := BoundsAround(Point{Lat: -17.7, Lng: 179.9}, 50)
+	assert.Equal(t, -180.0, fiji.MinLng)
+	assert.Equal(t, 180.0, fiji.MaxLng)
+}
+
+func TestFormatDistance(t *testing.T) {
+	assert.Equal(t, "850 m", FormatDistance(0.854))
+	assert.Equal(t, "12.3 km", FormatDistance(12.34))
+	assert.Equal(t, "240 km", FormatDistance(239.6))
+}
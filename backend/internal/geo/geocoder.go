@@ -0,0 +1,129 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/models"
+)
+
+// ErrNoMatch is returned when a geocoder can't resolve an address.
+var ErrNoMatch = errors.New("geo: no match for address")
+
+// Geocoder turns a free-text address into a structured place with
+// coordinates. Implementations wrap a specific provider.
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (*models.Place, error)
+}
+
+// NoGeocoder is used when no provider is configured. Locations then only get
+// coordinates from map links or from the client.
+type NoGeocoder struct{}
+
+func (NoGeocoder) Geocode(ctx context.Context, query string) (*models.Place, error) {
+	return nil, ErrNoMatch
+}
+
+// NominatimGeocoder queries a Nominatim (OpenStreetMap) search endpoint.
+type NominatimGeocoder struct {
+	URL       string
+	UserAgent string // Nominatim's usage policy requires an identifying agent
+	Client    *http.Client
+}
+
+func NewNominatimGeocoder(baseURL, userAgent string) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		URL:       strings.TrimRight(baseURL, "/"),
+		UserAgent: userAgent,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type nominatimResult struct {
+	Lat     string `json:"lat"`
+	Lon     string `json:"lon"`
+	Name    string `json:"name"`
+	Address struct {
+		HouseNumber string `json:"house_number"`
+		Road        string `json:"road"`
+		City        string `json:"city"`
+		Town        string `json:"town"`
+		Village     string `json:"village"`
+		State       string `json:"state"`
+		Postcode    string `json:"postcode"`
+		Country     string `json:"country"`
+	} `json:"address"`
+}
+
+func (g *NominatimGeocoder) Geocode(ctx context.Context, query string) (*models.Place, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "jsonv2")
+	params.Set("addressdetails", "1")
+	params.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.URL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", g.UserAgent)
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoder returned %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNoMatch
+	}
+	return results[0].place()
+}
+
+func (r nominatimResult) place() (*models.Place, error) {
+	p, ok := pointFromStrings(r.Lat, r.Lon)
+	if !ok {
+		return nil, ErrNoMatch
+	}
+
+	a := r.Address
+	city := a.City
+	if city == "" {
+		city = a.Town
+	}
+	if city == "" {
+		city = a.Village
+	}
+
+	return &models.Place{
+		PlaceName:   optional(r.Name),
+		AddressLine: optional(strings.TrimSpace(a.HouseNumber + " " + a.Road)),
+		City:        optional(city),
+		Region:      optional(a.State),
+		PostalCode:  optional(a.Postcode),
+		Country:     optional(a.Country),
+		Latitude:    &p.Lat,
+		Longitude:   &p.Lng,
+	}, nil
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
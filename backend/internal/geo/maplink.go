@@ -0,0 +1,89 @@
+package geo
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// Google place pins are encoded as !3d<lat>!4d<lng> in the data parameter
+	googlePinPattern = regexp.MustCompile(`!3d(-?\d+(?:\.\d+)?)!4d(-?\d+(?:\.\d+)?)`)
+	// Google viewport: /@<lat>,<lng>,<zoom>z
+	googleViewportPattern = regexp.MustCompile(`@(-?\d+(?:\.\d+)?),(-?\d+(?:\.\d+)?)`)
+)
+
+// Query parameters that carry a "lat,lng" pair, in order of preference.
+// Google uses q, query, ll, destination and center; Apple uses ll, q, coordinate,
+// daddr and sll.
+var coordinateParams = []string{"query", "q", "coordinate", "ll", "destination", "daddr", "center", "sll"}
+
+// ParseMapLink extracts coordinates from a Google Maps, Apple Maps or
+// OpenStreetMap URL. Short links (maps.app.goo.gl, osm.org/go) and links that
+// only contain a search text have no coordinates and return false.
+func ParseMapLink(raw string) (Point, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return Point{}, false
+	}
+	q := u.Query()
+
+	// OpenStreetMap marker
+	if p, ok := pointFromStrings(q.Get("mlat"), q.Get("mlon")); ok {
+		return p, true
+	}
+
+	// An explicit place pin beats the map viewport
+	if m := googlePinPattern.FindStringSubmatch(u.Path + u.RawQuery); m != nil {
+		if p, ok := pointFromStrings(m[1], m[2]); ok {
+			return p, true
+		}
+	}
+
+	for _, name := range coordinateParams {
+		if p, ok := parsePair(q.Get(name)); ok {
+			return p, true
+		}
+	}
+
+	if m := googleViewportPattern.FindStringSubmatch(u.Path); m != nil {
+		if p, ok := pointFromStrings(m[1], m[2]); ok {
+			return p, true
+		}
+	}
+
+	// OpenStreetMap viewport: #map=<zoom>/<lat>/<lng>
+	if strings.HasPrefix(u.Fragment, "map=") {
+		parts := strings.Split(strings.TrimPrefix(u.Fragment, "map="), "/")
+		if len(parts) >= 3 {
+			if p, ok := pointFromStrings(parts[1], parts[2]); ok {
+				return p, true
+			}
+		}
+	}
+
+	return Point{}, false
+}
+
+// parsePair parses "lat,lng", ignoring surrounding spaces.
+func parsePair(s string) (Point, bool) {
+	lat, lng, found := strings.Cut(s, ",")
+	if !found {
+		return Point{}, false
+	}
+	return pointFromStrings(lat, lng)
+}
+
+func pointFromStrings(lat, lng string) (Point, bool) {
+	la, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return Point{}, false
+	}
+	ln, err := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if err != nil {
+		return Point{}, false
+	}
+	p := Point{Lat: la, Lng: ln}
+	return p, p.Valid()
+}
@@ -3,8 +3,11 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
 	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
 	"privo-club-backend/internal/models"
 	"privo-club-backend/internal/repository"
 
@@ -23,7 +26,15 @@ func (h *AuthHandler) RegisterRoutes(r chi.Router) {
 	r.Method("POST", "/sync", api.Handler(h.SyncUser))
 }
 
+// SyncUser records the signed-in user. Only the token can vouch for the email
+// address, because a verified address gives access to the direct invites
+// sent to it.
 func (h *AuthHandler) SyncUser(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
 	var req models.SyncUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return api.ErrBadRequest("Invalid request body")
@@ -32,13 +43,19 @@ func (h *AuthHandler) SyncUser(w http.ResponseWriter, r *http.Request) error {
 	if req.ID == "" || req.Email == "" {
 		return api.ErrBadRequest("Missing required fields")
 	}
+	if req.ID != userID {
+		return api.ErrForbidden("Cannot sync another user")
+	}
 
 	user := &models.User{
-		ID:            req.ID,
-		Name:          req.Name,
-		Email:         &req.Email,
-		Image:         req.Image,
-		EmailVerified: req.EmailVerified,
+		ID:    req.ID,
+		Name:  req.Name,
+		Email: &req.Email,
+		Image: req.Image,
+	}
+	if email, ok := auth.VerifiedEmailFromContext(r.Context()); ok && strings.EqualFold(email, req.Email) {
+		now := time.Now()
+		user.EmailVerified = &now
 	}
 
 	if err := h.Repo.SyncUser(r.Context(), user); err != nil {
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncUser(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	handler := NewAuthHandler(repository.NewAuthRepository(sqlx.NewDb(mockDB, "sqlmock")))
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		verifiedEmail  string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:          "Verified email links pending invites",
+			body:          map[string]interface{}{"id": "user-123", "email": "ann@example.com"},
+			verifiedEmail: "Ann@example.com",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "User"`).
+					WithArgs("user-123", nil, "ann@example.com", nil, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`UPDATE "InviteInvitee" inv`).
+					WithArgs("user-123", "ann@example.com").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			// The body cannot claim a verified address on its own
+			name: "Unverified email leaves invites alone",
+			body: map[string]interface{}{"id": "user-123", "email": "victim@example.com", "emailVerified": "2026-01-01T00:00:00Z"},
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "User"`).
+					WithArgs("user-123", nil, "victim@example.com", nil, nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:          "Email verified for another address",
+			body:          map[string]interface{}{"id": "user-123", "email": "victim@example.com"},
+			verifiedEmail: "ann@example.com",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "User"`).
+					WithArgs("user-123", nil, "victim@example.com", nil, nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "ID does not match the token",
+			body:           map[string]interface{}{"id": "user-456", "email": "ann@example.com"},
+			verifiedEmail:  "ann@example.com",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/auth/sync", bytes.NewBuffer(body))
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+			if tt.verifiedEmail != "" {
+				ctx = context.WithValue(ctx, auth.VerifiedEmailKey, tt.verifiedEmail)
+			}
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.SyncUser).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+)
+
+const (
+	calendarDateLayout = "2006-01-02"
+	maxCalendarDays    = 92 // A quarter, enough for any month grid with padding weeks
+)
+
+// ListCalendar returns every invite visible to the user between from and to
+// (inclusive dates, YYYY-MM-DD) grouped by day in tz (IANA name, default UTC).
+// Events spanning midnight are listed on each day they touch.
+func (h *InvitesHandler) ListCalendar(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	q := r.URL.Query()
+	tz := q.Get("tz")
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return api.ErrBadRequest("Unknown time zone")
+	}
+
+	from, err := time.ParseInLocation(calendarDateLayout, q.Get("from"), loc)
+	if err != nil {
+		return api.ErrBadRequest("from must be a date (YYYY-MM-DD)")
+	}
+	last, err := time.ParseInLocation(calendarDateLayout, q.Get("to"), loc)
+	if err != nil {
+		return api.ErrBadRequest("to must be a date (YYYY-MM-DD)")
+	}
+	to := last.AddDate(0, 0, 1)
+	if !to.After(from) {
+		return api.ErrBadRequest("to must not be before from")
+	}
+	if to.After(from.AddDate(0, 0, maxCalendarDays)) {
+		return api.ErrBadRequest(fmt.Sprintf("The range can span at most %d days", maxCalendarDays))
+	}
+
+	// Invite dates are stored as UTC without a zone, so the bounds must be UTC too
+	invites, err := h.Repo.ListCalendarInvites(r.Context(), userID, from.UTC(), to.UTC())
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(models.CalendarResponse{
+		From:     from,
+		To:       to,
+		TimeZone: loc.String(),
+		Days:     groupByDay(invites, from, to, loc),
+	})
+}
+
+// groupByDay buckets invites by local date within [from, to). Invites must be
+// sorted by start; days without invites are left out.
+func groupByDay(invites []models.CalendarInvite, from, to time.Time, loc *time.Location) []models.CalendarDay {
+	byDate := map[string][]models.CalendarInvite{}
+	for _, invite := range invites {
+		start := invite.EventDate.In(loc)
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		if day.Before(from) {
+			day = from
+		}
+		for ; day.Before(to) && day.Before(invite.EndDate); day = day.AddDate(0, 0, 1) {
+			key := day.Format(calendarDateLayout)
+			byDate[key] = append(byDate[key], invite)
+		}
+	}
+
+	days := []models.CalendarDay{}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format(calendarDateLayout)
+		if invites, ok := byDate[key]; ok {
+			days = append(days, models.CalendarDay{Date: key, Invites: invites})
+		}
+	}
+	return days
+}
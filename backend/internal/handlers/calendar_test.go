@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListCalendar(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewInvitesHandler(repository.NewInviteRepository(sqlxDB), repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	columns := []string{"id", "title", "eventDate", "endDate", "location", "senderId", "circleId", "isDateTBD", "rsvpStatus"}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockBehavior   func()
+		expectedStatus int
+		expectedDays   map[string][]string
+	}{
+		{
+			name:  "Grouped by local day",
+			query: "?from=2026-03-01&to=2026-03-31&tz=Europe/Berlin",
+			mockBehavior: func() {
+				// Midnight in Berlin, sent to the database as UTC
+				from := time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC)
+				to := time.Date(2026, 3, 31, 22, 0, 0, 0, time.UTC)
+				rows := sqlmock.NewRows(columns).
+					// 23:30 UTC on the 4th is already the 5th in Berlin
+					AddRow("invite-late", "Drinks", time.Date(2026, 3, 4, 23, 30, 0, 0, time.UTC), time.Date(2026, 3, 5, 2, 30, 0, 0, time.UTC), nil, "user-1", nil, false, "YES").
+					// Ends at midnight, so it does not show up on the 6th
+					AddRow("invite-dinner", "Dinner", time.Date(2026, 3, 5, 19, 0, 0, 0, berlin), time.Date(2026, 3, 6, 0, 0, 0, 0, berlin), nil, "user-1", nil, false, nil).
+					// Runs past the end of the range
+					AddRow("invite-trip", "Trip", time.Date(2026, 3, 30, 9, 0, 0, 0, berlin), time.Date(2026, 4, 2, 18, 0, 0, 0, berlin), nil, "user-2", nil, false, "MAYBE")
+				mock.ExpectQuery(`SELECT (.+) FROM "Invite" i WHERE i."eventDate" < \$3`).
+					WithArgs("user-123", from, to, models.DefaultEventDuration.Seconds()).
+					WillReturnRows(rows)
+			},
+			expectedStatus: http.StatusOK,
+			expectedDays: map[string][]string{
+				"2026-03-05": {"invite-late", "invite-dinner"},
+				"2026-03-30": {"invite-trip"},
+				"2026-03-31": {"invite-trip"},
+			},
+		},
+		{
+			name:  "Daylight saving day in New York",
+			query: "?from=2026-03-08&to=2026-03-08&tz=America/New_York",
+			mockBehavior: func() {
+				// Clocks go forward that night, so the day is 23 hours long
+				mock.ExpectQuery(`SELECT (.+) FROM "Invite" i WHERE i."eventDate" < \$3`).
+					WithArgs("user-123", time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC), time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC), models.DefaultEventDuration.Seconds()).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("invite-brunch", "Brunch", time.Date(2026, 3, 8, 15, 0, 0, 0, time.UTC), time.Date(2026, 3, 8, 17, 0, 0, 0, time.UTC), nil, "user-1", nil, false, "YES"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedDays: map[string][]string{
+				"2026-03-08": {"invite-brunch"},
+			},
+		},
+		{
+			name:           "Range too long",
+			query:          "?from=2026-01-01&to=2026-12-31",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown time zone",
+			query:          "?from=2026-03-01&to=2026-03-31&tz=Mars/Olympus",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Reversed range",
+			query:          "?from=2026-03-31&to=2026-03-01",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/invites/calendar"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-123"))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.ListCalendar).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedDays != nil {
+				var resp models.CalendarResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				days := map[string][]string{}
+				for _, day := range resp.Days {
+					for _, invite := range day.Invites {
+						days[day.Date] = append(days[day.Date], invite.ID)
+					}
+				}
+				assert.Equal(t, tt.expectedDays, days)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxCapsuleMessageLength = 2000
+
+// RegisterCapsuleRoutes mounts the time capsule under /api/invites
+func (h *FeedHandler) RegisterCapsuleRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/capsule", api.Handler(h.GetCapsule))
+	r.Method("POST", "/{id}/capsule", api.Handler(h.CreateCapsuleMessage))
+}
+
+// CreateCapsuleMessage seals a note to the group until the vault unlocks
+func (h *FeedHandler) CreateCapsuleMessage(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	var req models.CapsuleMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return api.ErrBadRequest("Message is required")
+	}
+	if len([]rune(content)) > maxCapsuleMessageLength {
+		return api.ErrBadRequest("Message is too long")
+	}
+
+	item := &models.EventFeedItem{
+		ID:        utils.GenerateID("feed"),
+		InviteID:  inviteID,
+		UserID:    userID,
+		Content:   content,
+		Type:      models.FeedTypeCapsule,
+		CreatedAt: time.Now(),
+	}
+	if err := h.Repo.CreateCapsuleMessage(r.Context(), item); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.NewAPIError(http.StatusConflict, "The vault is already unlocked, post to the feed instead", err)
+		}
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(item)
+}
+
+// GetCapsule returns every capsule message once the vault is unlocked.
+// Before that, attendees only see how many there are and their own.
+func (h *FeedHandler) GetCapsule(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	unlocked, err := h.Repo.IsVaultUnlocked(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	view := models.CapsuleView{Sealed: !unlocked}
+	author := userID
+	if unlocked {
+		author = ""
+	}
+	view.Messages, err = h.Repo.ListCapsuleMessages(r.Context(), inviteID, author)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	view.MessageCount = len(view.Messages)
+	if !unlocked {
+		if view.MessageCount, err = h.Repo.CountCapsuleMessages(r.Context(), inviteID); err != nil {
+			return api.ErrInternal(err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(view)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCapsuleMessage(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name: "Sealed until unlock",
+			body: map[string]interface{}{"content": "  See you next year!  "},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectExec(`INSERT INTO "EventFeedItem" (.+) SELECT (.+) WHERE i.id = \$2 AND NOT i."isVaultUnlocked"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "See you next year!", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "Vault already unlocked",
+			body: map[string]interface{}{"content": "Too late"},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectExec(`INSERT INTO "EventFeedItem"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "Too late", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Empty message",
+			body: map[string]interface{}{"content": "   "},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/invites/invite-1/capsule", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.CreateCapsuleMessage).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetCapsule(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	now := time.Now()
+	columns := []string{"id", "inviteId", "userId", "content", "type", "createdAt", "user.id", "user.name"}
+
+	tests := []struct {
+		name             string
+		userID           string
+		mockBehavior     func()
+		expectedSealed   bool
+		expectedCount    int
+		expectedMessages int
+	}{
+		{
+			// The host gets no special treatment while the capsule is sealed
+			name:   "Sealed shows only own messages",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				mock.ExpectQuery(`SELECT "isVaultUnlocked" FROM "Invite"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"isVaultUnlocked"}).AddRow(false))
+				mock.ExpectQuery(`SELECT f\.\*, (.+) WHERE f."inviteId" = \$1 AND f.type = 'CAPSULE'`).
+					WithArgs("invite-1", "user-host").
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "EventFeedItem"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+			expectedSealed:   true,
+			expectedCount:    3,
+			expectedMessages: 0,
+		},
+		{
+			name:   "Unlocked shows everyone's",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT "isVaultUnlocked" FROM "Invite"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"isVaultUnlocked"}).AddRow(true))
+				mock.ExpectQuery(`SELECT f\.\*, (.+) WHERE f."inviteId" = \$1 AND f.type = 'CAPSULE'`).
+					WithArgs("invite-1", "").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("feed-1", "invite-1", "user-1", "Hi future us", "CAPSULE", now, "user-1", "Ann").
+						AddRow("feed-2", "invite-1", "user-2", "Same time next year", "CAPSULE", now, "user-2", "Bob"))
+			},
+			expectedSealed:   false,
+			expectedCount:    2,
+			expectedMessages: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/invites/invite-1/capsule", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.GetCapsule).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			var view models.CapsuleView
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &view))
+			assert.Equal(t, tt.expectedSealed, view.Sealed)
+			assert.Equal(t, tt.expectedCount, view.MessageCount)
+			assert.Len(t, view.Messages, tt.expectedMessages)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
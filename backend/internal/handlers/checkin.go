@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/tokens"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	checkInTokenPurpose = "check-in"
+	ticketKindMember    = "rsvp"
+	ticketKindGuest     = "guest"
+	// Tickets keep scanning for this long after the event ends
+	checkInTicketGrace = 24 * time.Hour
+)
+
+type CheckInHandler struct {
+	Repo   repository.CheckInRepository
+	Access *access.Policy
+	Signer *tokens.Signer
+}
+
+func NewCheckInHandler(repo repository.CheckInRepository, policy *access.Policy, signer *tokens.Signer) *CheckInHandler {
+	return &CheckInHandler{Repo: repo, Access: policy, Signer: signer}
+}
+
+// RegisterRoutes mounts tickets and check-in under /api/invites
+func (h *CheckInHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/ticket", api.Handler(h.GetTicket))
+	r.Method("POST", "/{id}/check-in", api.Handler(h.CheckIn))
+	r.Method("GET", "/{id}/check-ins", api.Handler(h.ListCheckIns))
+}
+
+// GetTicket returns the current user's QR ticket for an invite they said YES to.
+func (h *CheckInHandler) GetTicket(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	rsvp, err := h.Repo.GetUserRSVP(r.Context(), inviteID, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return api.ErrInternal(err)
+	}
+	if rsvp == nil || rsvp.Status != "YES" {
+		return api.ErrForbidden("RSVP yes to get a ticket")
+	}
+
+	eventEnd, err := h.Repo.GetEventEnd(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	ticket, err := newCheckInTicket(h.Signer, inviteID, ticketKindMember, rsvp.ID, eventEnd)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(ticket)
+}
+
+// CheckIn validates a scanned ticket, or records a walk-in, for the host.
+// Only RSVPs that are still a yes can be checked in. Scanning the same ticket
+// twice is not an error; the response says so and returns the first check-in.
+func (h *CheckInHandler) CheckIn(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can check people in"); err != nil {
+		return err
+	}
+
+	var req models.CheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+
+	checkIn := &models.CheckIn{
+		ID:            utils.GenerateID("checkin"),
+		InviteID:      inviteID,
+		CheckedInByID: userID,
+		CheckedInAt:   time.Now(),
+	}
+
+	switch {
+	case req.Token != "":
+		holder, err := h.ticketHolder(r, inviteID, req.Token)
+		if err != nil {
+			return err
+		}
+		checkIn.UserID = holder.UserID
+		checkIn.GuestRSVPID = holder.GuestRSVPID
+		checkIn.Name = holder.Name
+		checkIn.GuestCount = holder.GuestCount
+
+	case req.WalkIn != nil:
+		name := strings.TrimSpace(req.WalkIn.Name)
+		if name == "" && req.WalkIn.UserID == nil {
+			return api.ErrBadRequest("Walk-ins need a name")
+		}
+		if req.WalkIn.UserID != nil {
+			// Members without an RSVP still need access to the invite
+			if _, err := h.Access.RequireView(r.Context(), inviteID, *req.WalkIn.UserID); err != nil {
+				return err
+			}
+			checkIn.UserID = req.WalkIn.UserID
+		}
+		if name != "" {
+			checkIn.Name = &name
+		}
+		checkIn.GuestCount = req.WalkIn.GuestCount
+		if checkIn.GuestCount > maxGuestPartySize {
+			return api.ErrBadRequest("Guest count is too large")
+		}
+
+	default:
+		return api.ErrBadRequest("A ticket token or walk-in is required")
+	}
+
+	if checkIn.GuestCount < 1 {
+		checkIn.GuestCount = 1
+	}
+
+	created, err := h.Repo.CreateCheckIn(r.Context(), checkIn)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	counts, err := h.Repo.GetAttendanceCounts(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(models.CheckInResponse{
+		CheckIn:          *checkIn,
+		AlreadyCheckedIn: !created,
+		Attendance:       *counts,
+	})
+}
+
+func (h *CheckInHandler) ListCheckIns(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can see check-ins"); err != nil {
+		return err
+	}
+
+	counts, err := h.Repo.GetAttendanceCounts(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	checkIns, err := h.Repo.ListCheckIns(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(models.CheckInList{Attendance: *counts, CheckIns: checkIns})
+}
+
+// ticketHolder verifies a token and makes sure it belongs to this invite.
+func (h *CheckInHandler) ticketHolder(r *http.Request, inviteID, token string) (*models.TicketHolder, error) {
+	payload, ok := h.Signer.VerifyAt(checkInTokenPurpose, token, time.Now())
+	if !ok {
+		return nil, api.ErrBadRequest("Invalid ticket")
+	}
+
+	parts := strings.SplitN(payload, ":", 3)
+	if len(parts) != 3 || (parts[1] != ticketKindMember && parts[1] != ticketKindGuest) {
+		return nil, api.ErrBadRequest("Invalid ticket")
+	}
+	if parts[0] != inviteID {
+		return nil, api.ErrBadRequest("This ticket is for a different event")
+	}
+
+	holder, err := h.Repo.GetTicketHolder(r.Context(), inviteID, parts[2], parts[1] == ticketKindGuest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound("RSVP for this ticket no longer exists")
+	}
+	if err != nil {
+		return nil, api.ErrInternal(err)
+	}
+	if holder.Status != "YES" {
+		return nil, api.ErrForbidden("This RSVP is no longer a yes")
+	}
+	return holder, nil
+}
+
+// newCheckInTicket signs "<inviteId>:<kind>:<rsvpId>" and renders it as a QR
+// code. The token expires checkInTicketGrace after eventEnd.
+func newCheckInTicket(signer *tokens.Signer, inviteID, kind, rsvpID string, eventEnd time.Time) (*models.CheckInTicket, error) {
+	token := signer.SignUntil(checkInTokenPurpose, inviteID+":"+kind+":"+rsvpID, eventEnd.Add(checkInTicketGrace))
+
+	png, err := qrcode.Encode(token, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CheckInTicket{
+		Token:  token,
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
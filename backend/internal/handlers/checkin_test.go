@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/tokens"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIn(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	signer := tokens.NewSigner("test-secret")
+	handler := NewCheckInHandler(repository.NewCheckInRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), signer)
+
+	tonight := time.Now().Add(3 * time.Hour)
+	ticket, err := newCheckInTicket(signer, "invite-1", ticketKindMember, "rsvp-1", tonight)
+	if err != nil {
+		t.Fatalf("failed to issue ticket: %s", err)
+	}
+	otherTicket, _ := newCheckInTicket(signer, "invite-2", ticketKindMember, "rsvp-2", tonight)
+	expiredTicket, _ := newCheckInTicket(signer, "invite-1", ticketKindMember, "rsvp-1", time.Now().Add(-checkInTicketGrace-time.Hour))
+	firstScan := time.Date(2026, 5, 1, 19, 30, 0, 0, time.UTC)
+
+	expectCounts := func() {
+		mock.ExpectQuery(`SELECT .* AS expected`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"expected", "checkedIn", "walkIns"}).AddRow(10, 3, 1))
+	}
+	expectHolder := func(status string) {
+		mock.ExpectQuery(`FROM "RSVP" r`).
+			WithArgs("rsvp-1", "invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"userId", "guestRsvpId", "name", "status", "guestCount"}).
+				AddRow("user-123", nil, "Ann", status, 2))
+	}
+
+	tests := []struct {
+		name             string
+		userID           string
+		body             map[string]interface{}
+		mockBehavior     func()
+		expectedStatus   int
+		alreadyCheckedIn bool
+		checkInID        string
+	}{
+		{
+			name:   "Valid ticket",
+			userID: "user-host",
+			body:   map[string]interface{}{"token": ticket.Token},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				expectHolder("YES")
+				mock.ExpectExec(`INSERT INTO "CheckIn"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", nil, "Ann", 2, "user-host", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectCounts()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Ticket scanned twice",
+			userID: "user-host",
+			body:   map[string]interface{}{"token": ticket.Token},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				expectHolder("YES")
+				mock.ExpectExec(`INSERT INTO "CheckIn"`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT \* FROM "CheckIn" WHERE "inviteId" = \$1 AND`).
+					WithArgs("invite-1", "user-123", nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "userId", "guestRsvpId", "name", "guestCount", "checkedInById", "checkedInAt"}).
+						AddRow("checkin-first", "invite-1", "user-123", nil, "Ann", 2, "user-host", firstScan))
+				expectCounts()
+			},
+			expectedStatus:   http.StatusOK,
+			alreadyCheckedIn: true,
+			checkInID:        "checkin-first",
+		},
+		{
+			name:   "RSVP changed to no",
+			userID: "user-host",
+			body:   map[string]interface{}{"token": ticket.Token},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				expectHolder("NO")
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Expired ticket",
+			userID: "user-host",
+			body:   map[string]interface{}{"token": expiredTicket.Token},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Ticket for another event",
+			userID: "user-host",
+			body:   map[string]interface{}{"token": otherTicket.Token},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Tampered ticket",
+			userID: "user-host",
+			body:   map[string]interface{}{"token": ticket.Token + "x"},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Walk-in guest",
+			userID: "user-host",
+			body:   map[string]interface{}{"walkIn": map[string]interface{}{"name": "Neighbour", "guestCount": 2}},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				mock.ExpectExec(`INSERT INTO "CheckIn"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", nil, nil, "Neighbour", 2, "user-host", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectCounts()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Not the host",
+			userID: "user-123",
+			body:   map[string]interface{}{"token": ticket.Token},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/invites/invite-1/check-in", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.CheckIn).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if rr.Code == http.StatusOK {
+				var resp models.CheckInResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.alreadyCheckedIn, resp.AlreadyCheckedIn)
+				assert.Equal(t, 3, resp.Attendance.CheckedIn)
+				if tt.checkInID != "" {
+					assert.Equal(t, tt.checkInID, resp.CheckIn.ID)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetTicket(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewCheckInHandler(repository.NewCheckInRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), tokens.NewSigner("test-secret"))
+
+	expectRSVP := func(status string) {
+		mock.ExpectQuery(`SELECT \* FROM "RSVP" WHERE "inviteId" = \$1 AND "userId" = \$2`).
+			WithArgs("invite-1", "user-123").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "userId", "status", "guestCount"}).
+				AddRow("rsvp-1", "invite-1", "user-123", status, 1))
+	}
+
+	tests := []struct {
+		name           string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name: "Yes RSVP",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectRSVP("YES")
+				mock.ExpectQuery(`SELECT COALESCE\("endDate", "eventDate"\) FROM "Invite"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(time.Now().Add(3 * time.Hour)))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			// Check-in only accepts yes RSVPs, so a maybe would hold a ticket
+			// that always fails at the door
+			name: "Maybe RSVP",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectRSVP("MAYBE")
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/invites/invite-1/ticket", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, "user-123")
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.GetTicket).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
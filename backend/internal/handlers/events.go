@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/events"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Proxies close idle connections; a comment every so often keeps streams open
+const defaultEventsHeartbeat = 25 * time.Second
+
+type EventsHandler struct {
+	Hub       *events.Hub
+	Access    *access.Policy
+	Heartbeat time.Duration
+}
+
+func NewEventsHandler(hub *events.Hub, policy *access.Policy) *EventsHandler {
+	return &EventsHandler{Hub: hub, Access: policy, Heartbeat: defaultEventsHeartbeat}
+}
+
+// RegisterRoutes mounts the event stream under /api/invites
+func (h *EventsHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/events", api.Handler(h.Stream))
+}
+
+// Stream pushes the invite's feed posts and their edits, hides and removals,
+// RSVP changes, media uploads and vault unlock as Server-Sent Events. Events
+// carry IDs only; clients fetch what changed. Anyone who can see the invite
+// details can listen.
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return api.ErrInternal(errors.New("streaming not supported"))
+	}
+
+	stream, stop := h.Hub.Subscribe(inviteID)
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Don't let nginx buffer the stream
+	w.WriteHeader(http.StatusOK)
+
+	// Reconnecting clients may have missed events, so they refetch on open
+	if _, err := fmt.Fprint(w, "retry: 5000\n\n"); err != nil {
+		return nil
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case e := <-stream:
+			// Access can be lost mid-stream, e.g. when removed from the circle.
+			// The response has started, so the stream simply ends.
+			if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+				return nil
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				return nil
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return nil
+			}
+		}
+		flusher.Flush()
+	}
+}
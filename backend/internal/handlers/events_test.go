@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/events"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEventsServer(handler *EventsHandler, userID string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "invite-1")
+		ctx := context.WithValue(r.Context(), auth.UserIDKey, userID)
+		api.Handler(handler.Stream).ServeHTTP(w, r.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx)))
+	}))
+}
+
+// readEvent returns the next message of the stream, skipping blank lines
+func readEvent(t *testing.T, stream *bufio.Reader) string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %s", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if len(lines) > 0 {
+				return strings.Join(lines, "\n")
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestEventsStream(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	hub := events.NewHub()
+	handler := NewEventsHandler(hub, access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+	handler.Heartbeat = 50 * time.Millisecond
+
+	server := newEventsServer(handler, "user-123")
+	defer server.Close()
+
+	expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("opening stream: %s", err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Subscribed once the first message is out
+	stream := bufio.NewReader(resp.Body)
+	assert.Equal(t, "retry: 5000", readEvent(t, stream))
+
+	expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+	hub.Publish(events.Event{Type: events.TypeRSVP, InviteID: "invite-other", ID: "rsvp-9"})
+	hub.Publish(events.Event{Type: events.TypeFeedPost, InviteID: "invite-1", ID: "feed-1"})
+	msg := readEvent(t, stream)
+	for msg == ": ping" {
+		msg = readEvent(t, stream)
+	}
+	assert.Equal(t, "event: feed.post\ndata: {\"type\":\"feed.post\",\"inviteId\":\"invite-1\",\"id\":\"feed-1\"}", msg)
+
+	// Removed from the circle: the next event ends the stream instead
+	expectInviteAccess(mock, "invite-1", "user-123", "user-host", false)
+	hub.Publish(events.Event{Type: events.TypeMedia, InviteID: "invite-1", ID: "media-1"})
+	rest, _ := io.ReadAll(resp.Body)
+	assert.NotContains(t, string(rest), "media-1")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestEventsStreamRequiresAccess(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewEventsHandler(events.NewHub(), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	server := newEventsServer(handler, "user-999")
+	defer server.Close()
+
+	expectInviteAccess(mock, "invite-1", "user-999", "user-host", false)
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("opening stream: %s", err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/ledger"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ExpensesHandler struct {
+	Repo   repository.ExpenseRepository
+	Access *access.Policy
+}
+
+func NewExpensesHandler(repo repository.ExpenseRepository, policy *access.Policy) *ExpensesHandler {
+	return &ExpensesHandler{Repo: repo, Access: policy}
+}
+
+// RegisterRoutes mounts the expense ledger under /api/invites
+func (h *ExpensesHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/expenses", api.Handler(h.GetLedger))
+	r.Method("POST", "/{id}/expenses", api.Handler(h.CreateExpense))
+	r.Method("DELETE", "/{id}/expenses/{expenseId}", api.Handler(h.DeleteExpense))
+	r.Method("GET", "/{id}/expenses/export", api.Handler(h.ExportCSV))
+	r.Method("POST", "/{id}/settlements", api.Handler(h.CreateSettlement))
+	r.Method("DELETE", "/{id}/settlements/{settlementId}", api.Handler(h.DeleteSettlement))
+}
+
+// GetLedger returns expenses, settlements, per-user balances and the
+// transfers that would settle everyone up.
+func (h *ExpensesHandler) GetLedger(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	result, err := h.loadLedger(r, inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(result)
+}
+
+func (h *ExpensesHandler) CreateExpense(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	var req models.CreateExpenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Description == "" || req.AmountCents <= 0 {
+		return api.ErrBadRequest("Description and a positive amount are required")
+	}
+	if req.AmountCents > ledger.MaxAmountCents {
+		return api.ErrBadRequest("Amount is too large")
+	}
+	if req.SplitType == "" {
+		req.SplitType = ledger.SplitEven
+	}
+
+	attendees, err := h.attendeeSet(r, inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if !attendees[userID] {
+		return api.ErrForbidden("Only attendees can record expenses")
+	}
+
+	paidByID := userID
+	if req.PaidByID != nil {
+		paidByID = *req.PaidByID
+	}
+	if !attendees[paidByID] {
+		return api.ErrBadRequest("The payer must be an attendee")
+	}
+
+	// An even split without participants covers everyone attending
+	if len(req.Splits) == 0 && req.SplitType == ledger.SplitEven {
+		for id := range attendees {
+			req.Splits = append(req.Splits, models.ExpenseSplitRequest{UserID: id})
+		}
+		sort.Slice(req.Splits, func(i, j int) bool { return req.Splits[i].UserID < req.Splits[j].UserID })
+	}
+
+	parts := make([]ledger.Part, len(req.Splits))
+	seen := make(map[string]bool, len(req.Splits))
+	for i, split := range req.Splits {
+		if !attendees[split.UserID] {
+			return api.ErrBadRequest("Expenses can only be split among attendees")
+		}
+		if seen[split.UserID] {
+			return api.ErrBadRequest("Each participant can only appear once")
+		}
+		seen[split.UserID] = true
+		parts[i] = ledger.Part{UserID: split.UserID, Shares: split.Shares, AmountCents: split.AmountCents}
+	}
+
+	amounts, err := ledger.Split(req.AmountCents, req.SplitType, parts)
+	if err != nil {
+		return api.NewAPIError(http.StatusBadRequest, "Invalid split: "+err.Error(), err)
+	}
+
+	expense := &models.Expense{
+		ID:          utils.GenerateID("expense"),
+		InviteID:    inviteID,
+		PaidByID:    paidByID,
+		Description: req.Description,
+		AmountCents: req.AmountCents,
+		SplitType:   req.SplitType,
+		CreatedByID: userID,
+		CreatedAt:   time.Now(),
+	}
+
+	shares := make([]models.ExpenseShare, len(parts))
+	for i, part := range parts {
+		shares[i] = models.ExpenseShare{
+			ID:          utils.GenerateID("expshare"),
+			ExpenseID:   expense.ID,
+			UserID:      part.UserID,
+			AmountCents: amounts[i],
+		}
+		if req.SplitType == ledger.SplitShares {
+			n := part.Shares
+			shares[i].Shares = &n
+		}
+	}
+
+	if err := h.Repo.CreateExpense(r.Context(), expense, shares); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(models.ExpenseWithShares{Expense: *expense, Shares: shares})
+}
+
+// DeleteExpense can be done by whoever recorded or paid the expense, or the host.
+func (h *ExpensesHandler) DeleteExpense(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	acc, err := h.Access.RequirePost(r.Context(), inviteID, userID)
+	if err != nil {
+		return err
+	}
+
+	expense, err := h.Repo.GetExpense(r.Context(), inviteID, chi.URLParam(r, "expenseId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Expense not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if expense.CreatedByID != userID && expense.PaidByID != userID && !acc.IsHost() {
+		return api.ErrForbidden("You can only delete your own expenses")
+	}
+
+	if err := h.Repo.DeleteExpense(r.Context(), inviteID, expense.ID); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *ExpensesHandler) CreateSettlement(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	acc, err := h.Access.RequirePost(r.Context(), inviteID, userID)
+	if err != nil {
+		return err
+	}
+
+	var req models.CreateSettlementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+
+	fromUserID := userID
+	if req.FromUserID != nil {
+		fromUserID = *req.FromUserID
+	}
+	if req.AmountCents <= 0 || req.ToUserID == "" || req.ToUserID == fromUserID {
+		return api.ErrBadRequest("A recipient and a positive amount are required")
+	}
+	if req.AmountCents > ledger.MaxAmountCents {
+		return api.ErrBadRequest("Amount is too large")
+	}
+	// Either side of the payment (or the host) can record it
+	if fromUserID != userID && req.ToUserID != userID && !acc.IsHost() {
+		return api.ErrForbidden("You can only record payments you made or received")
+	}
+
+	attendees, err := h.attendeeSet(r, inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if !attendees[fromUserID] || !attendees[req.ToUserID] {
+		return api.ErrBadRequest("Settlements are only between attendees")
+	}
+
+	settlement := &models.Settlement{
+		ID:          utils.GenerateID("settlement"),
+		InviteID:    inviteID,
+		FromUserID:  fromUserID,
+		ToUserID:    req.ToUserID,
+		AmountCents: req.AmountCents,
+		CreatedByID: userID,
+		CreatedAt:   time.Now(),
+	}
+	if err := h.Repo.CreateSettlement(r.Context(), settlement); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(settlement)
+}
+
+func (h *ExpensesHandler) DeleteSettlement(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	acc, err := h.Access.RequirePost(r.Context(), inviteID, userID)
+	if err != nil {
+		return err
+	}
+
+	settlement, err := h.Repo.GetSettlement(r.Context(), inviteID, chi.URLParam(r, "settlementId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Settlement not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if settlement.CreatedByID != userID && !acc.IsHost() {
+		return api.ErrForbidden("You can only delete settlements you recorded")
+	}
+
+	if err := h.Repo.DeleteSettlement(r.Context(), inviteID, settlement.ID); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// ExportCSV lets the host download every expense and settlement as a spreadsheet.
+func (h *ExpensesHandler) ExportCSV(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can export expenses"); err != nil {
+		return err
+	}
+
+	result, err := h.loadLedger(r, inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	names := make(map[string]string, len(result.Attendees))
+	for _, u := range result.Attendees {
+		names[u.ID] = displayName(u)
+	}
+	name := func(id string) string {
+		if n, ok := names[id]; ok {
+			return csvSafe(n)
+		}
+		return id
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="expenses-%s.csv"`, inviteID))
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Date", "Type", "Description", "From", "To", "Amount", "Split"})
+	for _, e := range result.Expenses {
+		split := make([]string, len(e.Shares))
+		for i, s := range e.Shares {
+			split[i] = fmt.Sprintf("%s %s", name(s.UserID), formatCents(s.AmountCents))
+		}
+		cw.Write([]string{
+			e.CreatedAt.Format("2006-01-02"), "Expense", csvSafe(e.Description),
+			name(e.PaidByID), "", formatCents(e.AmountCents), strings.Join(split, "; "),
+		})
+	}
+	for _, s := range result.Settlements {
+		cw.Write([]string{
+			s.CreatedAt.Format("2006-01-02"), "Settlement", "",
+			name(s.FromUserID), name(s.ToUserID), formatCents(s.AmountCents), "",
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (h *ExpensesHandler) loadLedger(r *http.Request, inviteID string) (*models.ExpenseLedger, error) {
+	attendees, err := h.Repo.ListAttendees(r.Context(), inviteID)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := h.Repo.ListExpenses(r.Context(), inviteID)
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := h.Repo.ListSettlements(r.Context(), inviteID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := ledger.Balances(expenses, settlements)
+	result := &models.ExpenseLedger{
+		Attendees:   attendees,
+		Expenses:    expenses,
+		Settlements: settlements,
+		Balances:    make([]models.ExpenseBalance, 0, len(balances)),
+		Transfers:   ledger.Settle(balances),
+	}
+	for id, cents := range balances {
+		result.Balances = append(result.Balances, models.ExpenseBalance{UserID: id, BalanceCents: cents})
+	}
+	sort.Slice(result.Balances, func(i, j int) bool { return result.Balances[i].UserID < result.Balances[j].UserID })
+	return result, nil
+}
+
+func (h *ExpensesHandler) attendeeSet(r *http.Request, inviteID string) (map[string]bool, error) {
+	attendees, err := h.Repo.ListAttendees(r.Context(), inviteID)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(attendees))
+	for _, u := range attendees {
+		set[u.ID] = true
+	}
+	return set, nil
+}
+
+func displayName(u models.User) string {
+	if u.Name != nil && *u.Name != "" {
+		return *u.Name
+	}
+	if u.Email != nil {
+		return *u.Email
+	}
+	return u.ID
+}
+
+// csvSafe stops spreadsheets from running user-entered text as a formula by
+// prefixing a quote to cells that start with a formula character.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
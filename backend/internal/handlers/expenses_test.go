@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/ledger"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateExpense(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewExpensesHandler(repository.NewExpenseRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	expectAttendees := func() {
+		mock.ExpectQuery(`SELECT u\.\* FROM "User" u`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow("user-host", "Host").
+				AddRow("user-123", "Ann"))
+	}
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name: "Even split among all attendees",
+			body: map[string]interface{}{"description": "Groceries", "amountCents": 1001},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectAttendees()
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "Expense"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "Groceries", int64(1001), "EVEN", "user-123", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "ExpenseShare"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", nil, int64(501)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "ExpenseShare"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "user-host", nil, int64(500)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "Exact amounts that do not add up",
+			body: map[string]interface{}{
+				"description": "Gas",
+				"amountCents": 4000,
+				"splitType":   "EXACT",
+				"splits":      []map[string]interface{}{{"userId": "user-123", "amountCents": 1000}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectAttendees()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Participant is not attending",
+			body: map[string]interface{}{
+				"description": "Gas",
+				"amountCents": 4000,
+				"splits":      []map[string]interface{}{{"userId": "user-456"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectAttendees()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Amount above the limit",
+			body: map[string]interface{}{"description": "Yacht", "amountCents": ledger.MaxAmountCents + 1},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Shares above the limit",
+			body: map[string]interface{}{
+				"description": "Gas",
+				"amountCents": ledger.MaxAmountCents,
+				"splitType":   "SHARES",
+				"splits":      []map[string]interface{}{{"userId": "user-123", "shares": ledger.MaxShares + 1}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectAttendees()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Missing amount",
+			body: map[string]interface{}{"description": "Gas"},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/invites/invite-1/expenses", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, "user-123")
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.CreateExpense).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCSVSafe(t *testing.T) {
+	assert.Equal(t, "'=HYPERLINK(\"http://evil.test\")", csvSafe(`=HYPERLINK("http://evil.test")`))
+	assert.Equal(t, "'+1", csvSafe("+1"))
+	assert.Equal(t, "'-2", csvSafe("-2"))
+	assert.Equal(t, "'@SUM(A1)", csvSafe("@SUM(A1)"))
+	assert.Equal(t, "'\tcmd", csvSafe("\tcmd"))
+	assert.Equal(t, "'\rcmd", csvSafe("\rcmd"))
+	assert.Equal(t, "Pizza = 20", csvSafe("Pizza = 20"))
+	assert.Equal(t, "", csvSafe(""))
+}
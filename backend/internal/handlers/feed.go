@@ -1,12 +1,21 @@
 package handlers
 
 import (
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
+	"privo-club-backend/internal/access"
 	"privo-club-backend/internal/api"
 	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/mention"
 	"privo-club-backend/internal/models"
 	"privo-club-backend/internal/repository"
 	"privo-club-backend/internal/utils"
@@ -14,59 +23,601 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+	maxPostLength    = 2000
+	maxEmojiRunes    = 10 // Long enough for ZWJ sequences like 👨‍👩‍👧‍👦
+	maxReasonLength  = 500
+)
+
 type FeedHandler struct {
-	Repo repository.FeedRepository
+	Repo   repository.FeedRepository
+	Access *access.Policy
 }
 
-func NewFeedHandler(repo repository.FeedRepository) *FeedHandler {
-	return &FeedHandler{Repo: repo}
+func NewFeedHandler(repo repository.FeedRepository, policy *access.Policy) *FeedHandler {
+	return &FeedHandler{Repo: repo, Access: policy}
 }
 
+// RegisterRoutes mounts the feed under /api/invites
 func (h *FeedHandler) RegisterRoutes(r chi.Router) {
-	r.Post("/", h.CreatePost)
-	r.Method("GET", "/", api.Handler(h.GetFeed))
+	r.Method("GET", "/{id}/feed", api.Handler(h.GetFeed))
+	r.Method("POST", "/{id}/feed", api.Handler(h.CreatePost))
+	r.Method("PATCH", "/{id}/feed/{postId}", api.Handler(h.EditPost))
+	r.Method("DELETE", "/{id}/feed/{postId}", api.Handler(h.DeletePost))
+	r.Method("GET", "/{id}/feed/{postId}/revisions", api.Handler(h.ListRevisions))
+	r.Method("POST", "/{id}/feed/{postId}/moderation", api.Handler(h.ModeratePost))
+	r.Method("GET", "/{id}/feed/{postId}/replies", api.Handler(h.ListReplies))
+	r.Method("POST", "/{id}/feed/{postId}/reactions", api.Handler(h.AddReaction))
+	r.Method("DELETE", "/{id}/feed/{postId}/reactions", api.Handler(h.RemoveReaction))
+}
+
+// RegisterMentionRoutes mounts the current user's mentions under /api/users
+func (h *FeedHandler) RegisterMentionRoutes(r chi.Router) {
+	r.Method("GET", "/me/mentions", api.Handler(h.ListMentions))
 }
 
-func (h *FeedHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
+func (h *FeedHandler) CreatePost(w http.ResponseWriter, r *http.Request) error {
 	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	a, err := h.Access.RequirePost(r.Context(), inviteID, userID)
+	if err != nil {
+		return err
 	}
 
 	var req models.CreatePostRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+		return api.ErrBadRequest("Invalid request body")
+	}
+
+	switch req.Type {
+	case "":
+		req.Type = models.FeedTypeChat
+	case models.FeedTypeChat:
+	case models.FeedTypeUpdate:
+		if req.ParentID != nil {
+			return api.ErrBadRequest("Replies cannot be updates")
+		}
+		if !a.IsHost() {
+			return api.ErrForbidden("Only the host can post updates")
+		}
+	case models.FeedTypeCapsule:
+		return api.ErrBadRequest("Time-capsule messages are posted to the invite's capsule")
+	default:
+		return api.ErrBadRequest("Type must be UPDATE or CHAT")
+	}
+
+	content, err := postContent(req.Content)
+	if err != nil {
+		return err
+	}
+
+	if req.ParentID != nil {
+		parent, err := h.Repo.GetPost(r.Context(), inviteID, *req.ParentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.ErrNotFound("Post not found")
+		}
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+		if parent.ParentID != nil {
+			return api.ErrBadRequest("Replies cannot be replied to; reply to the original post")
+		}
+		if parent.HiddenAt != nil || parent.RemovedAt != nil {
+			return api.ErrBadRequest("Hidden and removed posts cannot be replied to")
+		}
 	}
 
-	id := utils.GenerateID("feed")
 	item := &models.EventFeedItem{
-		ID:        id,
-		InviteID:  req.InviteID,
+		ID:        utils.GenerateID("feed"),
+		InviteID:  inviteID,
 		UserID:    userID,
-		Content:   req.Content,
+		Content:   content,
 		Type:      req.Type,
+		ParentID:  req.ParentID,
 		CreatedAt: time.Now(),
 	}
-
-	if err := h.Repo.CreatePost(r.Context(), item); err != nil {
-		http.Error(w, "Failed to post update: "+err.Error(), http.StatusInternalServerError)
-		return
+	mentions, err := h.findMentions(r, item, item.CreatedAt)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if err := h.Repo.CreatePost(r.Context(), item, mentions); err != nil {
+		return api.ErrInternal(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"id": id})
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(item)
 }
 
+// GetFeed returns one page of the invite's feed, newest first. It takes
+// type=UPDATE|CHAT, limit and cursor.
 func (h *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) error {
-	inviteID := chi.URLParam(r, "inviteId")
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	a, err := h.Access.RequireView(r.Context(), inviteID, userID)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFeedListFilter(r)
+	if err != nil {
+		return err
+	}
+	limit := filter.Limit
+	filter.Limit++ // One extra row tells us whether there is a next page
+
+	items, err := h.Repo.ListFeed(r.Context(), inviteID, userID, filter)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	for i := range items {
+		items[i].Redact(userID, a.CanModerate())
+	}
+
+	page := models.FeedPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		cursor := encodeFeedCursor(models.FeedCursor{PostedAt: last.PostedAt, ID: last.ID})
+		page.NextCursor = &cursor
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(page)
+}
+
+// EditPost lets authors change the text of their post. The previous text is
+// kept as a revision and the post is marked as edited.
+func (h *FeedHandler) EditPost(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	var req models.EditPostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	content, err := postContent(req.Content)
+	if err != nil {
+		return err
+	}
+
+	post, err := h.getPost(r, inviteID)
+	if err != nil {
+		return err
+	}
+	if post.RemovedAt != nil {
+		return api.ErrNotFound("Post not found")
+	}
+	if post.UserID != userID {
+		return api.ErrForbidden("Only the author can edit this post")
+	}
+	if post.Type == models.FeedTypeCapsule {
+		return api.ErrBadRequest("Time-capsule messages cannot be edited")
+	}
+
+	if content != post.Content {
+		now := time.Now()
+		post.Content = content
+		post.EditedAt = &now
+		mentions, err := h.findMentions(r, post, now)
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+		err = h.Repo.EditPost(r.Context(), post, mentions)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Removed in the meantime
+			return api.ErrNotFound("Post not found")
+		}
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(post)
+}
+
+// DeletePost lets authors take back their post. It stays as a tombstone so
+// its replies keep their place.
+func (h *FeedHandler) DeletePost(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+	post, err := h.getPost(r, inviteID)
+	if err != nil {
+		return err
+	}
+	if post.UserID != userID {
+		return api.ErrForbidden("Only the author can delete this post")
+	}
+
+	err = h.Repo.RemovePost(r.Context(), post.ID, userID, nil, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Post not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// ListRevisions returns the earlier texts of an edited post, oldest first
+func (h *FeedHandler) ListRevisions(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	a, err := h.Access.RequireView(r.Context(), inviteID, userID)
+	if err != nil {
+		return err
+	}
+	post, err := h.getPost(r, inviteID)
+	if err != nil {
+		return err
+	}
+	if post.HiddenAt != nil && post.UserID != userID && !a.CanModerate() {
+		return api.ErrNotFound("Post not found")
+	}
+
+	revisions, err := h.Repo.ListRevisions(r.Context(), post.ID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(revisions)
+}
+
+// ModeratePost lets the host and circle admins hide, unhide or remove any
+// post. Hiding and removing take a reason, shown to the author.
+func (h *FeedHandler) ModeratePost(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
 
-	items, err := h.Repo.GetFeed(r.Context(), inviteID)
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireModerator(r.Context(), inviteID, userID, "Only the host and circle admins can moderate posts"); err != nil {
+		return err
+	}
+
+	var req models.ModeratePostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	switch req.Action {
+	case models.ModerationHide, models.ModerationRemove:
+		if reason == "" {
+			return api.ErrBadRequest("A reason is required")
+		}
+		if len([]rune(reason)) > maxReasonLength {
+			return api.ErrBadRequest("Reason is too long")
+		}
+	case models.ModerationUnhide:
+	default:
+		return api.ErrBadRequest("Action must be HIDE, UNHIDE or REMOVE")
+	}
+
+	post, err := h.getPost(r, inviteID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch req.Action {
+	case models.ModerationHide:
+		err = h.Repo.HidePost(r.Context(), post.ID, userID, reason, now)
+	case models.ModerationUnhide:
+		err = h.Repo.UnhidePost(r.Context(), post.ID)
+	case models.ModerationRemove:
+		err = h.Repo.RemovePost(r.Context(), post.ID, userID, &reason, now)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.NewAPIError(http.StatusConflict, "The post is already "+moderatedState(post), nil)
+	}
 	if err != nil {
 		return api.ErrInternal(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(items)
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// ListMentions returns one page of the posts mentioning the current user,
+// newest first. It takes limit and cursor like the feed.
+func (h *FeedHandler) ListMentions(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	filter := models.FeedListFilter{Limit: defaultFeedLimit}
+	if err := parseFeedPage(r, &filter); err != nil {
+		return err
+	}
+	limit := filter.Limit
+	filter.Limit++ // One extra row tells us whether there is a next page
+
+	items, err := h.Repo.ListMentionsOfUser(r.Context(), userID, filter)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	page := models.MentionPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		cursor := encodeFeedCursor(models.FeedCursor{PostedAt: last.PostedAt, ID: last.ID})
+		page.NextCursor = &cursor
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(page)
+}
+
+// ListReplies returns a post's replies, oldest first
+func (h *FeedHandler) ListReplies(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	a, err := h.Access.RequireView(r.Context(), inviteID, userID)
+	if err != nil {
+		return err
+	}
+	post, err := h.getPost(r, inviteID)
+	if err != nil {
+		return err
+	}
+
+	replies, err := h.Repo.ListReplies(r.Context(), post.ID, userID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	for i := range replies {
+		replies[i].Redact(userID, a.CanModerate())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(replies)
+}
+
+// AddReaction reacts to a post and returns the post's updated reactions
+func (h *FeedHandler) AddReaction(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	var req models.ReactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	if !isEmoji(req.Emoji) {
+		return api.ErrBadRequest("Reaction must be an emoji")
+	}
+
+	post, err := h.getPost(r, inviteID)
+	if err != nil {
+		return err
+	}
+	if post.HiddenAt != nil || post.RemovedAt != nil {
+		return api.ErrBadRequest("Hidden and removed posts cannot be reacted to")
+	}
+
+	reaction := &models.FeedReaction{
+		ID:         utils.GenerateID("reaction"),
+		FeedItemID: post.ID,
+		UserID:     userID,
+		Emoji:      req.Emoji,
+		CreatedAt:  time.Now(),
+	}
+	if err := h.Repo.AddReaction(r.Context(), reaction); err != nil {
+		return api.ErrInternal(err)
+	}
+	return h.writeReactions(w, r, post.ID, userID)
+}
+
+// RemoveReaction takes back the ?emoji= reaction and returns the post's
+// updated reactions
+func (h *FeedHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+	post, err := h.getPost(r, inviteID)
+	if err != nil {
+		return err
+	}
+
+	err = h.Repo.RemoveReaction(r.Context(), post.ID, userID, r.URL.Query().Get("emoji"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Reaction not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	return h.writeReactions(w, r, post.ID, userID)
+}
+
+// findMentions resolves the post's @mentions against the people who can see
+// the invite and sets item.Mentions
+func (h *FeedHandler) findMentions(r *http.Request, item *models.EventFeedItem, now time.Time) ([]models.FeedMention, error) {
+	if !strings.Contains(item.Content, "@") {
+		return nil, nil
+	}
+	candidates, err := h.Repo.ListMentionCandidates(r.Context(), item.InviteID)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Mentions = mention.Find(item.Content, candidates)
+	mentions := make([]models.FeedMention, len(item.Mentions))
+	for i, span := range item.Mentions {
+		mentions[i] = models.FeedMention{
+			ID:         utils.GenerateID("mention"),
+			FeedItemID: item.ID,
+			UserID:     span.UserID,
+			Start:      span.Start,
+			Length:     span.Length,
+			CreatedAt:  now,
+		}
+	}
+	return mentions, nil
+}
+
+// postContent trims a post's text and checks its length
+func postContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", api.ErrBadRequest("Content is required")
+	}
+	if len([]rune(content)) > maxPostLength {
+		return "", api.ErrBadRequest("Post is too long")
+	}
+	return content, nil
+}
+
+func moderatedState(post *models.EventFeedItem) string {
+	switch {
+	case post.RemovedAt != nil:
+		return "removed"
+	case post.HiddenAt != nil:
+		return "hidden"
+	default:
+		return "visible"
+	}
+}
+
+// getPost loads the {postId} post, which must belong to the invite and be visible
+func (h *FeedHandler) getPost(r *http.Request, inviteID string) (*models.EventFeedItem, error) {
+	post, err := h.Repo.GetPost(r.Context(), inviteID, chi.URLParam(r, "postId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound("Post not found")
+	}
+	if err != nil {
+		return nil, api.ErrInternal(err)
+	}
+	return post, nil
+}
+
+func (h *FeedHandler) writeReactions(w http.ResponseWriter, r *http.Request, postID, userID string) error {
+	reactions, err := h.Repo.ListReactions(r.Context(), postID, userID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(reactions)
+}
+
+// isEmoji accepts a single emoji, including skin tones and ZWJ sequences
+// such as family or flag emoji. Plain text is rejected.
+func isEmoji(s string) bool {
+	runes := []rune(s)
+	if len(runes) == 0 || len(runes) > maxEmojiRunes {
+		return false
+	}
+	for _, r := range runes {
+		if r < '\u200d' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseFeedListFilter(r *http.Request) (models.FeedListFilter, error) {
+	q := r.URL.Query()
+	filter := models.FeedListFilter{Limit: defaultFeedLimit}
+
+	switch t := strings.ToUpper(q.Get("type")); t {
+	case "", models.FeedTypeUpdate, models.FeedTypeChat:
+		filter.Type = t
+	default:
+		return filter, api.ErrBadRequest("type must be UPDATE or CHAT")
+	}
+
+	return filter, parseFeedPage(r, &filter)
+}
+
+// parseFeedPage reads the limit and cursor of a paginated list of posts
+func parseFeedPage(r *http.Request, filter *models.FeedListFilter) error {
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxFeedLimit {
+			return api.ErrBadRequest(fmt.Sprintf("limit must be between 1 and %d", maxFeedLimit))
+		}
+		filter.Limit = limit
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := decodeFeedCursor(v)
+		if err != nil {
+			return api.ErrBadRequest("Invalid cursor")
+		}
+		filter.After = &cursor
+	}
+	return nil
+}
+
+// Cursors are opaque to clients: base64 of "<postedAt>|<id>"
+func encodeFeedCursor(c models.FeedCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.PostedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+func decodeFeedCursor(s string) (models.FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return models.FeedCursor{}, err
+	}
+	date, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return models.FeedCursor{}, errors.New("malformed cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, date)
+	if err != nil {
+		return models.FeedCursor{}, err
+	}
+	return models.FeedCursor{PostedAt: t, ID: id}, nil
 }
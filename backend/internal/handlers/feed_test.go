@@ -3,13 +3,17 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
 	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
 	"privo-club-backend/internal/repository"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -26,44 +30,222 @@ func TestCreatePost(t *testing.T) {
 	defer mockDB.Close()
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	repo := repository.NewFeedRepository(sqlxDB)
-	handler := NewFeedHandler(repo)
+	handler := NewFeedHandler(repo, access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
 
 	tests := []struct {
-		name           string
-		userID         string
-		body           map[string]interface{}
-		mockBehavior   func()
-		expectedStatus int
+		name             string
+		userID           string
+		body             map[string]interface{}
+		mockBehavior     func()
+		expectedStatus   int
+		expectedMentions []models.MentionSpan
 	}{
 		{
 			name:   "Success",
 			userID: "user-123",
 			body: map[string]interface{}{
-				"inviteId": "invite-1",
-				"content":  "Hello",
-				"type":     "CHAT",
+				"content": "Hello",
+				"type":    "CHAT",
 			},
 			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectBegin()
 				mock.ExpectExec(`INSERT INTO "EventFeedItem"`).
-					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "Hello", "CHAT", sqlmock.AnyArg()).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "Hello", "CHAT", sqlmock.AnyArg(), nil).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Type defaults to chat",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content": "  Running late  ",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "EventFeedItem"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "Running late", "CHAT", sqlmock.AnyArg(), nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Host posts an update",
+			userID: "user-host",
+			body: map[string]interface{}{
+				"content": "Doors open at 7",
+				"type":    "UPDATE",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "EventFeedItem"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-host", "Doors open at 7", "UPDATE", sqlmock.AnyArg(), nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Guests cannot post updates",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content": "Doors open at 7",
+				"type":    "UPDATE",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Unknown type",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content": "Hello",
+				"type":    "ANNOUNCEMENT",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Capsule messages go to the capsule",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content": "Hello future",
+				"type":    "CAPSULE",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Empty content",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content": "   ",
+				"type":    "CHAT",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Mentions",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content": "@Ann Lee and @Bob, can you bring chairs?",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT u.id AS "userId", u.name FROM \(.+ UNION .+\) people JOIN "User" u`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"userId", "name"}).
+						AddRow("user-ann", "Ann Lee").
+						AddRow("user-host", "Hana"))
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "EventFeedItem"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "@Ann Lee and @Bob, can you bring chairs?", "CHAT", sqlmock.AnyArg(), nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				// Bob can't see the invite, so he isn't mentioned
+				mock.ExpectExec(`INSERT INTO "FeedMention"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "user-ann", 0, 8, sqlmock.AnyArg(), nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus:   http.StatusCreated,
+			expectedMentions: []models.MentionSpan{{UserID: "user-ann", Start: 0, Length: 8}},
+		},
+		{
+			name:   "Reply",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content":  "I can bring chairs",
+				"parentId": "feed-1",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f JOIN "Invite" i (.+) WHERE f.id = \$1 AND f."inviteId" = \$2`).
+					WithArgs("feed-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "type", "parentId"}).AddRow("feed-1", "invite-1", "UPDATE", nil))
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "EventFeedItem"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "I can bring chairs", "CHAT", sqlmock.AnyArg(), "feed-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Threads are one level deep",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content":  "Me too",
+				"parentId": "feed-2",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+					WithArgs("feed-2", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "type", "parentId"}).AddRow("feed-2", "invite-1", "CHAT", "feed-1"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Reply to a post of another invite",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"content":  "Hi",
+				"parentId": "feed-9",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+					WithArgs("feed-9", "invite-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Not a circle member",
+			userID: "user-456",
+			body: map[string]interface{}{
+				"content": "Hello",
+				"type":    "CHAT",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-host", false)
+			},
+			expectedStatus: http.StatusNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			body, _ := json.Marshal(tt.body)
-			req, _ := http.NewRequest("POST", "/feed", bytes.NewBuffer(body))
-			if tt.userID != "" {
-				ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
-				req = req.WithContext(ctx)
-			}
+			req, _ := http.NewRequest("POST", "/invites/invite-1/feed", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
 			tt.mockBehavior()
 			rr := httptest.NewRecorder()
-			handler.CreatePost(rr, req)
+			api.Handler(handler.CreatePost).ServeHTTP(rr, req)
 			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if rr.Code == http.StatusCreated {
+				var item models.EventFeedItem
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &item))
+				assert.Equal(t, tt.expectedMentions, item.Mentions)
+			}
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("unfulfilled expectations: %s", err)
 			}
@@ -79,39 +261,679 @@ func TestGetFeed(t *testing.T) {
 	defer mockDB.Close()
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	repo := repository.NewFeedRepository(sqlxDB)
-	handler := NewFeedHandler(repo)
+	handler := NewFeedHandler(repo, access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	now := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	columns := []string{"id", "inviteId", "userId", "content", "type", "createdAt", "postedAt", "replyCount", "user.id", "user.name"}
+	reactionColumns := []string{"feedItemId", "emoji", "count", "reacted"}
+	cursor := encodeFeedCursor(models.FeedCursor{PostedAt: now, ID: "feed-2"})
 
 	tests := []struct {
 		name           string
-		inviteID       string
+		userID         string
+		query          string
 		mockBehavior   func()
 		expectedStatus int
+		expectedItems  int
+		expectedNext   bool
 	}{
 		{
-			name:     "Success",
-			inviteID: "invite-1",
+			name:   "First page",
+			userID: "user-123",
+			query:  "?limit=2",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{"id", "inviteId", "userId", "content", "type", "createdAt"}).
-					AddRow("feed-1", "invite-1", "user-1", "Hi", "CHAT", time.Now())
-				mock.ExpectQuery(`SELECT \* FROM "EventFeedItem"`).
-					WithArgs("invite-1").
-					WillReturnRows(rows)
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\*, (.+) FROM "EventFeedItem" f JOIN "Invite" i (.+) LIMIT \$5`).
+					WithArgs("invite-1", "", nil, "", 3).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("feed-3", "invite-1", "user-1", "Hi", "CHAT", now, now, 2, "user-1", "Ann").
+						AddRow("feed-2", "invite-1", "user-2", "Hey", "CHAT", now, now, 0, "user-2", "Bob").
+						AddRow("feed-1", "invite-1", "user-1", "Yo", "CHAT", now, now, 0, "user-1", "Ann"))
+				// One query for the reactions of the whole page
+				mock.ExpectQuery(`SELECT "feedItemId", emoji, COUNT\(\*\)`).
+					WithArgs(`{"feed-3","feed-2","feed-1"}`, "user-123").
+					WillReturnRows(sqlmock.NewRows(reactionColumns).
+						AddRow("feed-3", "👍", 3, true).
+						AddRow("feed-3", "🎉", 1, false))
+				// And one for their mentions
+				mock.ExpectQuery(`SELECT "feedItemId", "userId", start, length FROM "FeedMention"`).
+					WithArgs(`{"feed-3","feed-2","feed-1"}`).
+					WillReturnRows(sqlmock.NewRows([]string{"feedItemId", "userId", "start", "length"}))
+			},
+			expectedStatus: http.StatusOK,
+			expectedItems:  2,
+			expectedNext:   true,
+		},
+		{
+			name:   "Updates after a cursor",
+			userID: "user-123",
+			query:  "?type=update&cursor=" + cursor,
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\*, (.+) FROM "EventFeedItem" f`).
+					WithArgs("invite-1", "UPDATE", now, "feed-2", defaultFeedLimit+1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("feed-1", "invite-1", "user-host", "Doors at 7", "UPDATE", now, now, 0, "user-host", "Hana"))
+				mock.ExpectQuery(`SELECT "feedItemId", emoji, COUNT\(\*\)`).
+					WithArgs(`{"feed-1"}`, "user-123").
+					WillReturnRows(sqlmock.NewRows(reactionColumns))
+				mock.ExpectQuery(`FROM "FeedMention"`).
+					WithArgs(`{"feed-1"}`).
+					WillReturnRows(sqlmock.NewRows([]string{"feedItemId", "userId", "start", "length"}))
+			},
+			expectedStatus: http.StatusOK,
+			expectedItems:  1,
+		},
+		{
+			name:   "Unknown type",
+			userID: "user-123",
+			query:  "?type=CAPSULE",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Invalid cursor",
+			userID: "user-123",
+			query:  "?cursor=nope",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Outsiders cannot read it",
+			userID: "user-456",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-host", false)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/invites/invite-1/feed"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.GetFeed).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var page models.FeedPage
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+				assert.Len(t, page.Items, tt.expectedItems)
+				assert.Equal(t, tt.expectedNext, page.NextCursor != nil)
+				for _, item := range page.Items {
+					assert.NotNil(t, item.Reactions)
+				}
+				if page.NextCursor != nil {
+					next, err := decodeFeedCursor(*page.NextCursor)
+					assert.NoError(t, err)
+					assert.Equal(t, "feed-2", next.ID)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestListReplies(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	now := time.Now()
+	expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+	mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+		WithArgs("feed-1", "invite-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "type"}).AddRow("feed-1", "invite-1", "UPDATE"))
+	mock.ExpectQuery(`SELECT f\.\*, (.+) WHERE f."parentId" = \$1`).
+		WithArgs("feed-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "userId", "content", "type", "parentId", "createdAt", "user.id", "user.name"}).
+			AddRow("feed-2", "invite-1", "user-1", "@Hana count me in", "CHAT", "feed-1", now, "user-1", "Ann").
+			AddRow("feed-3", "invite-1", "user-2", "Same", "CHAT", "feed-1", now, "user-2", "Bob"))
+	mock.ExpectQuery(`SELECT "feedItemId", emoji, COUNT\(\*\)`).
+		WithArgs(`{"feed-2","feed-3"}`, "user-123").
+		WillReturnRows(sqlmock.NewRows([]string{"feedItemId", "emoji", "count", "reacted"}).AddRow("feed-3", "👍", 1, false))
+	mock.ExpectQuery(`FROM "FeedMention"`).
+		WithArgs(`{"feed-2","feed-3"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"feedItemId", "userId", "start", "length"}).AddRow("feed-2", "user-host", 0, 5))
+
+	req, _ := http.NewRequest("GET", "/invites/invite-1/feed/feed-1/replies", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "invite-1")
+	rctx.URLParams.Add("postId", "feed-1")
+	ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+	rr := httptest.NewRecorder()
+	api.Handler(handler.ListReplies).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var replies []models.FeedWithUser
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &replies))
+	assert.Len(t, replies, 2)
+	assert.Empty(t, replies[0].Reactions)
+	assert.Equal(t, []models.ReactionCount{{Emoji: "👍", Count: 1}}, replies[1].Reactions)
+	assert.Equal(t, []models.MentionSpan{{UserID: "user-host", Start: 0, Length: 5}}, replies[0].Mentions)
+	assert.Empty(t, replies[1].Mentions)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAddReaction(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	tests := []struct {
+		name           string
+		emoji          string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:  "Success",
+			emoji: "👍",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+					WithArgs("feed-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "type"}).AddRow("feed-1", "invite-1", "CHAT"))
+				mock.ExpectExec(`INSERT INTO "FeedReaction" (.+) ON CONFLICT`).
+					WithArgs(sqlmock.AnyArg(), "feed-1", "user-123", "👍", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(`SELECT "feedItemId", emoji, COUNT\(\*\)`).
+					WithArgs(`{"feed-1"}`, "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"feedItemId", "emoji", "count", "reacted"}).AddRow("feed-1", "👍", 4, true))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Not an emoji",
+			emoji: "+1",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Sealed or missing post",
+			emoji: "🎉",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+					WithArgs("feed-1", "invite-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"emoji": tt.emoji})
+			req, _ := http.NewRequest("POST", "/invites/invite-1/feed/feed-1/reactions", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			rctx.URLParams.Add("postId", "feed-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.AddReaction).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestRemoveReaction(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	tests := []struct {
+		name           string
+		affected       int64
+		expectedStatus int
+	}{
+		{name: "Success", affected: 1, expectedStatus: http.StatusOK},
+		{name: "Had not reacted", affected: 0, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+				WithArgs("feed-1", "invite-1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "type"}).AddRow("feed-1", "invite-1", "CHAT"))
+			mock.ExpectExec(`DELETE FROM "FeedReaction"`).
+				WithArgs("feed-1", "user-123", "👍").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			if tt.affected > 0 {
+				mock.ExpectQuery(`SELECT "feedItemId", emoji, COUNT\(\*\)`).
+					WithArgs(`{"feed-1"}`, "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"feedItemId", "emoji", "count", "reacted"}))
+			}
+
+			req, _ := http.NewRequest("DELETE", "/invites/invite-1/feed/feed-1/reactions?emoji=%F0%9F%91%8D", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			rctx.URLParams.Add("postId", "feed-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			api.Handler(handler.RemoveReaction).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, "[]", rr.Body.String())
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestIsEmoji(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"👍", true},
+		{"👍🏽", true},
+		{"❤️", true},
+		{"👨‍👩‍👧‍👦", true},
+		{"🇯🇵", true},
+		{"", false},
+		{"+1", false},
+		{"lol", false},
+		{"👍 ", false},
+		{"🎉🎉🎉🎉🎉🎉🎉🎉🎉🎉🎉", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, isEmoji(tt.input))
+		})
+	}
+}
+
+func TestListMentions(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	now := time.Now().UTC()
+	columns := []string{"id", "inviteId", "userId", "content", "type", "createdAt", "postedAt", "replyCount", "inviteTitle", "user.id", "user.name"}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockBehavior   func()
+		expectedStatus int
+		expectedItems  int
+		expectedNext   bool
+	}{
+		{
+			name:  "Newest first with a next page",
+			query: "?limit=1",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT f\.\*, (.+) FROM "EventFeedItem" f (.+) "FeedMention" m (.+) LIMIT \$4`).
+					WithArgs("user-123", nil, "", 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("feed-2", "invite-1", "user-1", "@Ann Lee bring ice", "CHAT", now, now, 0, "Dinner", "user-1", "Bob").
+						AddRow("feed-1", "invite-2", "user-2", "Thanks @Ann Lee", "CHAT", now, now, 0, "Hike", "user-2", "Cy"))
+				mock.ExpectQuery(`SELECT "feedItemId", emoji, COUNT\(\*\)`).
+					WithArgs(`{"feed-2","feed-1"}`, "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"feedItemId", "emoji", "count", "reacted"}))
+				mock.ExpectQuery(`FROM "FeedMention"`).
+					WithArgs(`{"feed-2","feed-1"}`).
+					WillReturnRows(sqlmock.NewRows([]string{"feedItemId", "userId", "start", "length"}).
+						AddRow("feed-2", "user-123", 0, 8).
+						AddRow("feed-1", "user-123", 7, 8))
+			},
+			expectedStatus: http.StatusOK,
+			expectedItems:  1,
+			expectedNext:   true,
+		},
+		{
+			name:           "Limit out of range",
+			query:          "?limit=500",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/users/me/mentions"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-123"))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.ListMentions).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if rr.Code == http.StatusOK {
+				var page models.MentionPage
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+				assert.Len(t, page.Items, tt.expectedItems)
+				assert.Equal(t, tt.expectedNext, page.NextCursor != nil)
+				assert.Equal(t, "Dinner", page.Items[0].InviteTitle)
+				assert.Equal(t, []models.MentionSpan{{UserID: "user-123", Start: 0, Length: 8}}, page.Items[0].Mentions)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestEditPost(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	postRow := func(author, postType string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "inviteId", "userId", "type", "content"}).
+			AddRow("feed-1", "invite-1", author, postType, "Doors at 7")
+	}
+
+	tests := []struct {
+		name           string
+		content        string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:    "Success",
+			content: "Doors at 8 now",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+					WithArgs("feed-1", "invite-1").
+					WillReturnRows(postRow("user-123", "CHAT"))
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "FeedPostRevision"`).
+					WithArgs(sqlmock.AnyArg(), "feed-1", sqlmock.AnyArg(), "user-123").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`UPDATE "EventFeedItem"`).
+					WithArgs("feed-1", "user-123", "Doors at 8 now", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`DELETE FROM "FeedMention"`).
+					WithArgs("feed-1").
+					WillReturnRows(sqlmock.NewRows([]string{"userId", "notifiedAt"}))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "Unchanged content is not written",
+			content: "  Doors at 7 ",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+					WithArgs("feed-1", "invite-1").
+					WillReturnRows(postRow("user-123", "CHAT"))
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:    "Not the author",
+			content: "Doors at 8 now",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+					WithArgs("feed-1", "invite-1").
+					WillReturnRows(postRow("user-456", "CHAT"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:    "Capsule",
+			content: "Same time next year",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+					WithArgs("feed-1", "invite-1").
+					WillReturnRows(postRow("user-123", "CAPSULE"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "Empty content",
+			content: "   ",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"content": tt.content})
+			req, _ := http.NewRequest("PATCH", "/invites/invite-1/feed/feed-1", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			rctx.URLParams.Add("postId", "feed-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.EditPost).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDeletePost(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	tests := []struct {
+		name           string
+		author         string
+		expectedStatus int
+	}{
+		{name: "Success", author: "user-123", expectedStatus: http.StatusOK},
+		{name: "Not the author", author: "user-456", expectedStatus: http.StatusForbidden},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req, _ := http.NewRequest("GET", "/feed/"+tt.inviteID, nil)
+			expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+				WithArgs("feed-1", "invite-1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "userId", "type"}).AddRow("feed-1", "invite-1", tt.author, "CHAT"))
+			if tt.expectedStatus == http.StatusOK {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "EventFeedItem"`).
+					WithArgs("feed-1", sqlmock.AnyArg(), "user-123", nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM "FeedPostRevision"`).WithArgs("feed-1").WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`DELETE FROM "FeedReaction"`).WithArgs("feed-1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM "FeedMention"`).WithArgs("feed-1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			}
+
+			req, _ := http.NewRequest("DELETE", "/invites/invite-1/feed/feed-1", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			rctx.URLParams.Add("postId", "feed-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			api.Handler(handler.DeletePost).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestModeratePost(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	expectPost := func() {
+		mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+			WithArgs("feed-1", "invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "userId", "type"}).AddRow("feed-1", "invite-1", "user-456", "CHAT"))
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           models.ModeratePostRequest
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Host hides a post",
+			userID: "user-host",
+			body:   models.ModeratePostRequest{Action: models.ModerationHide, Reason: "Off topic"},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				expectPost()
+				mock.ExpectExec(`UPDATE "EventFeedItem"`).
+					WithArgs("feed-1", sqlmock.AnyArg(), "user-host", "Off topic").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Circle admin removes a post",
+			userID: "user-admin",
+			body:   models.ModeratePostRequest{Action: models.ModerationRemove, Reason: "Spam"},
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+					WithArgs("invite-1", "user-admin").
+					WillReturnRows(sqlmock.NewRows([]string{"inviteId", "senderId", "circleId", "isActiveMember", "isInvitee", "circleRole"}).
+						AddRow("invite-1", "user-host", "circle-1", true, false, "ADMIN"))
+				expectPost()
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "EventFeedItem"`).
+					WithArgs("feed-1", sqlmock.AnyArg(), "user-admin", "Spam").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM "FeedPostRevision"`).WithArgs("feed-1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM "FeedReaction"`).WithArgs("feed-1").WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`DELETE FROM "FeedMention"`).WithArgs("feed-1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Members cannot moderate",
+			userID: "user-123",
+			body:   models.ModeratePostRequest{Action: models.ModerationHide, Reason: "Off topic"},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Reason is required",
+			userID: "user-host",
+			body:   models.ModeratePostRequest{Action: models.ModerationHide, Reason: "  "},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Unknown action",
+			userID: "user-host",
+			body:   models.ModeratePostRequest{Action: "DELETE"},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Unhiding a visible post",
+			userID: "user-host",
+			body:   models.ModeratePostRequest{Action: models.ModerationUnhide},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				expectPost()
+				mock.ExpectExec(`UPDATE "EventFeedItem"`).
+					WithArgs("feed-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
 
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/invites/invite-1/feed/feed-1/moderation", bytes.NewBuffer(body))
 			rctx := chi.NewRouteContext()
-			rctx.URLParams.Add("inviteId", tt.inviteID)
-			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rctx.URLParams.Add("id", "invite-1")
+			rctx.URLParams.Add("postId", "feed-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
 
 			tt.mockBehavior()
 			rr := httptest.NewRecorder()
-			handler.GetFeed(rr, req)
+			api.Handler(handler.ModeratePost).ServeHTTP(rr, req)
+
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("unfulfilled expectations: %s", err)
@@ -119,3 +941,59 @@ func TestGetFeed(t *testing.T) {
 		})
 	}
 }
+
+func TestListRevisions(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewFeedHandler(repository.NewFeedRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	replacedAt := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		hidden         bool
+		expectedStatus int
+	}{
+		{name: "Success", expectedStatus: http.StatusOK},
+		{name: "Hidden from other members", hidden: true, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hiddenAt *time.Time
+			if tt.hidden {
+				hiddenAt = &replacedAt
+			}
+			expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			mock.ExpectQuery(`SELECT f\.\* FROM "EventFeedItem" f`).
+				WithArgs("feed-1", "invite-1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "userId", "type", "hiddenAt"}).AddRow("feed-1", "invite-1", "user-456", "CHAT", hiddenAt))
+			if !tt.hidden {
+				mock.ExpectQuery(`SELECT .* FROM "FeedPostRevision"`).
+					WithArgs("feed-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "feedItemId", "content", "createdAt"}).AddRow("revision-1", "feed-1", "Doors at 7", replacedAt))
+			}
+
+			req, _ := http.NewRequest("GET", "/invites/invite-1/feed/feed-1/revisions", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			rctx.URLParams.Add("postId", "feed-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			api.Handler(handler.ListRevisions).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, `[{"id":"revision-1","feedItemId":"feed-1","content":"Doors at 7","replacedAt":"2026-05-01T18:00:00Z"}]`, rr.Body.String())
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -1,34 +1,60 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
 	"time"
 
+	"privo-club-backend/internal/access"
 	"privo-club-backend/internal/api"
 	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
 	"privo-club-backend/internal/models"
 	"privo-club-backend/internal/repository"
 	"privo-club-backend/internal/utils"
+	"privo-club-backend/internal/vault"
 
 	"github.com/go-chi/chi/v5"
 )
 
+const (
+	defaultInviteListLimit = 50
+	maxInviteListLimit     = 100
+)
+
 type InvitesHandler struct {
-	Repo repository.InviteRepository
+	Repo     repository.InviteRepository
+	Venues   repository.VenueRepository
+	Access   *access.Policy
+	Geocoder geo.Geocoder
 }
 
-func NewInvitesHandler(repo repository.InviteRepository) *InvitesHandler {
-	return &InvitesHandler{Repo: repo}
+func NewInvitesHandler(repo repository.InviteRepository, venuesRepo repository.VenueRepository, policy *access.Policy, geocoder geo.Geocoder) *InvitesHandler {
+	return &InvitesHandler{Repo: repo, Venues: venuesRepo, Access: policy, Geocoder: geocoder}
 }
 
 func (h *InvitesHandler) RegisterRoutes(r chi.Router) {
 	r.Method("POST", "/", api.Handler(h.CreateInvite))
 	r.Method("GET", "/", api.Handler(h.ListInvites))
+	r.Method("GET", "/nearby", api.Handler(h.ListNearbyInvites))
+	r.Method("GET", "/calendar", api.Handler(h.ListCalendar))
 	r.Method("GET", "/{id}", api.Handler(h.GetInvite))
 	r.Method("POST", "/{id}/rsvp", api.Handler(h.RespondToRSVP))
 	r.Method("PATCH", "/{id}", api.Handler(h.UpdateInvite))
 	r.Method("DELETE", "/{id}", api.Handler(h.DeleteInvite))
+	r.Method("POST", "/{id}/invitees", api.Handler(h.AddInvitees))
+	r.Method("DELETE", "/{id}/invitees/{inviteeId}", api.Handler(h.RemoveInvitee))
+	r.Method("POST", "/{id}/duplicate", api.Handler(h.DuplicateInvite))
+	r.Method("POST", "/{id}/vault/unlock", api.Handler(h.UnlockVault))
 }
 
 func (h *InvitesHandler) CreateInvite(w http.ResponseWriter, r *http.Request) error {
@@ -42,17 +68,47 @@ func (h *InvitesHandler) CreateInvite(w http.ResponseWriter, r *http.Request) er
 		return api.ErrBadRequest("Invalid request body")
 	}
 
-	if req.Title == "" || req.EventDate.IsZero() {
-		return api.ErrBadRequest("Title, event date are required")
+	inviteID := utils.GenerateID("invite")
+
+	// Several candidate dates start a date poll instead of fixing the date now
+	dateOptions, err := buildDateOptions(inviteID, req.DateOptions)
+	if err != nil {
+		return err
+	}
+	isDateTBD := len(dateOptions) > 0
+	if isDateTBD {
+		if len(dateOptions) < 2 {
+			return api.ErrBadRequest("A date poll needs at least two options")
+		}
+		// Options are sorted, the earliest one stands in for the date until the poll is finalized
+		req.EventDate = dateOptions[0].StartsAt
 	}
 
-	// Validate that the event date is not in the past
-	// We allow a small 5-minute buffer to account for potential clock skew between client and server
-	if req.EventDate.Before(time.Now().Add(-5 * time.Minute)) {
-		return api.ErrBadRequest("Event date cannot be in the past")
+	if req.Title == "" {
+		return api.ErrBadRequest("Title is required")
+	}
+	if err := validateEventDate(req.EventDate); err != nil {
+		return err
+	}
+	if err := validateEndDate(req.EventDate, req.EndDate); err != nil {
+		return err
+	}
+	policy := vault.Normalize(req.VaultPolicy)
+	if err := vault.Validate(policy, req.EventDate); err != nil {
+		return api.NewAPIError(http.StatusBadRequest, "Invalid vault policy: "+err.Error(), err)
+	}
+
+	if req.VenueID != nil {
+		if err := h.useVenue(r.Context(), userID, &req); err != nil {
+			return err
+		}
+	}
+
+	place, err := resolvePlace(r.Context(), h.Geocoder, req.Location, req.MapLink, req.Place)
+	if err != nil {
+		return err
 	}
 
-	inviteID := utils.GenerateID("invite")
 	invite := &models.Invite{
 		ID:          inviteID,
 		Title:       req.Title,
@@ -60,13 +116,31 @@ func (h *InvitesHandler) CreateInvite(w http.ResponseWriter, r *http.Request) er
 		Location:    req.Location,
 		MapLink:     req.MapLink,
 		EventDate:   req.EventDate,
+		EndDate:     req.EndDate,
 		SenderID:    userID,
 		CircleID:    req.CircleID,
+		IsDateTBD:   isDateTBD,
+		VenueID:     req.VenueID,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+		Place:       place,
+		VaultPolicy: policy,
 	}
 
-	if err := h.Repo.CreateInvite(r.Context(), invite); err != nil {
+	invitees, err := buildInvitees(inviteID, req.Invitees)
+	if err != nil {
+		return err
+	}
+	if err := h.checkInviteeUsers(r, invitees); err != nil {
+		return err
+	}
+
+	rel := models.InviteRelations{Invitees: invitees, DateOptions: dateOptions}
+	if !rel.IsEmpty() {
+		if err := h.Repo.CreateInviteWithRelations(r.Context(), invite, rel); err != nil {
+			return api.ErrInternal(err)
+		}
+	} else if err := h.Repo.CreateInvite(r.Context(), invite); err != nil {
 		return api.ErrInternal(err)
 	}
 
@@ -74,19 +148,36 @@ func (h *InvitesHandler) CreateInvite(w http.ResponseWriter, r *http.Request) er
 	return json.NewEncoder(w).Encode(invite)
 }
 
+// ListInvites returns one page of the user's invites. See parseInviteListFilter
+// for the supported query parameters.
 func (h *InvitesHandler) ListInvites(w http.ResponseWriter, r *http.Request) error {
 	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
 		return api.ErrUnauthorized("Unauthorized")
 	}
 
-	response, err := h.Repo.ListInvites(r.Context(), userID)
+	filter, err := parseInviteListFilter(r)
+	if err != nil {
+		return err
+	}
+	limit := filter.Limit
+	filter.Limit++ // One extra row tells us whether there is a next page
+
+	invites, err := h.Repo.ListInvites(r.Context(), userID, filter)
 	if err != nil {
 		return api.ErrInternal(err)
 	}
 
+	page := models.InviteListPage{Invites: invites}
+	if len(invites) > limit {
+		page.Invites = invites[:limit]
+		last := page.Invites[limit-1]
+		cursor := encodeInviteCursor(models.InviteCursor{EventDate: last.EventDate, ID: last.ID})
+		page.NextCursor = &cursor
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(response)
+	return json.NewEncoder(w).Encode(page)
 }
 
 func (h *InvitesHandler) RespondToRSVP(w http.ResponseWriter, r *http.Request) error {
@@ -109,6 +200,10 @@ func (h *InvitesHandler) RespondToRSVP(w http.ResponseWriter, r *http.Request) e
 		return api.ErrBadRequest("Status is required")
 	}
 
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
 	if req.GuestCount < 1 {
 		req.GuestCount = 1
 	}
@@ -130,8 +225,18 @@ func (h *InvitesHandler) RespondToRSVP(w http.ResponseWriter, r *http.Request) e
 		return api.ErrInternal(err)
 	}
 
+	resp := models.RSVPResponse{Success: true}
+	if req.Status == "YES" {
+		// The RSVP is already saved, a failed check only costs the warning
+		overlaps, err := h.Repo.ListOverlappingInvites(r.Context(), inviteID, userID)
+		if err != nil {
+			slog.Warn("Overlap check failed", "inviteId", inviteID, "error", err)
+		}
+		resp.Overlaps = overlaps
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+	return json.NewEncoder(w).Encode(resp)
 }
 
 func (h *InvitesHandler) DeleteInvite(w http.ResponseWriter, r *http.Request) error {
@@ -159,30 +264,50 @@ func (h *InvitesHandler) DeleteInvite(w http.ResponseWriter, r *http.Request) er
 }
 
 func (h *InvitesHandler) GetInvite(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
 	inviteID := chi.URLParam(r, "id")
 
-	details, err := h.Repo.GetInviteDetails(r.Context(), inviteID)
+	a, err := h.Access.RequireView(r.Context(), inviteID, userID)
 	if err != nil {
+		return err
+	}
+
+	details, err := h.Repo.GetInviteDetails(r.Context(), inviteID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
 		return api.ErrNotFound("Invite not found")
 	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	// Distance is only shown when the client shares its position
+	position, hasPosition, err := positionFromQuery(r)
+	if err != nil {
+		return err
+	}
+	if hasPosition {
+		details.Distance = distanceTo(position, details.Place)
+	}
 
-	// Lazy Unlock Check
-	// If vault is locked AND 24h have passed since event start
-	if !details.Invite.IsVaultUnlocked {
-		unlockTime := details.Invite.EventDate.Add(24 * time.Hour)
-		if time.Now().After(unlockTime) {
-			// Unlock it
-			if err := h.Repo.UpdateVaultStatus(r.Context(), inviteID, true, unlockTime); err != nil {
-				// Log error but proceed with returning the (technically locked) invite
-				// In a real app, use a logger
-				println("Failed to update vault status:", err.Error())
-			} else {
-				// Update in-memory response
-				details.Invite.IsVaultUnlocked = true
-				details.Invite.VaultUnlockDate = &unlockTime
+	// Email addresses of people without an account are only shown to the host
+	if !a.IsHost() {
+		for i := range details.Invitees {
+			if details.Invitees[i].User == nil {
+				details.Invitees[i].Email = nil
 			}
 		}
+		for i := range details.GuestRSVPs {
+			details.GuestRSVPs[i].Email = ""
+		}
+	}
+	for i := range details.FeedItems {
+		details.FeedItems[i].Redact(userID, a.CanModerate())
 	}
+	sealVault(details, userID)
 
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(details)
@@ -208,18 +333,379 @@ func (h *InvitesHandler) UpdateInvite(w http.ResponseWriter, r *http.Request) er
 		return api.ErrForbidden("Only the host can update invite details")
 	}
 
-	var req struct {
-		Location *string `json:"location"`
-		MapLink  *string `json:"mapLink"`
+	var req models.UpdateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+
+	place, err := resolvePlace(r.Context(), h.Geocoder, req.Location, req.MapLink, req.Place)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.UpdateInvite(r.Context(), inviteID, req.Location, req.MapLink, place); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *InvitesHandler) AddInvitees(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can invite people"); err != nil {
+		return err
 	}
+
+	var req models.AddInviteesRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return api.ErrBadRequest("Invalid request body")
 	}
 
-	if err := h.Repo.UpdateInvite(r.Context(), inviteID, req.Location, req.MapLink); err != nil {
+	invitees, err := buildInvitees(inviteID, req.Invitees)
+	if err != nil {
+		return err
+	}
+	if len(invitees) == 0 {
+		return api.ErrBadRequest("At least one invitee is required")
+	}
+	if err := h.checkInviteeUsers(r, invitees); err != nil {
+		return err
+	}
+
+	if err := h.Repo.AddInvitees(r.Context(), invitees); err != nil {
 		return api.ErrInternal(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
+
+func (h *InvitesHandler) RemoveInvitee(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	inviteeID := chi.URLParam(r, "inviteeId")
+	if inviteeID == "" {
+		return api.ErrBadRequest("Invitee ID required")
+	}
+
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can remove invitees"); err != nil {
+		return err
+	}
+
+	if err := h.Repo.RemoveInvitee(r.Context(), inviteID, inviteeID); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// checkInviteeUsers rejects invitees whose user ID has no account, which
+// would otherwise fail on the foreign key
+func (h *InvitesHandler) checkInviteeUsers(r *http.Request, invitees []models.InviteInvitee) error {
+	var userIDs []string
+	for _, invitee := range invitees {
+		if invitee.UserID != nil {
+			userIDs = append(userIDs, *invitee.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	count, err := h.Repo.CountUsers(r.Context(), userIDs)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if count != len(userIDs) {
+		return api.ErrBadRequest("Invitee user not found")
+	}
+	return nil
+}
+
+// buildInvitees validates and de-duplicates invitee requests. Emails are
+// normalized to lower case so they match the address used on sign in.
+func buildInvitees(inviteID string, reqs []models.InviteeRequest) ([]models.InviteInvitee, error) {
+	seen := make(map[string]bool)
+	invitees := []models.InviteInvitee{}
+
+	for _, req := range reqs {
+		invitee := models.InviteInvitee{
+			ID:        utils.GenerateID("invitee"),
+			InviteID:  inviteID,
+			CreatedAt: time.Now(),
+		}
+
+		switch {
+		case req.UserID != nil && *req.UserID != "":
+			key := "user:" + *req.UserID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			invitee.UserID = req.UserID
+		case req.Email != nil && *req.Email != "":
+			addr, err := mail.ParseAddress(*req.Email)
+			if err != nil {
+				return nil, api.ErrBadRequest("Invalid invitee email: " + *req.Email)
+			}
+			email := strings.ToLower(addr.Address)
+			if seen["email:"+email] {
+				continue
+			}
+			seen["email:"+email] = true
+			invitee.Email = &email
+		default:
+			return nil, api.ErrBadRequest("Each invitee needs a user ID or an email")
+		}
+
+		invitees = append(invitees, invitee)
+	}
+	return invitees, nil
+}
+
+// DuplicateInvite copies an invite's details to a new date. Sign-up sheets
+// (without claims) and direct invitees can be carried over; RSVPs, feed and
+// media never are.
+func (h *InvitesHandler) DuplicateInvite(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	sourceID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), sourceID, userID, "Only the host can duplicate this invite"); err != nil {
+		return err
+	}
+
+	var req models.DuplicateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	if err := validateEventDate(req.EventDate); err != nil {
+		return err
+	}
+
+	source, err := h.Repo.GetInviteDetails(r.Context(), sourceID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Invite not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	// The copy posts into the same circle, which needs a current membership
+	if source.CircleID != nil {
+		isMember, err := h.Repo.IsCircleMember(r.Context(), *source.CircleID, userID)
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+		if !isMember {
+			return api.ErrForbidden("You are not a member of this circle")
+		}
+	}
+
+	now := time.Now()
+	invite := &models.Invite{
+		ID:          utils.GenerateID("invite"),
+		Title:       source.Title,
+		Description: source.Description,
+		Location:    source.Location,
+		MapLink:     source.MapLink,
+		EventDate:   req.EventDate,
+		EndDate:     shiftEndDate(source.Invite, req.EventDate),
+		SenderID:    userID,
+		CircleID:    source.CircleID,
+		VenueID:     source.VenueID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Place:       source.Place,
+		VaultPolicy: vault.Shift(source.VaultPolicy, req.EventDate.Sub(source.EventDate)),
+	}
+
+	var rel models.InviteRelations
+	if req.IncludeSignupSheets {
+		for _, sheet := range source.SignupSheets {
+			sheetID := utils.GenerateID("signup")
+			rel.SignupSheets = append(rel.SignupSheets, models.SignupSheet{
+				ID:          sheetID,
+				InviteID:    invite.ID,
+				Title:       sheet.Title,
+				Description: sheet.Description,
+				CreatedByID: userID,
+				CreatedAt:   now,
+			})
+			for _, item := range sheet.Items {
+				rel.SignupItems = append(rel.SignupItems, models.SignupItem{
+					ID:        utils.GenerateID("item"),
+					SheetID:   sheetID,
+					Name:      item.Name,
+					Quantity:  item.Quantity,
+					Position:  item.Position,
+					CreatedAt: now,
+				})
+			}
+		}
+	}
+	if req.IncludeInvitees {
+		for _, invitee := range source.Invitees {
+			rel.Invitees = append(rel.Invitees, models.InviteInvitee{
+				ID:        utils.GenerateID("invitee"),
+				InviteID:  invite.ID,
+				UserID:    invitee.UserID,
+				Email:     invitee.Email,
+				CreatedAt: now,
+			})
+		}
+	}
+
+	if !rel.IsEmpty() {
+		if err := h.Repo.CreateInviteWithRelations(r.Context(), invite, rel); err != nil {
+			return api.ErrInternal(err)
+		}
+	} else if err := h.Repo.CreateInvite(r.Context(), invite); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(invite)
+}
+
+// useVenue fills the request's location from a venue of the invite's circle
+func (h *InvitesHandler) useVenue(ctx context.Context, userID string, req *models.CreateInviteRequest) error {
+	if req.CircleID == nil {
+		return api.ErrBadRequest("Venues can only be used for circle invites")
+	}
+	if _, err := h.Venues.GetMemberRole(ctx, *req.CircleID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.ErrForbidden("You are not a member of this circle")
+		}
+		return api.ErrInternal(err)
+	}
+
+	venue, err := h.Venues.GetVenue(ctx, *req.CircleID, *req.VenueID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrBadRequest("Venue not found in this circle")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	venueDefaults(req, venue)
+	return nil
+}
+
+// parseInviteListFilter reads when=upcoming|past, circleId, hosted=true,
+// rsvp=YES|NO|MAYBE|NONE, from/to (RFC 3339), limit and cursor.
+func parseInviteListFilter(r *http.Request) (models.InviteListFilter, error) {
+	q := r.URL.Query()
+	// Invite dates are stored as UTC without a zone, so every bound must be UTC too
+	filter := models.InviteListFilter{Limit: defaultInviteListLimit, Now: time.Now().UTC()}
+
+	switch when := strings.ToLower(q.Get("when")); when {
+	case "", models.InvitesUpcoming, models.InvitesPast:
+		filter.When = when
+	default:
+		return filter, api.ErrBadRequest("when must be upcoming or past")
+	}
+
+	if circleID := q.Get("circleId"); circleID != "" {
+		filter.CircleID = &circleID
+	}
+	filter.HostedOnly = q.Get("hosted") == "true"
+
+	switch status := strings.ToUpper(q.Get("rsvp")); status {
+	case "", "YES", "NO", "MAYBE", "NONE":
+		filter.RSVPStatus = status
+	default:
+		return filter, api.ErrBadRequest("rsvp must be YES, NO, MAYBE or NONE")
+	}
+
+	for name, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, api.ErrBadRequest(name + " must be an RFC 3339 timestamp")
+			}
+			t = t.UTC()
+			*dst = &t
+		}
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxInviteListLimit {
+			return filter, api.ErrBadRequest(fmt.Sprintf("limit must be between 1 and %d", maxInviteListLimit))
+		}
+		filter.Limit = limit
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := decodeInviteCursor(v)
+		if err != nil {
+			return filter, api.ErrBadRequest("Invalid cursor")
+		}
+		filter.After = &cursor
+	}
+	return filter, nil
+}
+
+// Cursors are opaque to clients: base64 of "<eventDate>|<id>"
+func encodeInviteCursor(c models.InviteCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.EventDate.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+func decodeInviteCursor(s string) (models.InviteCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return models.InviteCursor{}, err
+	}
+	date, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return models.InviteCursor{}, errors.New("malformed cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, date)
+	if err != nil {
+		return models.InviteCursor{}, err
+	}
+	return models.InviteCursor{EventDate: t, ID: id}, nil
+}
+
+// validateEventDate rejects missing dates and dates in the past. We allow a
+// small 5-minute buffer to account for clock skew between client and server.
+func validateEventDate(eventDate time.Time) error {
+	if eventDate.IsZero() {
+		return api.ErrBadRequest("Event date is required")
+	}
+	if eventDate.Before(time.Now().Add(-5 * time.Minute)) {
+		return api.ErrBadRequest("Event date cannot be in the past")
+	}
+	return nil
+}
+
+func validateEndDate(eventDate time.Time, endDate *time.Time) error {
+	if endDate != nil && !endDate.After(eventDate) {
+		return api.ErrBadRequest("End date must be after the event date")
+	}
+	return nil
+}
+
+// shiftEndDate keeps the source invite's duration when it moves to a new date
+func shiftEndDate(source models.Invite, eventDate time.Time) *time.Time {
+	if source.EndDate == nil {
+		return nil
+	}
+	end := eventDate.Add(source.EndDate.Sub(source.EventDate))
+	return &end
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,8 +11,11 @@ import (
 	"testing"
 	"time"
 
+	"privo-club-backend/internal/access"
 	"privo-club-backend/internal/api"
 	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
+	"privo-club-backend/internal/models"
 	"privo-club-backend/internal/repository"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -28,10 +32,10 @@ func TestCreateInvite(t *testing.T) {
 	defer mockDB.Close()
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	repo := repository.NewInviteRepository(sqlxDB)
-	handler := NewInvitesHandler(repo)
+	handler := NewInvitesHandler(repo, repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
 
-	// Fixed time for event date
-	eventDate := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Event date safely in the future so the past-date validation passes
+	eventDate := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)
 
 	tests := []struct {
 		name           string
@@ -49,11 +53,172 @@ func TestCreateInvite(t *testing.T) {
 			},
 			mockBehavior: func() {
 				mock.ExpectExec(`INSERT INTO "Invite"`).
-					WithArgs(sqlmock.AnyArg(), "Party", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WithArgs(sqlmock.AnyArg(), "Party", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), false, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, "DELAY", 24, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:   "Coordinates from map link",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":     "Picnic",
+				"eventDate": eventDate,
+				"location":  "Central Park",
+				"mapLink":   "https://www.google.com/maps/place/Central+Park/@40.78,-73.96,15z/data=!3d40.7829!4d-73.9654",
+			},
+			mockBehavior: func() {
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WithArgs(sqlmock.AnyArg(), "Picnic", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), false, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 40.7829, -73.9654, "DELAY", 24, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Location from circle venue",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":     "Book club",
+				"eventDate": eventDate,
+				"circleId":  "circle-1",
+				"venueId":   "venue-1",
+			},
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-123", "MEMBER")
+				mock.ExpectQuery(`SELECT \* FROM "Venue" WHERE id = \$1`).
+					WithArgs("venue-1", "circle-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "circleId", "name", "address", "latitude", "longitude", "createdById"}).
+						AddRow("venue-1", "circle-1", "Library", "5 Main St", 40.7, -74.0, "user-456"))
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WithArgs(sqlmock.AnyArg(), "Book club", sqlmock.AnyArg(), "Library, 5 Main St", sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", "circle-1", sqlmock.AnyArg(), sqlmock.AnyArg(), false, "venue-1", nil, "Library", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 40.7, -74.0, "DELAY", 24, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Venue from another circle",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":     "Book club",
+				"eventDate": eventDate,
+				"circleId":  "circle-1",
+				"venueId":   "venue-9",
+			},
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-123", "MEMBER")
+				mock.ExpectQuery(`SELECT \* FROM "Venue" WHERE id = \$1`).
+					WithArgs("venue-9", "circle-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "End before start",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":     "Party",
+				"eventDate": eventDate,
+				"endDate":   eventDate.Add(-time.Hour),
+			},
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Invalid vault policy",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":                 "Party",
+				"eventDate":             eventDate,
+				"vaultPolicy":           "DELAY",
+				"vaultUnlockAfterHours": 0,
+			},
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Latitude without longitude",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":     "Picnic",
+				"eventDate": eventDate,
+				"latitude":  40.7829,
+			},
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Success with date poll",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":       "Game night",
+				"dateOptions": []time.Time{eventDate.Add(24 * time.Hour), eventDate, eventDate},
+			},
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				// The earliest option stands in for the event date while the poll is open
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WithArgs(sqlmock.AnyArg(), "Game night", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), eventDate, "user-123", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), true, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, "DELAY", 24, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "DatePollOption"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), eventDate, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "DatePollOption"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), eventDate.Add(24*time.Hour), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Date poll with a single option",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":       "Game night",
+				"dateOptions": []time.Time{eventDate},
+			},
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Success with direct invitees",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":     "Dinner",
+				"eventDate": eventDate,
+				"invitees": []map[string]interface{}{
+					{"userId": "user-456"},
+					{"email": "Guest@Example.com"},
+					{"email": "guest@example.com"},
+				},
+			},
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "User"`).
+					WithArgs(`{"user-456"}`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "InviteInvitee"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "user-456", nil, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "InviteInvitee"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "guest@example.com", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Invalid invitee email",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"title":     "Dinner",
+				"eventDate": eventDate,
+				"invitees":  []map[string]interface{}{{"email": "not-an-email"}},
+			},
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -84,15 +249,16 @@ func TestRSVP(t *testing.T) {
 	defer mockDB.Close()
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	repo := repository.NewInviteRepository(sqlxDB)
-	handler := NewInvitesHandler(repo)
+	handler := NewInvitesHandler(repo, repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
 
 	tests := []struct {
-		name           string
-		userID         string
-		inviteID       string
-		body           map[string]interface{}
-		mockBehavior   func()
-		expectedStatus int
+		name             string
+		userID           string
+		inviteID         string
+		body             map[string]interface{}
+		mockBehavior     func()
+		expectedStatus   int
+		expectedOverlaps int
 	}{
 		{
 			name:     "Success",
@@ -103,6 +269,7 @@ func TestRSVP(t *testing.T) {
 				"guestCount": 1,
 			},
 			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
 				// The Insert statement is complex with ON CONFLICT, we just match prefix or regex
 				mock.ExpectExec(`INSERT INTO "RSVP"`).
 					WithArgs(
@@ -117,9 +284,66 @@ func TestRSVP(t *testing.T) {
 						sqlmock.AnyArg(), // updatedAt
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(`SELECT (.+) FROM "Invite" i JOIN "RSVP" r`).
+					WithArgs("invite-1", "user-123", models.DefaultEventDuration.Seconds()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Warns about overlapping events",
+			userID:   "user-123",
+			inviteID: "invite-1",
+			body: map[string]interface{}{
+				"status": "YES",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectExec(`INSERT INTO "RSVP"`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				start := time.Now().Add(48 * time.Hour)
+				mock.ExpectQuery(`SELECT (.+) FROM "Invite" i JOIN "RSVP" r`).
+					WithArgs("invite-1", "user-123", models.DefaultEventDuration.Seconds()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "eventDate", "endDate", "location", "senderId", "circleId", "isDateTBD", "rsvpStatus"}).
+						AddRow("invite-2", "Dinner", start, start.Add(2*time.Hour), nil, "user-789", nil, false, "YES"))
+			},
+			expectedStatus:   http.StatusOK,
+			expectedOverlaps: 1,
+		},
+		{
+			name:     "Declining drops ride data",
+			userID:   "user-123",
+			inviteID: "invite-1",
+			body: map[string]interface{}{
+				"status": "NO",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "RSVP"`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`DELETE FROM "RideOffer"`).
+					WithArgs("invite-1", "user-123").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM "RideRequest"`).
+					WithArgs("invite-1", "user-123").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:     "Not a circle member",
+			userID:   "user-456",
+			inviteID: "invite-1",
+			body: map[string]interface{}{
+				"status": "YES",
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-host", false)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
 	}
 
 	for _, tt := range tests {
@@ -140,6 +364,11 @@ func TestRSVP(t *testing.T) {
 			rr := httptest.NewRecorder()
 			api.Handler(handler.RespondToRSVP).ServeHTTP(rr, req)
 			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if rr.Code == http.StatusOK {
+				var resp models.RSVPResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Len(t, resp.Overlaps, tt.expectedOverlaps)
+			}
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("unfulfilled expectations: %s", err)
 			}
@@ -155,46 +384,107 @@ func TestListInvites(t *testing.T) {
 	defer mockDB.Close()
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	repo := repository.NewInviteRepository(sqlxDB)
-	handler := NewInvitesHandler(repo)
+	handler := NewInvitesHandler(repo, repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
+
+	eventDate := time.Date(2030, 5, 1, 18, 0, 0, 0, time.UTC)
+	inviteRows := func(ids ...string) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{
+			"id", "title", "description", "location", "eventDate", "senderId", "circleId", "createdAt", "updatedAt",
+			"sender_id", "sender_name", "sender_email", "sender_image",
+			"circle_id", "circle_name", "rsvp_count",
+		})
+		for _, id := range ids {
+			rows.AddRow(
+				id, "Party", "Desc", "Loc", eventDate, "user-123", nil, time.Now(), time.Now(),
+				"user-123", "User Name", "user@email.com", nil,
+				nil, nil, 0,
+			)
+		}
+		return rows
+	}
 
 	tests := []struct {
 		name           string
-		userID         string
+		query          string
 		mockBehavior   func()
 		expectedStatus int
+		expectedCursor *string
 	}{
 		{
-			name:   "Success",
-			userID: "user-123",
+			name:  "Success",
+			query: "",
 			mockBehavior: func() {
-				rows := sqlmock.NewRows([]string{
-					"id", "title", "description", "location", "eventDate", "senderId", "circleId", "createdAt", "updatedAt",
-					"sender_id", "sender_name", "sender_email", "sender_image",
-					"circle_id", "circle_name", "rsvp_count",
-				}).AddRow(
-					"invite-1", "Party", "Desc", "Loc", time.Now(), "user-123", nil, time.Now(), time.Now(),
-					"user-123", "User Name", "user@email.com", nil,
-					nil, nil, 0,
-				)
-				mock.ExpectQuery(`SELECT DISTINCT .* FROM "Invite"`).
-					WithArgs("user-123").
-					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i .* cm\.status = 'ACTIVE'.* ORDER BY i\."eventDate" ASC, i\.id ASC LIMIT \$2`).
+					WithArgs("user-123", defaultInviteListLimit+1).
+					WillReturnRows(inviteRows("invite-1"))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Filters",
+			query: "?when=upcoming&circleId=circle-1&hosted=true&rsvp=yes",
+			mockBehavior: func() {
+				mock.ExpectQuery(`i\."eventDate" >= \$2 AND i\."circleId" = \$3 AND i\."senderId" = \$1 AND EXISTS \(SELECT 1 FROM "RSVP" r .* r\.status = \$4\)`).
+					WithArgs("user-123", sqlmock.AnyArg(), "circle-1", "YES", defaultInviteListLimit+1).
+					WillReturnRows(inviteRows())
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Past invites page backwards from the cursor",
+			query: "?when=past&limit=2&cursor=" + encodeInviteCursor(models.InviteCursor{EventDate: eventDate, ID: "invite-9"}),
+			mockBehavior: func() {
+				mock.ExpectQuery(`i\."eventDate" < \$2 AND \(i\."eventDate", i\.id\) < \(\$3, \$4\) ORDER BY i\."eventDate" DESC, i\.id DESC LIMIT \$5`).
+					WithArgs("user-123", sqlmock.AnyArg(), eventDate, "invite-9", 3).
+					WillReturnRows(inviteRows("invite-3", "invite-2", "invite-1"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedCursor: func() *string {
+				c := encodeInviteCursor(models.InviteCursor{EventDate: eventDate, ID: "invite-2"})
+				return &c
+			}(),
+		},
+		{
+			name:  "Date range with an offset",
+			query: "?from=2026-03-01T00:00:00%2B01:00&to=2026-03-31T23:59:59-05:00",
+			mockBehavior: func() {
+				// The column has no zone, so the bounds are sent as UTC
+				mock.ExpectQuery(`i\."eventDate" >= \$2 AND i\."eventDate" < \$3`).
+					WithArgs("user-123", time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 4, 59, 59, 0, time.UTC), defaultInviteListLimit+1).
+					WillReturnRows(inviteRows())
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "Invalid status",
+			query:          "?rsvp=SOMETIMES",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid cursor",
+			query:          "?cursor=not-a-cursor",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req, _ := http.NewRequest("GET", "/invites", nil)
-			if tt.userID != "" {
-				ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
-				req = req.WithContext(ctx)
-			}
+			req, _ := http.NewRequest("GET", "/invites"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, "user-123")
+			req = req.WithContext(ctx)
+
 			tt.mockBehavior()
 			rr := httptest.NewRecorder()
 			api.Handler(handler.ListInvites).ServeHTTP(rr, req)
 			assert.Equal(t, tt.expectedStatus, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				var page models.InviteListPage
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+				assert.Equal(t, tt.expectedCursor, page.NextCursor)
+			}
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("unfulfilled expectations: %s", err)
 			}
@@ -210,18 +500,21 @@ func TestGetInvite(t *testing.T) {
 	defer mockDB.Close()
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	repo := repository.NewInviteRepository(sqlxDB)
-	handler := NewInvitesHandler(repo)
+	handler := NewInvitesHandler(repo, repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
 
 	tests := []struct {
 		name           string
+		userID         string
 		inviteID       string
 		mockBehavior   func()
 		expectedStatus int
 	}{
 		{
 			name:     "Success",
+			userID:   "user-123",
 			inviteID: "invite-1",
 			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
 				// 1. Invite
 				rowsInvite := sqlmock.NewRows([]string{"id", "title", "senderId", "circleId"}).
 					AddRow("invite-1", "Party", "user-123", nil)
@@ -237,11 +530,29 @@ func TestGetInvite(t *testing.T) {
 				mock.ExpectQuery(`SELECT r\.\*, .* FROM "RSVP"`).
 					WithArgs("invite-1").
 					WillReturnRows(sqlmock.NewRows([]string{}))
-				// 4. Feed
+				// 4. Invitees
+				mock.ExpectQuery(`SELECT inv\.\*, .* FROM "InviteInvitee"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{}))
+				// 5. Guest RSVPs
+				mock.ExpectQuery(`SELECT \* FROM "GuestRSVP"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{}))
+				// 6. Sign-up sheets
+				mock.ExpectQuery(`SELECT \* FROM "SignupSheet"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{}))
+				mock.ExpectQuery(`SELECT it\.\* FROM "SignupItem"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{}))
+				mock.ExpectQuery(`SELECT c\.\*, .* FROM "SignupClaim"`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{}))
+				// 7. Feed
 				mock.ExpectQuery(`SELECT f\.\*, .* FROM "EventFeedItem"`).
 					WithArgs("invite-1").
 					WillReturnRows(sqlmock.NewRows([]string{}))
-				// 5. Media
+				// 8. Media
 				mock.ExpectQuery(`SELECT \* FROM "MediaItem"`).
 					WithArgs("invite-1").
 					WillReturnRows(sqlmock.NewRows([]string{}))
@@ -250,19 +561,57 @@ func TestGetInvite(t *testing.T) {
 		},
 		{
 			name:     "Not Found",
+			userID:   "user-123",
 			inviteID: "invite-999",
 			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+					WithArgs("invite-999", "user-123").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "Removed member has no access",
+			userID:   "user-456",
+			inviteID: "invite-1",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-123", false)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "Deleted during the lookup",
+			userID:   "user-123",
+			inviteID: "invite-1",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
 				mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
-					WithArgs("invite-999").
-					WillReturnError(errors.New("no rows"))
+					WithArgs("invite-1").
+					WillReturnError(sql.ErrNoRows)
 			},
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:     "Details lookup fails",
+			userID:   "user-123",
+			inviteID: "invite-1",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+				mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+					WithArgs("invite-1").
+					WillReturnError(errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			req, _ := http.NewRequest("GET", "/invites/"+tt.inviteID, nil)
+			if tt.userID != "" {
+				ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+				req = req.WithContext(ctx)
+			}
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("id", tt.inviteID)
@@ -288,7 +637,7 @@ func TestDeleteInvite(t *testing.T) {
 	defer mockDB.Close()
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	repo := repository.NewInviteRepository(sqlxDB)
-	handler := NewInvitesHandler(repo)
+	handler := NewInvitesHandler(repo, repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
 
 	tests := []struct {
 		name           string
@@ -352,3 +701,243 @@ func TestDeleteInvite(t *testing.T) {
 		})
 	}
 }
+
+func TestAddInvitees(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	repo := repository.NewInviteRepository(sqlxDB)
+	handler := NewInvitesHandler(repo, repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Host adds invitee",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"invitees": []map[string]interface{}{{"email": "friend@example.com"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "InviteInvitee"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", nil, "friend@example.com", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Unknown user",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"invitees": []map[string]interface{}{{"userId": "user-missing"}, {"userId": "user-456"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "User"`).
+					WithArgs(`{"user-missing","user-456"}`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Member cannot add invitees",
+			userID: "user-456",
+			body: map[string]interface{}{
+				"invitees": []map[string]interface{}{{"email": "friend@example.com"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-123", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/invites/invite-1/invitees", bytes.NewBuffer(body))
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.AddInvitees).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+// expectInviteAccess stubs the access policy lookup for a user on an invite
+func expectInviteAccess(mock sqlmock.Sqlmock, inviteID, userID, senderID string, isActiveMember bool) {
+	rows := sqlmock.NewRows([]string{"inviteId", "senderId", "circleId", "isActiveMember", "isInvitee"}).
+		AddRow(inviteID, senderID, "circle-1", isActiveMember, false)
+	mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+		WithArgs(inviteID, userID).
+		WillReturnRows(rows)
+}
+
+func TestDuplicateInvite(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	repo := repository.NewInviteRepository(sqlxDB)
+	handler := NewInvitesHandler(repo, repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
+
+	eventDate := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Minute)
+	expectDetails := func(circleID interface{}) {
+		mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "senderId", "circleId", "vaultPolicy", "vaultUnlockAfterHours"}).
+				AddRow("invite-1", "Potluck", "user-123", circleID, "DELAY", 24))
+		mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+			WithArgs("user-123").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("user-123", "Sender"))
+		if circleID != nil {
+			mock.ExpectQuery(`SELECT \* FROM "Circle" WHERE id = \$1`).
+				WithArgs(circleID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "ownerId"}).AddRow(circleID, "Friends", "user-123"))
+			mock.ExpectQuery(`FROM "CircleMember" cm`).
+				WithArgs(circleID).
+				WillReturnRows(sqlmock.NewRows([]string{}))
+		}
+		mock.ExpectQuery(`SELECT r\.\*, .* FROM "RSVP"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{}))
+		mock.ExpectQuery(`SELECT inv\.\*, .* FROM "InviteInvitee"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{}))
+		mock.ExpectQuery(`SELECT \* FROM "GuestRSVP"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{}))
+		mock.ExpectQuery(`SELECT \* FROM "SignupSheet"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "title", "createdById"}).
+				AddRow("signup-1", "invite-1", "Food", "user-123"))
+		mock.ExpectQuery(`SELECT it\.\* FROM "SignupItem"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "sheetId", "name", "quantity", "position"}).
+				AddRow("item-1", "signup-1", "Salad", 2, 0))
+		mock.ExpectQuery(`SELECT c\.\*, .* FROM "SignupClaim"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{}))
+		mock.ExpectQuery(`SELECT f\.\*, .* FROM "EventFeedItem"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{}))
+		mock.ExpectQuery(`SELECT \* FROM "MediaItem"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{}))
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Copies details only",
+			userID: "user-123",
+			body:   map[string]interface{}{"eventDate": eventDate},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+				expectDetails(nil)
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WithArgs(sqlmock.AnyArg(), "Potluck", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), false, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, "DELAY", 24, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Copies sign-up sheets",
+			userID: "user-123",
+			body:   map[string]interface{}{"eventDate": eventDate, "includeSignupSheets": true},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+				expectDetails(nil)
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "SignupSheet"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "Food", nil, "user-123", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "SignupItem"`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "Salad", 2, 0, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Host left the circle",
+			userID: "user-123",
+			body:   map[string]interface{}{"eventDate": eventDate},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+				expectDetails("circle-1")
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "CircleMember"`).
+					WithArgs("circle-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Past date",
+			userID: "user-123",
+			body:   map[string]interface{}{"eventDate": time.Now().Add(-time.Hour)},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Guest cannot duplicate",
+			userID: "user-456",
+			body:   map[string]interface{}{"eventDate": eventDate},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-123", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/invites/invite-1/duplicate", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.DuplicateInvite).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
+	"privo-club-backend/internal/access"
 	"privo-club-backend/internal/api"
 	"privo-club-backend/internal/auth"
 	"privo-club-backend/internal/models"
@@ -17,18 +20,27 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+// uploadDir is where media files are stored, one directory per invite
+const uploadDir = "uploads"
+
 type MediaHandler struct {
-	Repo repository.MediaRepository
+	Repo   repository.MediaRepository
+	Access *access.Policy
 }
 
-func NewMediaHandler(repo repository.MediaRepository) *MediaHandler {
-	return &MediaHandler{Repo: repo}
+func NewMediaHandler(repo repository.MediaRepository, policy *access.Policy) *MediaHandler {
+	return &MediaHandler{Repo: repo, Access: policy}
 }
 
 func (h *MediaHandler) RegisterRoutes(r chi.Router) {
 	r.Method("POST", "/", api.Handler(h.UploadMedia))
 }
 
+// RegisterFileRoutes serves the stored files, mounted at /uploads
+func (h *MediaHandler) RegisterFileRoutes(r chi.Router) {
+	r.Method("GET", "/{inviteId}/{filename}", api.Handler(h.ServeUpload))
+}
+
 func (h *MediaHandler) UploadMedia(w http.ResponseWriter, r *http.Request) error {
 
 	userID, ok := auth.UserIDFromContext(r.Context())
@@ -56,9 +68,13 @@ func (h *MediaHandler) UploadMedia(w http.ResponseWriter, r *http.Request) error
 		return api.ErrBadRequest("Invite ID is required")
 	}
 
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
 	// Create uploads directory if not exists
-	uploadDir := filepath.Join(".", "uploads", inviteID)
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
+	inviteDir := filepath.Join(".", uploadDir, inviteID)
+	if err := os.MkdirAll(inviteDir, os.ModePerm); err != nil {
 
 		return api.ErrInternal(err)
 	}
@@ -66,7 +82,7 @@ func (h *MediaHandler) UploadMedia(w http.ResponseWriter, r *http.Request) error
 	// Generate filename
 	ext := filepath.Ext(header.Filename)
 	filename := utils.GenerateID("media") + ext
-	filePath := filepath.Join(uploadDir, filename)
+	filePath := filepath.Join(inviteDir, filename)
 
 	// Save file
 	dst, err := os.Create(filePath)
@@ -102,3 +118,36 @@ func (h *MediaHandler) UploadMedia(w http.ResponseWriter, r *http.Request) error
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(media)
 }
+
+// ServeUpload serves a stored media file. Only files recorded as media are
+// served, to people who can view the invite, and while the vault is sealed
+// only to the person who uploaded them.
+func (h *MediaHandler) ServeUpload(w http.ResponseWriter, r *http.Request) error {
+	inviteID := chi.URLParam(r, "inviteId")
+	filename := chi.URLParam(r, "filename")
+
+	media, err := h.Repo.GetMediaByURL(r.Context(), "/uploads/"+inviteID+"/"+filename)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("File not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	userID, _ := auth.UserIDFromContext(r.Context())
+	if media.IsVaultUnlocked {
+		if _, err := h.Access.RequireView(r.Context(), media.InviteID, userID); err != nil {
+			return err
+		}
+		w.Header().Set("Cache-Control", "private")
+	} else {
+		if userID != media.UserID {
+			return api.ErrForbidden("This memory is sealed until the vault unlocks")
+		}
+		// Keep shared caches from handing a sealed file to someone else
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+
+	http.ServeFile(w, r, filepath.Join(".", uploadDir, inviteID, filename))
+	return nil
+}
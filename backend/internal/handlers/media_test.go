@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeUpload(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewMediaHandler(repository.NewMediaRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	columns := []string{"id", "inviteId", "userId", "url", "type", "isVaultUnlocked"}
+	url := "/uploads/invite-1/media-1.jpg"
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockBehavior   func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Sealed for anonymous requests",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT m\.\*, i."isVaultUnlocked" FROM "MediaItem" m`).
+					WithArgs(url).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("media-1", "invite-1", "user-1", url, "IMAGE", false))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Sealed for other attendees",
+			userID: "user-2",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT m\.\*, i."isVaultUnlocked" FROM "MediaItem" m`).
+					WithArgs(url).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("media-1", "invite-1", "user-1", url, "IMAGE", false))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			// The file itself does not exist in the test, so passing the check ends in a 404 from the file server
+			name:   "Uploader can see their own sealed file",
+			userID: "user-1",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT m\.\*, i."isVaultUnlocked" FROM "MediaItem" m`).
+					WithArgs(url).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("media-1", "invite-1", "user-1", url, "IMAGE", false))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Unlocked for attendees",
+			userID: "user-2",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT m\.\*, i."isVaultUnlocked" FROM "MediaItem" m`).
+					WithArgs(url).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("media-1", "invite-1", "user-1", url, "IMAGE", true))
+				expectInviteAccess(mock, "invite-1", "user-2", "user-host", true)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found",
+		},
+		{
+			name:   "Unlocked but not invited",
+			userID: "user-3",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT m\.\*, i."isVaultUnlocked" FROM "MediaItem" m`).
+					WithArgs(url).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("media-1", "invite-1", "user-1", url, "IMAGE", true))
+				expectInviteAccess(mock, "invite-1", "user-3", "user-host", false)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Invite not found",
+		},
+		{
+			name: "Unlocked for anonymous requests",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT m\.\*, i."isVaultUnlocked" FROM "MediaItem" m`).
+					WithArgs(url).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("media-1", "invite-1", "user-1", url, "IMAGE", true))
+				expectInviteAccess(mock, "invite-1", "", "user-host", false)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Invite not found",
+		},
+		{
+			name: "Unknown files are not served",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT m\.\*, i."isVaultUnlocked" FROM "MediaItem" m`).
+					WithArgs(url).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", url, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("inviteId", "invite-1")
+			rctx.URLParams.Add("filename", "media-1.jpg")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			if tt.userID != "" {
+				ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			}
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.ServeUpload).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
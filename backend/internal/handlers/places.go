@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
+	"privo-club-backend/internal/models"
+)
+
+const (
+	defaultNearbyRadiusKm = 25.0
+	maxNearbyRadiusKm     = 200.0
+	geocodeTimeout        = 5 * time.Second
+)
+
+// ListNearbyInvites returns the user's upcoming invites within radiusKm of
+// lat/lng, closest first.
+func (h *InvitesHandler) ListNearbyInvites(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	position, hasPosition, err := positionFromQuery(r)
+	if err != nil {
+		return err
+	}
+	if !hasPosition {
+		return api.ErrBadRequest("lat and lng are required")
+	}
+
+	radius := defaultNearbyRadiusKm
+	if v := r.URL.Query().Get("radiusKm"); v != "" {
+		radius, err = strconv.ParseFloat(v, 64)
+		if err != nil || radius <= 0 || radius > maxNearbyRadiusKm {
+			return api.ErrBadRequest("radiusKm must be between 0 and 200")
+		}
+	}
+
+	invites, err := h.Repo.ListNearbyInvites(r.Context(), userID, position, radius)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	nearby := make([]models.NearbyInvite, 0, len(invites))
+	for _, invite := range invites {
+		if d := distanceTo(position, invite.Place); d != nil {
+			nearby = append(nearby, models.NearbyInvite{Invite: invite, Distance: *d})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(nearby)
+}
+
+// resolvePlace validates client-supplied coordinates, or fills them in from
+// the map link and then the geocoder. Geocoding failures never block saving.
+func resolvePlace(ctx context.Context, geocoder geo.Geocoder, location, mapLink *string, place models.Place) (models.Place, error) {
+	if place.Latitude != nil || place.Longitude != nil {
+		if !place.HasCoordinates() || !(geo.Point{Lat: *place.Latitude, Lng: *place.Longitude}).Valid() {
+			return place, api.ErrBadRequest("Latitude and longitude must both be set and in range")
+		}
+		return place, nil
+	}
+
+	if mapLink != nil {
+		if p, ok := geo.ParseMapLink(*mapLink); ok {
+			place.Latitude, place.Longitude = &p.Lat, &p.Lng
+			return place, nil
+		}
+	}
+
+	if location == nil || strings.TrimSpace(*location) == "" {
+		return place, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, geocodeTimeout)
+	defer cancel()
+	found, err := geocoder.Geocode(ctx, strings.TrimSpace(*location))
+	if err != nil {
+		if !errors.Is(err, geo.ErrNoMatch) {
+			slog.Warn("Geocoding failed", "error", err)
+		}
+		return place, nil
+	}
+	return mergePlace(place, *found), nil
+}
+
+// mergePlace fills the fields the client left empty from the geocoder result
+func mergePlace(dst, src models.Place) models.Place {
+	for _, f := range []struct{ dst, src **string }{
+		{&dst.PlaceName, &src.PlaceName},
+		{&dst.AddressLine, &src.AddressLine},
+		{&dst.City, &src.City},
+		{&dst.Region, &src.Region},
+		{&dst.PostalCode, &src.PostalCode},
+		{&dst.Country, &src.Country},
+	} {
+		if *f.dst == nil {
+			*f.dst = *f.src
+		}
+	}
+	dst.Latitude, dst.Longitude = src.Latitude, src.Longitude
+	return dst
+}
+
+// positionFromQuery reads the optional lat/lng query parameters
+func positionFromQuery(r *http.Request) (geo.Point, bool, error) {
+	lat, lng := r.URL.Query().Get("lat"), r.URL.Query().Get("lng")
+	if lat == "" && lng == "" {
+		return geo.Point{}, false, nil
+	}
+
+	la, errLat := strconv.ParseFloat(lat, 64)
+	ln, errLng := strconv.ParseFloat(lng, 64)
+	p := geo.Point{Lat: la, Lng: ln}
+	if errLat != nil || errLng != nil || !p.Valid() {
+		return geo.Point{}, false, api.ErrBadRequest("Invalid lat/lng")
+	}
+	return p, true, nil
+}
+
+// distanceTo returns nil when the place has no coordinates
+func distanceTo(from geo.Point, place models.Place) *models.Distance {
+	if !place.HasCoordinates() {
+		return nil
+	}
+	km := geo.DistanceKm(from, geo.Point{Lat: *place.Latitude, Lng: *place.Longitude})
+	return &models.Distance{Km: km, Text: geo.FormatDistance(km)}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListNearbyInvites(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewInvitesHandler(repository.NewInviteRepository(sqlxDB), repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
+
+	eventDate := time.Now().Add(48 * time.Hour)
+	tests := []struct {
+		name           string
+		query          string
+		mockBehavior   func()
+		expectedStatus int
+		expectedIDs    []string
+	}{
+		{
+			name:  "Closest first within the radius",
+			query: "?lat=40.7580&lng=-73.9855&radiusKm=5",
+			mockBehavior: func() {
+				// The database drops the box corners and orders by distance
+				rows := sqlmock.NewRows([]string{"id", "title", "eventDate", "senderId", "latitude", "longitude"}).
+					AddRow("invite-times", "Show", eventDate, "user-1", 40.7590, -73.9845). // ~0.1km
+					AddRow("invite-park", "Picnic", eventDate, "user-1", 40.7829, -73.9654) // ~3.2km
+				mock.ExpectQuery(`SELECT i\.\* FROM "Invite" i .* asin\(.*\) <= \$8 .* ORDER BY \(2 \* 6371\.0 \* asin`).
+					WithArgs("user-123", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 40.7580, -73.9855, 5.0).
+					WillReturnRows(rows)
+			},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"invite-times", "invite-park"},
+		},
+		{
+			name:           "Missing position",
+			query:          "",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Radius too large",
+			query:          "?lat=40.7580&lng=-73.9855&radiusKm=5000",
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/invites/nearby"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, "user-123"))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.ListNearbyInvites).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedIDs != nil {
+				var nearby []models.NearbyInvite
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &nearby))
+				var ids []string
+				for _, n := range nearby {
+					ids = append(ids, n.ID)
+				}
+				assert.Equal(t, tt.expectedIDs, ids)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxDateOptions = 10
+
+type PollsHandler struct {
+	Repo        repository.PollRepository
+	InvitesRepo repository.InviteRepository
+	Access      *access.Policy
+}
+
+func NewPollsHandler(repo repository.PollRepository, invitesRepo repository.InviteRepository, policy *access.Policy) *PollsHandler {
+	return &PollsHandler{Repo: repo, InvitesRepo: invitesRepo, Access: policy}
+}
+
+// RegisterRoutes mounts the date poll under /api/invites
+func (h *PollsHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/poll", api.Handler(h.GetPoll))
+	r.Method("POST", "/{id}/poll/options", api.Handler(h.AddOptions))
+	r.Method("PUT", "/{id}/poll/votes", api.Handler(h.Vote))
+	r.Method("POST", "/{id}/poll/finalize", api.Handler(h.Finalize))
+}
+
+func (h *PollsHandler) GetPoll(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	options, err := h.Repo.ListDatePoll(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(options)
+}
+
+func (h *PollsHandler) AddOptions(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can propose dates"); err != nil {
+		return err
+	}
+	if err := h.requireOpenPoll(r, inviteID); err != nil {
+		return err
+	}
+
+	var req models.AddDateOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+
+	options, err := buildDateOptions(inviteID, req.DateOptions)
+	if err != nil {
+		return err
+	}
+	if len(options) == 0 {
+		return api.ErrBadRequest("At least one date option is required")
+	}
+
+	if err := h.Repo.AddDateOptions(r.Context(), options); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *PollsHandler) Vote(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+	if err := h.requireOpenPoll(r, inviteID); err != nil {
+		return err
+	}
+
+	var req models.DatePollVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	if len(req.Votes) == 0 {
+		return api.ErrBadRequest("At least one vote is required")
+	}
+
+	votes := make([]models.DatePollVote, 0, len(req.Votes))
+	for _, v := range req.Votes {
+		switch v.Vote {
+		case "YES", "IF_NEEDED", "NO":
+		default:
+			return api.ErrBadRequest("Vote must be YES, IF_NEEDED or NO")
+		}
+		if v.OptionID == "" {
+			return api.ErrBadRequest("Option ID required")
+		}
+		votes = append(votes, models.DatePollVote{
+			ID:        utils.GenerateID("vote"),
+			OptionID:  v.OptionID,
+			UserID:    userID,
+			Vote:      v.Vote,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	}
+
+	err := h.Repo.UpsertVotes(r.Context(), inviteID, votes)
+	if errors.Is(err, repository.ErrUnknownOption) {
+		return api.NewAPIError(http.StatusBadRequest, "Invalid date option", err)
+	}
+	if errors.Is(err, repository.ErrPollClosed) {
+		return api.NewAPIError(http.StatusConflict, "The date for this invite has already been set", nil)
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// Finalize picks the winning option, sets eventDate and seeds RSVPs from the votes.
+func (h *PollsHandler) Finalize(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can finalize the date"); err != nil {
+		return err
+	}
+	if err := h.requireOpenPoll(r, inviteID); err != nil {
+		return err
+	}
+
+	var req models.FinalizeDatePollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+
+	option, err := h.Repo.GetDateOption(r.Context(), inviteID, req.OptionID)
+	if err != nil {
+		return api.ErrNotFound("Date option not found")
+	}
+	if option.StartsAt.Before(time.Now()) {
+		return api.ErrBadRequest("That date has already passed")
+	}
+
+	if err := h.Repo.FinalizeDatePoll(r.Context(), inviteID, option); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "eventDate": option.StartsAt})
+}
+
+func (h *PollsHandler) requireOpenPoll(r *http.Request, inviteID string) error {
+	invite, err := h.InvitesRepo.GetInviteByID(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrNotFound("Invite not found")
+	}
+	if !invite.IsDateTBD {
+		return api.NewAPIError(http.StatusConflict, "The date for this invite has already been set", nil)
+	}
+	return nil
+}
+
+// buildDateOptions validates candidate times and returns them de-duplicated and sorted.
+func buildDateOptions(inviteID string, times []time.Time) ([]models.DatePollOption, error) {
+	if len(times) > maxDateOptions {
+		return nil, api.ErrBadRequest("Too many date options")
+	}
+
+	sorted := append([]time.Time(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	options := []models.DatePollOption{}
+	for i, t := range sorted {
+		if t.IsZero() {
+			return nil, api.ErrBadRequest("Invalid date option")
+		}
+		if t.Before(time.Now().Add(-5 * time.Minute)) {
+			return nil, api.ErrBadRequest("Date options cannot be in the past")
+		}
+		if i > 0 && t.Equal(sorted[i-1]) {
+			continue
+		}
+		options = append(options, models.DatePollOption{
+			ID:        utils.GenerateID("dateopt"),
+			InviteID:  inviteID,
+			StartsAt:  t,
+			CreatedAt: time.Now(),
+		})
+	}
+	return options, nil
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectInviteTBD(mock sqlmock.Sqlmock, inviteID string, isDateTBD bool) {
+	rows := sqlmock.NewRows([]string{"id", "title", "senderId", "isDateTBD"}).
+		AddRow(inviteID, "Game night", "user-host", isDateTBD)
+	mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+		WithArgs(inviteID).
+		WillReturnRows(rows)
+}
+
+func expectPollLock(mock sqlmock.Sqlmock, inviteID string, isOpen bool) {
+	mock.ExpectQuery(`SELECT "isDateTBD" FROM "Invite" WHERE id = \$1 FOR SHARE`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{"isDateTBD"}).AddRow(isOpen))
+}
+
+func TestVoteOnDatePoll(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewPollsHandler(repository.NewPollRepository(sqlxDB), repository.NewInviteRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Success",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"votes": []map[string]string{{"optionId": "opt-1", "vote": "IF_NEEDED"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectInviteTBD(mock, "invite-1", true)
+				mock.ExpectBegin()
+				expectPollLock(mock, "invite-1", true)
+				mock.ExpectExec(`INSERT INTO "DatePollVote"`).
+					WithArgs(sqlmock.AnyArg(), "opt-1", "user-123", "IF_NEEDED", sqlmock.AnyArg(), "invite-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Option from another invite",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"votes": []map[string]string{{"optionId": "opt-other", "vote": "YES"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectInviteTBD(mock, "invite-1", true)
+				mock.ExpectBegin()
+				expectPollLock(mock, "invite-1", true)
+				mock.ExpectExec(`INSERT INTO "DatePollVote"`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Finalized while voting",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"votes": []map[string]string{{"optionId": "opt-1", "vote": "YES"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectInviteTBD(mock, "invite-1", true)
+				mock.ExpectBegin()
+				expectPollLock(mock, "invite-1", false)
+				mock.ExpectRollback()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Database error",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"votes": []map[string]string{{"optionId": "opt-1", "vote": "YES"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectInviteTBD(mock, "invite-1", true)
+				mock.ExpectBegin()
+				expectPollLock(mock, "invite-1", true)
+				mock.ExpectExec(`INSERT INTO "DatePollVote"`).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "Invalid vote",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"votes": []map[string]string{{"optionId": "opt-1", "vote": "PERHAPS"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectInviteTBD(mock, "invite-1", true)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Date already set",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"votes": []map[string]string{{"optionId": "opt-1", "vote": "YES"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectInviteTBD(mock, "invite-1", false)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Not invited",
+			userID: "user-456",
+			body: map[string]interface{}{
+				"votes": []map[string]string{{"optionId": "opt-1", "vote": "YES"}},
+			},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-host", false)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("PUT", "/invites/invite-1/poll/votes", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.Vote).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestFinalizeDatePoll(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewPollsHandler(repository.NewPollRepository(sqlxDB), repository.NewInviteRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	startsAt := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Success",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				expectInviteTBD(mock, "invite-1", true)
+				mock.ExpectQuery(`SELECT \* FROM "DatePollOption" WHERE id = \$1`).
+					WithArgs("opt-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "startsAt", "createdAt"}).
+						AddRow("opt-1", "invite-1", startsAt, time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "Invite"`).
+					WithArgs("invite-1", startsAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT \* FROM "DatePollVote"`).
+					WithArgs("opt-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "optionId", "userId", "vote", "createdAt", "updatedAt"}).
+						AddRow("vote-1", "opt-1", "user-123", "YES", time.Now(), time.Now()).
+						AddRow("vote-2", "opt-1", "user-456", "IF_NEEDED", time.Now(), time.Now()))
+				mock.ExpectExec(`INSERT INTO "RSVP"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "YES", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO "RSVP"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-456", "MAYBE", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Not the host",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Option already passed",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				expectInviteTBD(mock, "invite-1", true)
+				mock.ExpectQuery(`SELECT \* FROM "DatePollOption" WHERE id = \$1`).
+					WithArgs("opt-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "startsAt", "createdAt"}).
+						AddRow("opt-1", "invite-1", time.Now().Add(-time.Hour), time.Now()))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Unknown option",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				expectInviteTBD(mock, "invite-1", true)
+				mock.ExpectQuery(`SELECT \* FROM "DatePollOption" WHERE id = \$1`).
+					WithArgs("opt-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"optionId": "opt-1"})
+			req, _ := http.NewRequest("POST", "/invites/invite-1/poll/finalize", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.Finalize).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAddDateOptions(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewPollsHandler(repository.NewPollRepository(sqlxDB), repository.NewInviteRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	earlier := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+	expectInviteTBD(mock, "invite-1", true)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "DatePollOption"`).
+		WithArgs(sqlmock.AnyArg(), "invite-1", earlier, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// The placeholder follows the earliest option
+	mock.ExpectExec(`UPDATE "Invite" i\s+SET "eventDate" = o.first`).
+		WithArgs("invite-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	body, _ := json.Marshal(map[string][]time.Time{"dateOptions": {earlier}})
+	req, _ := http.NewRequest("POST", "/invites/invite-1/poll/options", bytes.NewBuffer(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "invite-1")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	req = req.WithContext(context.WithValue(ctx, auth.UserIDKey, "user-host"))
+
+	rr := httptest.NewRecorder()
+	api.Handler(handler.AddOptions).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/recap"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Recap pages are static; they only need their own styles and the vault's images
+const recapCSP = "default-src 'none'; img-src 'self'; style-src 'unsafe-inline'"
+
+type RecapHandler struct {
+	Repo   repository.RecapRepository
+	Access *access.Policy
+}
+
+func NewRecapHandler(repo repository.RecapRepository, policy *access.Policy) *RecapHandler {
+	return &RecapHandler{Repo: repo, Access: policy}
+}
+
+// RegisterRoutes mounts the attendee and host endpoints under /api/invites
+func (h *RecapHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/recap", api.Handler(h.GetRecap))
+	r.Method("POST", "/{id}/recap/share", api.Handler(h.ShareRecap))
+	r.Method("DELETE", "/{id}/recap/share", api.Handler(h.UnshareRecap))
+}
+
+// RegisterPublicRoutes mounts the session-less recap page
+func (h *RecapHandler) RegisterPublicRoutes(r chi.Router) {
+	r.Method("GET", "/recaps/{token}", api.Handler(h.GetPublicRecap))
+}
+
+// RegisterPublicMediaRoutes mounts the images of shared recaps. They are kept
+// apart from RegisterPublicRoutes so a page's images are not rate limited.
+func (h *RecapHandler) RegisterPublicMediaRoutes(r chi.Router) {
+	r.Method("GET", "/recaps/{token}/media/{filename}", api.Handler(h.GetPublicRecapMedia))
+}
+
+// GetRecap returns the recap manifest as JSON, or the page with ?format=html.
+// Recaps are generated once the vault unlocks and are for the host and the
+// people who came.
+func (h *RecapHandler) GetRecap(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	a, err := h.Access.RequireView(r.Context(), inviteID, userID)
+	if err != nil {
+		return err
+	}
+	if !a.IsHost() {
+		attended, err := h.Repo.IsAttendee(r.Context(), inviteID, userID)
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+		if !attended {
+			return api.ErrForbidden("Only people who came can see the recap")
+		}
+	}
+
+	stored, err := h.Repo.GetRecap(r.Context(), inviteID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("The recap is ready once the vault unlocks")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	if !a.IsHost() {
+		stored.PublicToken = nil
+	}
+	return writeRecap(w, r, stored)
+}
+
+// ShareRecap creates a public link to the recap, replacing any previous one
+func (h *RecapHandler) ShareRecap(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can share the recap"); err != nil {
+		return err
+	}
+
+	token := utils.GenerateRandomString(32)
+	err := h.Repo.SetPublicToken(r.Context(), inviteID, &token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("The recap is ready once the vault unlocks")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(map[string]string{"publicToken": token})
+}
+
+func (h *RecapHandler) UnshareRecap(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can share the recap"); err != nil {
+		return err
+	}
+
+	err := h.Repo.SetPublicToken(r.Context(), inviteID, nil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Recap not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// GetPublicRecap serves the recap page through its public link, or the
+// manifest with ?format=json. Time-capsule messages are left out. Visitors
+// have no session for /uploads, so the images point at GetPublicRecapMedia
+// instead.
+func (h *RecapHandler) GetPublicRecap(w http.ResponseWriter, r *http.Request) error {
+	token := chi.URLParam(r, "token")
+	stored, err := h.Repo.GetRecapByToken(r.Context(), token)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Unknown and revoked links look the same
+		return api.ErrNotFound("Recap not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	var manifest models.RecapManifest
+	if err := json.Unmarshal(stored.Manifest, &manifest); err != nil {
+		return api.ErrInternal(err)
+	}
+	manifest = recap.Public(manifest)
+	for i, item := range manifest.Media {
+		manifest.Media[i].URL = "/api/public/recaps/" + url.PathEscape(token) + "/media/" + path.Base(item.URL)
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "html":
+		page, err := recap.Render(manifest)
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+		return writeRecapHTML(w, page)
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(models.RecapView{Manifest: manifest, GeneratedAt: stored.GeneratedAt})
+	default:
+		return api.ErrBadRequest("Format must be json or html")
+	}
+}
+
+// GetPublicRecapMedia serves an image of a shared recap. Only images the recap
+// shows are served, and only while its link is active.
+func (h *RecapHandler) GetPublicRecapMedia(w http.ResponseWriter, r *http.Request) error {
+	stored, err := h.Repo.GetRecapByToken(r.Context(), chi.URLParam(r, "token"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("File not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	var manifest models.RecapManifest
+	if err := json.Unmarshal(stored.Manifest, &manifest); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	filename := chi.URLParam(r, "filename")
+	for _, item := range manifest.Media {
+		if item.URL == "/uploads/"+stored.InviteID+"/"+filename {
+			http.ServeFile(w, r, filepath.Join(".", uploadDir, stored.InviteID, filename))
+			return nil
+		}
+	}
+	return api.ErrNotFound("File not found")
+}
+
+func writeRecap(w http.ResponseWriter, r *http.Request, stored *models.InviteRecap) error {
+	switch r.URL.Query().Get("format") {
+	case "html":
+		if stored.StaleAt == nil {
+			return writeRecapHTML(w, stored.HTML)
+		}
+		// The page may still quote a post that changed since; the manifest
+		// already left it out
+		var manifest models.RecapManifest
+		if err := json.Unmarshal(stored.Manifest, &manifest); err != nil {
+			return api.ErrInternal(err)
+		}
+		page, err := recap.Render(manifest)
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+		return writeRecapHTML(w, page)
+	case "", "json":
+	default:
+		return api.ErrBadRequest("Format must be json or html")
+	}
+
+	view := models.RecapView{PublicToken: stored.PublicToken, GeneratedAt: stored.GeneratedAt}
+	if err := json.Unmarshal(stored.Manifest, &view.Manifest); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(view)
+}
+
+func writeRecapHTML(w http.ResponseWriter, page string) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", recapCSP)
+	_, err := w.Write([]byte(page))
+	return err
+}
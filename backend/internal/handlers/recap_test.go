@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var recapColumns = []string{"inviteId", "manifest", "html", "publicToken", "generatedAt", "staleAt"}
+
+const publicRecapManifest = `{"inviteId":"invite-1","title":"Picnic","media":[{"url":"/uploads/invite-1/media-1.jpg","type":"IMAGE","author":"Ann"}],"highlights":[{"type":"UPDATE","content":"Bring a blanket","author":"Ann"},{"type":"CAPSULE","content":"Note to future us","author":"Ann"}]}`
+
+func TestGetRecap(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewRecapHandler(repository.NewRecapRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	manifest := `{"inviteId":"invite-1","title":"Dinner","headcount":4}`
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		userID         string
+		query          string
+		mockBehavior   func()
+		expectedStatus int
+		expectedToken  bool
+		expectedPage   string
+	}{
+		{
+			name:   "Attendee sees the manifest",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM "CheckIn"`).
+					WithArgs("invite-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "inviteId" = \$1`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows(recapColumns).AddRow("invite-1", []byte(manifest), "<html></html>", "secret", now, nil))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Host sees the public token",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "inviteId" = \$1`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows(recapColumns).AddRow("invite-1", []byte(manifest), "<html></html>", "secret", now, nil))
+			},
+			expectedStatus: http.StatusOK,
+			expectedToken:  true,
+		},
+		{
+			name:   "HTML page",
+			userID: "user-123",
+			query:  "?format=html",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM "CheckIn"`).
+					WithArgs("invite-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "inviteId" = \$1`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows(recapColumns).AddRow("invite-1", []byte(manifest), "<html></html>", nil, now, nil))
+			},
+			expectedStatus: http.StatusOK,
+			expectedPage:   "<html></html>",
+		},
+		{
+			name:   "Stale page is rendered from the manifest",
+			userID: "user-123",
+			query:  "?format=html",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM "CheckIn"`).
+					WithArgs("invite-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "inviteId" = \$1`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows(recapColumns).AddRow("invite-1", []byte(manifest), "<html>Edited away</html>", nil, now, now))
+			},
+			expectedStatus: http.StatusOK,
+			expectedPage:   "<h1>Dinner</h1>",
+		},
+		{
+			name:   "Not generated yet",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM "CheckIn"`).
+					WithArgs("invite-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "inviteId" = \$1`).
+					WithArgs("invite-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Members who did not come cannot see it",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM "CheckIn"`).
+					WithArgs("invite-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Outsiders cannot see it",
+			userID: "user-999",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-999", "user-host", false)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/invites/invite-1/recap"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.GetRecap).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if rr.Code == http.StatusOK && tt.query == "" {
+				var view models.RecapView
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &view))
+				assert.Equal(t, "Dinner", view.Manifest.Title)
+				assert.Equal(t, tt.expectedToken, view.PublicToken != nil)
+			}
+			if tt.query == "?format=html" {
+				assert.Contains(t, rr.Body.String(), tt.expectedPage)
+				assert.NotContains(t, rr.Body.String(), "Edited away")
+				assert.Contains(t, rr.Header().Get("Content-Type"), "text/html")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestShareRecap(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewRecapHandler(repository.NewRecapRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Host shares",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				mock.ExpectExec(`UPDATE "InviteRecap" SET "publicToken" = \$2`).
+					WithArgs("invite-1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "No recap yet",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				mock.ExpectExec(`UPDATE "InviteRecap" SET "publicToken" = \$2`).
+					WithArgs("invite-1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Guests cannot share",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/invites/invite-1/recap/share", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.ShareRecap).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetPublicRecap(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewRecapHandler(repository.NewRecapRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	tests := []struct {
+		name           string
+		token          string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:  "Serves the page",
+			token: "secret",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "publicToken" = \$1`).
+					WithArgs("secret").
+					WillReturnRows(sqlmock.NewRows(recapColumns).AddRow("invite-1", []byte(publicRecapManifest), "<html>recap</html>", "secret", time.Now(), nil))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Revoked link",
+			token: "old",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "publicToken" = \$1`).
+					WithArgs("old").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/public/recaps/"+tt.token, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("token", tt.token)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.GetPublicRecap).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if rr.Code == http.StatusOK {
+				// Images go through the link, visitors have no session for /uploads
+				assert.Contains(t, rr.Body.String(), `<h1>Picnic</h1>`)
+				assert.Contains(t, rr.Body.String(), `src="/api/public/recaps/secret/media/media-1.jpg"`)
+				assert.NotContains(t, rr.Body.String(), "/uploads/")
+				// Time capsules are for the people who came
+				assert.Contains(t, rr.Body.String(), "Bring a blanket")
+				assert.NotContains(t, rr.Body.String(), "Note to future us")
+				assert.Equal(t, recapCSP, rr.Header().Get("Content-Security-Policy"))
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetPublicRecapMedia(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewRecapHandler(repository.NewRecapRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	expectRecap := func() {
+		mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "publicToken" = \$1`).
+			WithArgs("secret").
+			WillReturnRows(sqlmock.NewRows(recapColumns).AddRow("invite-1", []byte(publicRecapManifest), "", "secret", time.Now(), nil))
+	}
+
+	tests := []struct {
+		name           string
+		filename       string
+		mockBehavior   func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			// The file itself does not exist in the test, so passing the check ends in a 404 from the file server
+			name:           "Image in the recap",
+			filename:       "media-1.jpg",
+			mockBehavior:   expectRecap,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found",
+		},
+		{
+			name:           "Image not in the recap",
+			filename:       "media-2.jpg",
+			mockBehavior:   expectRecap,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "File not found",
+		},
+		{
+			name:     "Revoked link",
+			filename: "media-1.jpg",
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT \* FROM "InviteRecap" WHERE "publicToken" = \$1`).
+					WithArgs("secret").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "File not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/public/recaps/secret/media/"+tt.filename, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("token", "secret")
+			rctx.URLParams.Add("filename", tt.filename)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.GetPublicRecapMedia).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
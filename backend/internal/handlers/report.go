@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/report"
+	"privo-club-backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReportHandler struct {
+	Repo   repository.ReportRepository
+	Access *access.Policy
+}
+
+func NewReportHandler(repo repository.ReportRepository, policy *access.Policy) *ReportHandler {
+	return &ReportHandler{Repo: repo, Access: policy}
+}
+
+func (h *ReportHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/report", api.Handler(h.GetReport))
+}
+
+// GetReport returns the headcount and dietary report as JSON, or as a CSV
+// download with ?format=csv.
+func (h *ReportHandler) GetReport(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can view the guest report"); err != nil {
+		return err
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		return api.ErrBadRequest("Format must be json or csv")
+	}
+
+	invite, err := h.Repo.GetInvite(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrNotFound("Invite not found")
+	}
+	guests, err := h.Repo.ListReportGuests(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	rep := report.Build(invite, guests, time.Now())
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="guest-report-%s.csv"`, inviteID))
+		return writeReportCSV(w, rep)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(rep)
+}
+
+// writeReportCSV writes the totals, the dietary summary and the guest list as
+// three blocks separated by blank lines, which is how caterers read it.
+// Text entered by guests goes through csvSafe.
+func writeReportCSV(w http.ResponseWriter, rep models.HeadcountReport) error {
+	cw := csv.NewWriter(w)
+
+	cw.Write([]string{csvSafe(rep.Title), rep.EventDate.Format("Mon, Jan 2 2006 3:04 PM")})
+	cw.Write([]string{"Status", "Responses", "People"})
+	for _, status := range []string{"YES", "MAYBE", "NO"} {
+		t := rep.Totals[status]
+		cw.Write([]string{status, strconv.Itoa(t.Responses), strconv.Itoa(t.People)})
+	}
+	cw.Write(nil)
+
+	cw.Write([]string{"Dietary category", "Count", "Guests"})
+	for _, d := range rep.Dietary {
+		cw.Write([]string{csvSafe(d.Category), strconv.Itoa(d.Count), csvSafe(strings.Join(d.Names, "; "))})
+	}
+	cw.Write(nil)
+
+	cw.Write([]string{"Name", "Type", "Email", "Status", "Party size", "Dietary", "Dietary categories", "Note", "Responded"})
+	for _, g := range rep.Guests {
+		cw.Write([]string{
+			csvSafe(g.Name), g.Kind, csvSafe(valueOrEmpty(g.Email)), g.Status, strconv.Itoa(g.GuestCount),
+			csvSafe(valueOrEmpty(g.Dietary)), csvSafe(strings.Join(g.DietaryCategories, "; ")), csvSafe(valueOrEmpty(g.Note)),
+			g.RespondedAt.Format("2006-01-02 15:04"),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReport(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewReportHandler(repository.NewReportRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	expectReport := func() {
+		expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+		mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "eventDate", "senderId"}).
+				AddRow("invite-1", "Dinner", time.Now(), "user-host"))
+		mock.ExpectQuery(`SELECT 'MEMBER' AS kind`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"kind", "name", "email", "status", "guestCount", "dietary", "note", "respondedAt"}).
+				AddRow("MEMBER", "Zoe", "zoe@example.com", "YES", 2, "Vegan", nil, time.Now()).
+				AddRow("GUEST", "Adam", "adam@example.com", "MAYBE", 1, "gluten free", "Might be late", time.Now()).
+				AddRow("GUEST", `=HYPERLINK("http://evil.example","x")`, nil, "NO", 1, nil, "+1 can't make it", time.Now()))
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		format         string
+		mockBehavior   func()
+		expectedStatus int
+		check          func(t *testing.T, body string)
+	}{
+		{
+			name:           "JSON",
+			userID:         "user-host",
+			mockBehavior:   expectReport,
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body string) {
+				assert.Contains(t, body, `"YES":{"responses":1,"people":2}`)
+				assert.Contains(t, body, `"category":"Gluten-free"`)
+			},
+		},
+		{
+			name:           "CSV",
+			userID:         "user-host",
+			format:         "csv",
+			mockBehavior:   expectReport,
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, body string) {
+				r := csv.NewReader(strings.NewReader(body))
+				r.FieldsPerRecord = -1
+				records, err := r.ReadAll()
+				assert.NoError(t, err)
+				assert.Contains(t, records, []string{"YES", "1", "2"})
+				assert.Contains(t, records, []string{"Vegan", "1", "Zoe"})
+				assert.Contains(t, body, "Might be late")
+
+				// Formulas typed by guests are neutralised
+				last := records[len(records)-1]
+				assert.Equal(t, `'=HYPERLINK("http://evil.example","x")`, last[0])
+				assert.Equal(t, "'+1 can't make it", last[7])
+			},
+		},
+		{
+			name:   "Guest cannot view",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/invites/invite-1/report?format="+tt.format, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.GetReport).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.check != nil {
+				tt.check(t, rr.Body.String())
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
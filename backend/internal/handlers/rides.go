@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxRideSeats = 20
+
+type RidesHandler struct {
+	Repo   repository.RideRepository
+	Access *access.Policy
+}
+
+func NewRidesHandler(repo repository.RideRepository, policy *access.Policy) *RidesHandler {
+	return &RidesHandler{Repo: repo, Access: policy}
+}
+
+// RegisterRoutes mounts carpool coordination under /api/invites
+func (h *RidesHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/rides", api.Handler(h.GetRides))
+	r.Method("PUT", "/{id}/rides/offer", api.Handler(h.UpsertOffer))
+	r.Method("DELETE", "/{id}/rides/offers/{offerId}", api.Handler(h.DeleteOffer))
+	r.Method("PUT", "/{id}/rides/request", api.Handler(h.UpsertRequest))
+	r.Method("DELETE", "/{id}/rides/request", api.Handler(h.DeleteRequest))
+	r.Method("POST", "/{id}/rides/offers/{offerId}/join", api.Handler(h.JoinOffer))
+	r.Method("DELETE", "/{id}/rides/offers/{offerId}/join", api.Handler(h.LeaveOffer))
+}
+
+func (h *RidesHandler) GetRides(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	offers, err := h.Repo.ListOffers(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	requests, err := h.Repo.ListRequests(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(buildRideBoard(userID, offers, requests))
+}
+
+func (h *RidesHandler) UpsertOffer(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if err := h.requireAttending(r, inviteID, userID); err != nil {
+		return err
+	}
+
+	var req models.RideOfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	req.DepartureArea = strings.TrimSpace(req.DepartureArea)
+	if req.Seats < 1 || req.Seats > maxRideSeats {
+		return api.ErrBadRequest("Invalid number of seats")
+	}
+	if req.DepartureArea == "" || req.DepartureTime.IsZero() {
+		return api.ErrBadRequest("Departure area and time are required")
+	}
+
+	offer := &models.RideOffer{
+		ID:            utils.GenerateID("ride"),
+		InviteID:      inviteID,
+		DriverID:      userID,
+		Seats:         req.Seats,
+		DepartureArea: req.DepartureArea,
+		DepartureTime: req.DepartureTime,
+		Notes:         req.Notes,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	saved, err := h.Repo.UpsertOffer(r.Context(), offer)
+	if errors.Is(err, repository.ErrRiderCannotDrive) {
+		return api.NewAPIError(http.StatusConflict, "Leave the ride you joined before offering one", nil)
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if !saved {
+		return api.NewAPIError(http.StatusConflict, "More riders have joined than that many seats", nil)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(offer)
+}
+
+func (h *RidesHandler) DeleteOffer(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	err := h.Repo.DeleteOffer(r.Context(), inviteID, chi.URLParam(r, "offerId"), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Ride offer not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *RidesHandler) UpsertRequest(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if err := h.requireAttending(r, inviteID, userID); err != nil {
+		return err
+	}
+
+	var req models.RideRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+
+	request := &models.RideRequest{
+		ID:         utils.GenerateID("riderequest"),
+		InviteID:   inviteID,
+		RiderID:    userID,
+		PickupArea: req.PickupArea,
+		Notes:      req.Notes,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := h.Repo.UpsertRequest(r.Context(), request); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *RidesHandler) DeleteRequest(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	if err := h.Repo.DeleteRequest(r.Context(), inviteID, userID); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// JoinOffer takes a seat in someone's car, moving the rider out of any other ride.
+func (h *RidesHandler) JoinOffer(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if err := h.requireAttending(r, inviteID, userID); err != nil {
+		return err
+	}
+
+	offer, err := h.Repo.GetOffer(r.Context(), inviteID, chi.URLParam(r, "offerId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Ride offer not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if offer.DriverID == userID {
+		return api.ErrBadRequest("You can't join your own ride")
+	}
+
+	request := &models.RideRequest{
+		ID:        utils.GenerateID("riderequest"),
+		InviteID:  inviteID,
+		RiderID:   userID,
+		OfferID:   &offer.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	joined, err := h.Repo.JoinOffer(r.Context(), request)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Ride offer not found")
+	}
+	if errors.Is(err, repository.ErrDriverCannotRide) {
+		return api.NewAPIError(http.StatusConflict, "You are offering a ride yourself", nil)
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if !joined {
+		return api.NewAPIError(http.StatusConflict, "This ride is full", nil)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *RidesHandler) LeaveOffer(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	if err := h.Repo.LeaveOffer(r.Context(), inviteID, chi.URLParam(r, "offerId"), userID); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// requireAttending allows ride actions only for users who RSVP'd YES or MAYBE.
+func (h *RidesHandler) requireAttending(r *http.Request, inviteID, userID string) error {
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	status, err := h.Repo.GetRSVPStatus(r.Context(), inviteID, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return api.ErrInternal(err)
+	}
+	if status != "YES" && status != "MAYBE" {
+		return api.ErrForbidden("RSVP yes or maybe to coordinate rides")
+	}
+	return nil
+}
+
+// buildRideBoard redacts ride data for userID: drivers and riders only see
+// each other's identity and notes once they are matched. Riders sharing a car
+// only see each other's name and picture.
+func buildRideBoard(userID string, offers []models.RideOfferWithDriver, requests []models.RideRequestWithRider) models.RideBoard {
+	ridersByOffer := make(map[string][]models.User)
+	myOfferID := ""
+	for _, req := range requests {
+		if req.OfferID != nil {
+			ridersByOffer[*req.OfferID] = append(ridersByOffer[*req.OfferID], req.Rider)
+			if req.RiderID == userID {
+				myOfferID = *req.OfferID
+			}
+		}
+	}
+
+	driverOffers := make(map[string]string) // offer ID -> driver ID
+	board := models.RideBoard{
+		Offers:   make([]models.RideOfferView, len(offers)),
+		Requests: make([]models.RideRequestView, len(requests)),
+	}
+
+	for i, offer := range offers {
+		driverOffers[offer.ID] = offer.DriverID
+		view := models.RideOfferView{
+			ID:            offer.ID,
+			Seats:         offer.Seats,
+			SeatsTaken:    len(ridersByOffer[offer.ID]),
+			DepartureArea: offer.DepartureArea,
+			DepartureTime: offer.DepartureTime,
+			IsMine:        offer.DriverID == userID,
+			Joined:        offer.ID == myOfferID,
+		}
+		if view.IsMine || view.Joined {
+			driver := offer.Driver
+			view.Driver = &driver
+			view.Notes = offer.Notes
+			view.Riders = ridersByOffer[offer.ID]
+			if !view.IsMine {
+				view.Riders = make([]models.User, len(ridersByOffer[offer.ID]))
+				for j, rider := range ridersByOffer[offer.ID] {
+					view.Riders[j] = models.User{ID: rider.ID, Name: rider.Name, Image: rider.Image}
+				}
+			}
+		}
+		board.Offers[i] = view
+	}
+
+	for i, req := range requests {
+		view := models.RideRequestView{
+			ID:         req.ID,
+			PickupArea: req.PickupArea,
+			Matched:    req.OfferID != nil,
+			IsMine:     req.RiderID == userID,
+		}
+		if view.IsMine || (req.OfferID != nil && driverOffers[*req.OfferID] == userID) {
+			rider := req.Rider
+			view.Rider = &rider
+			view.Notes = req.Notes
+		}
+		board.Requests[i] = view
+	}
+	return board
+}
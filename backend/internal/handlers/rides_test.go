@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinRideOffer(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewRidesHandler(repository.NewRideRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	departure := time.Now().Add(24 * time.Hour)
+	expectAttending := func(userID string) {
+		expectInviteAccess(mock, "invite-1", userID, "user-host", true)
+		mock.ExpectQuery(`SELECT status FROM "RSVP"`).
+			WithArgs("invite-1", userID).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("YES"))
+	}
+	expectOffer := func(driverID string) {
+		mock.ExpectQuery(`SELECT \* FROM "RideOffer" WHERE id = \$1`).
+			WithArgs("ride-1", "invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "driverId", "seats", "departureArea", "departureTime"}).
+				AddRow("ride-1", "invite-1", driverID, 2, "Downtown", departure))
+	}
+	expectNoOwnOffer := func(userID string) {
+		mock.ExpectQuery(`SELECT id FROM "RideOffer" WHERE "inviteId" = \$1 AND "driverId" = \$2`).
+			WithArgs("invite-1", userID).
+			WillReturnError(sql.ErrNoRows)
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Success",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectAttending("user-123")
+				expectOffer("user-driver")
+				mock.ExpectBegin()
+				expectNoOwnOffer("user-123")
+				mock.ExpectQuery(`SELECT seats FROM "RideOffer"`).
+					WithArgs("ride-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"seats"}).AddRow(2))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "RideRequest"`).
+					WithArgs("ride-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectExec(`INSERT INTO "RideRequest"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-123", "ride-1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Ride is full",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectAttending("user-123")
+				expectOffer("user-driver")
+				mock.ExpectBegin()
+				expectNoOwnOffer("user-123")
+				mock.ExpectQuery(`SELECT seats FROM "RideOffer"`).
+					WithArgs("ride-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"seats"}).AddRow(2))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "RideRequest"`).
+					WithArgs("ride-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectRollback()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Driver of another ride",
+			userID: "user-other-driver",
+			mockBehavior: func() {
+				expectAttending("user-other-driver")
+				expectOffer("user-driver")
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM "RideOffer" WHERE "inviteId" = \$1 AND "driverId" = \$2`).
+					WithArgs("invite-1", "user-other-driver").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ride-2"))
+				mock.ExpectRollback()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Own ride",
+			userID: "user-driver",
+			mockBehavior: func() {
+				expectAttending("user-driver")
+				expectOffer("user-driver")
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/invites/invite-1/rides/offers/ride-1/join", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			rctx.URLParams.Add("offerId", "ride-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.JoinOffer).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBuildRideBoard(t *testing.T) {
+	offerID := "ride-1"
+	notes := "Blue van"
+	riderEmail, coRiderEmail := "rider@example.com", "co@example.com"
+	offers := []models.RideOfferWithDriver{{
+		RideOffer: models.RideOffer{ID: offerID, DriverID: "driver", Seats: 3, DepartureArea: "Downtown", Notes: &notes},
+		Driver:    models.User{ID: "driver"},
+	}}
+	requests := []models.RideRequestWithRider{
+		{RideRequest: models.RideRequest{ID: "req-1", RiderID: "rider", OfferID: &offerID}, Rider: models.User{ID: "rider", Email: &riderEmail}},
+		{RideRequest: models.RideRequest{ID: "req-3", RiderID: "co-rider", OfferID: &offerID}, Rider: models.User{ID: "co-rider", Email: &coRiderEmail}},
+		{RideRequest: models.RideRequest{ID: "req-2", RiderID: "looking"}, Rider: models.User{ID: "looking"}},
+	}
+
+	// Someone not involved sees seats and areas only
+	board := buildRideBoard("other", offers, requests)
+	assert.Equal(t, 2, board.Offers[0].SeatsTaken)
+	assert.Nil(t, board.Offers[0].Driver)
+	assert.Nil(t, board.Offers[0].Notes)
+	assert.Nil(t, board.Requests[0].Rider)
+	assert.Nil(t, board.Requests[1].Rider)
+
+	// Matched rider sees the driver, the driver sees their rider but not unmatched ones
+	board = buildRideBoard("rider", offers, requests)
+	assert.True(t, board.Offers[0].Joined)
+	assert.Equal(t, "driver", board.Offers[0].Driver.ID)
+
+	// Co-riders see each other's name and picture, not their email
+	if assert.Len(t, board.Offers[0].Riders, 2) {
+		assert.Equal(t, "co-rider", board.Offers[0].Riders[1].ID)
+		assert.Nil(t, board.Offers[0].Riders[1].Email)
+	}
+
+	board = buildRideBoard("driver", offers, requests)
+	assert.Equal(t, "rider", board.Requests[0].Rider.ID)
+	assert.Nil(t, board.Requests[2].Rider)
+	assert.Len(t, board.Offers[0].Riders, 2)
+	assert.Equal(t, &riderEmail, board.Offers[0].Riders[0].Email)
+}
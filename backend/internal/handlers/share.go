@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/tokens"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	guestRSVPTokenPurpose  = "guest-rsvp"
+	maxGuestRSVPsPerInvite = 500
+	maxGuestPartySize      = 10
+	// Manage links keep working this long after the event (or after being
+	// issued, for events already past)
+	guestManageTokenGrace = 30 * 24 * time.Hour
+)
+
+type ShareHandler struct {
+	Repo   repository.ShareRepository
+	Access *access.Policy
+	Signer *tokens.Signer
+	AppURL string // Base URL of the web app, used to build guest management links
+}
+
+func NewShareHandler(repo repository.ShareRepository, policy *access.Policy, signer *tokens.Signer, appURL string) *ShareHandler {
+	return &ShareHandler{Repo: repo, Access: policy, Signer: signer, AppURL: strings.TrimRight(appURL, "/")}
+}
+
+// RegisterHostRoutes mounts link management under /api/invites
+func (h *ShareHandler) RegisterHostRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/share-link", api.Handler(h.GetShareLink))
+	r.Method("POST", "/{id}/share-link", api.Handler(h.CreateShareLink))
+	r.Method("DELETE", "/{id}/share-link", api.Handler(h.RevokeShareLink))
+}
+
+// RegisterPublicRoutes mounts the session-less guest endpoints
+func (h *ShareHandler) RegisterPublicRoutes(r chi.Router) {
+	r.Method("GET", "/invites/{token}", api.Handler(h.GetPublicInvite))
+	r.Method("POST", "/invites/{token}/rsvp", api.Handler(h.CreateGuestRSVP))
+	r.Method("GET", "/rsvp/{manageToken}", api.Handler(h.GetGuestRSVP))
+	r.Method("PUT", "/rsvp/{manageToken}", api.Handler(h.UpdateGuestRSVP))
+	r.Method("GET", "/rsvp/{manageToken}/ticket", api.Handler(h.GetGuestTicket))
+}
+
+func (h *ShareHandler) GetShareLink(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can manage share links"); err != nil {
+		return err
+	}
+
+	link, err := h.Repo.GetActiveShareLink(r.Context(), inviteID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("No active share link")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(link)
+}
+
+// CreateShareLink issues a new public link, revoking the previous one.
+func (h *ShareHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can manage share links"); err != nil {
+		return err
+	}
+
+	link := &models.InviteShareLink{
+		ID:          utils.GenerateID("share"),
+		InviteID:    inviteID,
+		Token:       utils.GenerateRandomString(32),
+		CreatedByID: userID,
+		CreatedAt:   time.Now(),
+	}
+	if err := h.Repo.CreateShareLink(r.Context(), link); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(link)
+}
+
+func (h *ShareHandler) RevokeShareLink(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can manage share links"); err != nil {
+		return err
+	}
+
+	if err := h.Repo.RevokeShareLinks(r.Context(), inviteID); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *ShareHandler) GetPublicInvite(w http.ResponseWriter, r *http.Request) error {
+	view, err := h.Repo.GetPublicInvite(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		// Unknown and revoked links look the same
+		return api.ErrNotFound("Invite not found")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(view)
+}
+
+func (h *ShareHandler) CreateGuestRSVP(w http.ResponseWriter, r *http.Request) error {
+	view, err := h.Repo.GetPublicInvite(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		return api.ErrNotFound("Invite not found")
+	}
+
+	var req models.GuestRSVPRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	if req.Website != "" {
+		// Honeypot field filled in, almost certainly a bot
+		return api.ErrBadRequest("Invalid submission")
+	}
+	email, err := validateGuestRSVP(&req)
+	if err != nil {
+		return err
+	}
+
+	rsvp := &models.GuestRSVP{
+		ID:          utils.GenerateID("guest"),
+		InviteID:    view.ID,
+		ShareLinkID: view.ShareLinkID,
+		Name:        strings.TrimSpace(req.Name),
+		Email:       email,
+		Status:      req.Status,
+		GuestCount:  req.GuestCount,
+		Dietary:     req.Dietary,
+		Note:        req.Note,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	created, err := h.Repo.CreateGuestRSVP(r.Context(), rsvp, maxGuestRSVPsPerInvite)
+	if errors.Is(err, repository.ErrGuestListFull) {
+		return api.ErrForbidden("This invite is no longer accepting guest responses")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if !created {
+		// Don't let anyone who knows an email overwrite that guest's answer
+		return api.NewAPIError(http.StatusConflict, "This email has already responded. Use the link from your confirmation to change your answer.", nil)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(h.guestResponse(rsvp, view))
+}
+
+func (h *ShareHandler) GetGuestRSVP(w http.ResponseWriter, r *http.Request) error {
+	rsvp, view, err := h.guestRSVPFromToken(r)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(h.guestResponse(rsvp, view))
+}
+
+func (h *ShareHandler) UpdateGuestRSVP(w http.ResponseWriter, r *http.Request) error {
+	rsvp, _, err := h.guestRSVPFromToken(r)
+	if err != nil {
+		return err
+	}
+
+	var req models.GuestRSVPRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	// The email is part of the guest's identity and can't be changed here
+	req.Email = rsvp.Email
+	if req.Name == "" {
+		req.Name = rsvp.Name
+	}
+	if _, err := validateGuestRSVP(&req); err != nil {
+		return err
+	}
+
+	rsvp.Name = strings.TrimSpace(req.Name)
+	rsvp.Status = req.Status
+	rsvp.GuestCount = req.GuestCount
+	rsvp.Dietary = req.Dietary
+	rsvp.Note = req.Note
+	rsvp.UpdatedAt = time.Now()
+
+	if err := h.Repo.UpdateGuestRSVP(r.Context(), rsvp); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	view, err := h.Repo.GetGuestPublicInvite(r.Context(), rsvp.ID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(h.guestResponse(rsvp, view))
+}
+
+// GetGuestTicket returns the guest's QR check-in ticket.
+func (h *ShareHandler) GetGuestTicket(w http.ResponseWriter, r *http.Request) error {
+	rsvp, view, err := h.guestRSVPFromToken(r)
+	if err != nil {
+		return err
+	}
+	if rsvp.Status != "YES" {
+		return api.ErrForbidden("RSVP yes to get a ticket")
+	}
+
+	eventEnd := view.EventDate
+	if view.EndDate != nil {
+		eventEnd = *view.EndDate
+	}
+	ticket, err := newCheckInTicket(h.Signer, rsvp.InviteID, ticketKindGuest, rsvp.ID, eventEnd)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(ticket)
+}
+
+func (h *ShareHandler) guestRSVPFromToken(r *http.Request) (*models.GuestRSVP, *models.PublicInviteView, error) {
+	rsvpID, ok := h.Signer.VerifyAt(guestRSVPTokenPurpose, chi.URLParam(r, "manageToken"), time.Now())
+	if !ok {
+		return nil, nil, api.ErrNotFound("RSVP not found")
+	}
+
+	rsvp, err := h.Repo.GetGuestRSVP(r.Context(), rsvpID)
+	if err != nil {
+		return nil, nil, api.ErrNotFound("RSVP not found")
+	}
+
+	// Revoking the share link also locks out the guests who used it
+	view, err := h.Repo.GetGuestPublicInvite(r.Context(), rsvp.ID)
+	if err != nil {
+		return nil, nil, api.ErrNotFound("RSVP not found")
+	}
+	return rsvp, view, nil
+}
+
+func (h *ShareHandler) guestResponse(rsvp *models.GuestRSVP, view *models.PublicInviteView) models.GuestRSVPResponse {
+	expiresAt := view.EventDate
+	if now := time.Now(); expiresAt.Before(now) {
+		expiresAt = now
+	}
+	token := h.Signer.SignUntil(guestRSVPTokenPurpose, rsvp.ID, expiresAt.Add(guestManageTokenGrace))
+	return models.GuestRSVPResponse{
+		RSVP:        *rsvp,
+		Invite:      *view,
+		ManageToken: token,
+		ManageURL:   h.AppURL + "/rsvp/" + token,
+	}
+}
+
+// validateGuestRSVP normalizes the request in place and returns the lower-cased email.
+func validateGuestRSVP(req *models.GuestRSVPRequest) (string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return "", api.ErrBadRequest("Name is required (max 100 characters)")
+	}
+
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		return "", api.ErrBadRequest("A valid email is required")
+	}
+
+	switch req.Status {
+	case "YES", "NO", "MAYBE":
+	default:
+		return "", api.ErrBadRequest("Status must be YES, NO or MAYBE")
+	}
+
+	if req.GuestCount < 1 {
+		req.GuestCount = 1
+	}
+	if req.GuestCount > maxGuestPartySize {
+		return "", api.ErrBadRequest("Guest count is too large")
+	}
+	if (req.Note != nil && len(*req.Note) > 1000) || (req.Dietary != nil && len(*req.Dietary) > 500) {
+		return "", api.ErrBadRequest("Note or dietary information is too long")
+	}
+
+	return strings.ToLower(addr.Address), nil
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/tokens"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var publicInviteColumns = []string{"id", "title", "description", "location", "map_link", "eventDate", "hostName", "shareLinkId", "going", "maybe"}
+
+func TestCreateGuestRSVP(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewShareHandler(repository.NewShareRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), tokens.NewSigner("secret"), "http://app.test")
+
+	expectPublicInvite := func() {
+		mock.ExpectQuery(`SELECT .* FROM "InviteShareLink" l`).
+			WithArgs("token-1").
+			WillReturnRows(sqlmock.NewRows(publicInviteColumns).
+				AddRow("invite-1", "Dinner", nil, nil, nil, time.Now().Add(48*time.Hour), "Host", "share-1", 3, 1))
+	}
+	expectGuestCount := func(count int) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM "Invite" WHERE id = \$1 FOR NO KEY UPDATE`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invite-1"))
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "GuestRSVP"`).
+			WithArgs("invite-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: map[string]interface{}{"name": "Guest", "email": "Guest@Example.com", "status": "YES", "guestCount": 2},
+			mockBehavior: func() {
+				expectPublicInvite()
+				expectGuestCount(0)
+				mock.ExpectExec(`INSERT INTO "GuestRSVP"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "share-1", "Guest", "guest@example.com", "YES", 2, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "Email already responded",
+			body: map[string]interface{}{"name": "Guest", "email": "guest@example.com", "status": "NO"},
+			mockBehavior: func() {
+				expectPublicInvite()
+				expectGuestCount(1)
+				mock.ExpectExec(`INSERT INTO "GuestRSVP"`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Guest list full",
+			body: map[string]interface{}{"name": "Guest", "email": "guest@example.com", "status": "YES"},
+			mockBehavior: func() {
+				expectPublicInvite()
+				expectGuestCount(maxGuestRSVPsPerInvite)
+				mock.ExpectRollback()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "Honeypot filled",
+			body: map[string]interface{}{"name": "Bot", "email": "bot@example.com", "status": "YES", "website": "http://spam"},
+			mockBehavior: func() {
+				expectPublicInvite()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Revoked link",
+			body: map[string]interface{}{"name": "Guest", "email": "guest@example.com", "status": "YES"},
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT .* FROM "InviteShareLink" l`).
+					WithArgs("token-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/public/invites/token-1/rsvp", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("token", "token-1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.CreateGuestRSVP).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if rr.Code == http.StatusCreated {
+				var resp models.GuestRSVPResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Contains(t, resp.ManageURL, "http://app.test/rsvp/")
+				id, ok := handler.Signer.VerifyAt(guestRSVPTokenPurpose, resp.ManageToken, time.Now())
+				assert.True(t, ok)
+				assert.Equal(t, resp.RSVP.ID, id)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUpdateGuestRSVP(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	signer := tokens.NewSigner("secret")
+	handler := NewShareHandler(repository.NewShareRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), signer, "http://app.test")
+
+	validUntil := time.Now().Add(time.Hour)
+	expectGuestRSVP := func() {
+		mock.ExpectQuery(`SELECT \* FROM "GuestRSVP" WHERE id = \$1`).
+			WithArgs("guest-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "shareLinkId", "name", "email", "status", "guestCount"}).
+				AddRow("guest-1", "invite-1", "share-1", "Guest", "guest@example.com", "YES", 1))
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:  "Success",
+			token: signer.SignUntil(guestRSVPTokenPurpose, "guest-1", validUntil),
+			mockBehavior: func() {
+				expectGuestRSVP()
+				mock.ExpectQuery(`SELECT .* FROM "GuestRSVP" g`).
+					WithArgs("guest-1").
+					WillReturnRows(sqlmock.NewRows(publicInviteColumns).
+						AddRow("invite-1", "Dinner", nil, nil, nil, time.Now(), "Host", "share-1", 1, 0))
+				mock.ExpectExec(`UPDATE "GuestRSVP"`).
+					WithArgs("guest-1", "Guest", "NO", 1, nil, nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT .* FROM "GuestRSVP" g`).
+					WithArgs("guest-1").
+					WillReturnRows(sqlmock.NewRows(publicInviteColumns).
+						AddRow("invite-1", "Dinner", nil, nil, nil, time.Now(), "Host", "share-1", 0, 0))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Share link revoked",
+			token: signer.SignUntil(guestRSVPTokenPurpose, "guest-1", validUntil),
+			mockBehavior: func() {
+				expectGuestRSVP()
+				mock.ExpectQuery(`SELECT .* FROM "GuestRSVP" g`).
+					WithArgs("guest-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Expired token",
+			token:          signer.SignUntil(guestRSVPTokenPurpose, "guest-1", time.Now().Add(-time.Hour)),
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Forged token",
+			token:          tokens.NewSigner("other").SignUntil(guestRSVPTokenPurpose, "guest-1", validUntil),
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]interface{}{"status": "NO"})
+			req, _ := http.NewRequest("PUT", "/public/rsvp/"+tt.token, bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("manageToken", tt.token)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.UpdateGuestRSVP).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetGuestTicket(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	signer := tokens.NewSigner("secret")
+	handler := NewShareHandler(repository.NewShareRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), signer, "http://app.test")
+
+	token := signer.SignUntil(guestRSVPTokenPurpose, "guest-1", time.Now().Add(time.Hour))
+	expectGuest := func(status string) {
+		mock.ExpectQuery(`SELECT \* FROM "GuestRSVP" WHERE id = \$1`).
+			WithArgs("guest-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "shareLinkId", "name", "email", "status", "guestCount"}).
+				AddRow("guest-1", "invite-1", "share-1", "Guest", "guest@example.com", status, 1))
+		mock.ExpectQuery(`SELECT .* FROM "GuestRSVP" g`).
+			WithArgs("guest-1").
+			WillReturnRows(sqlmock.NewRows(publicInviteColumns).
+				AddRow("invite-1", "Dinner", nil, nil, nil, time.Now().Add(3*time.Hour), "Host", "share-1", 1, 0))
+	}
+
+	tests := []struct {
+		name           string
+		status         string
+		expectedStatus int
+	}{
+		{name: "Yes RSVP", status: "YES", expectedStatus: http.StatusOK},
+		{name: "Maybe RSVP", status: "MAYBE", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/public/rsvp/"+token+"/ticket", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("manageToken", token)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			expectGuest(tt.status)
+			rr := httptest.NewRecorder()
+			api.Handler(handler.GetGuestTicket).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCreateShareLink(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewShareHandler(repository.NewShareRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), tokens.NewSigner("secret"), "http://app.test")
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Host rotates link",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "InviteShareLink" SET "revokedAt"`).
+					WithArgs("invite-1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO "InviteShareLink"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", sqlmock.AnyArg(), "user-123", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Member cannot create link",
+			userID: "user-456",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-123", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/invites/invite-1/share-link", nil)
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.CreateShareLink).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	maxSignupItems    = 50
+	maxSignupQuantity = 100
+)
+
+type SignupsHandler struct {
+	Repo   repository.SignupRepository
+	Access *access.Policy
+}
+
+func NewSignupsHandler(repo repository.SignupRepository, policy *access.Policy) *SignupsHandler {
+	return &SignupsHandler{Repo: repo, Access: policy}
+}
+
+// RegisterRoutes mounts sign-up sheets under /api/invites
+func (h *SignupsHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/signups", api.Handler(h.ListSheets))
+	r.Method("POST", "/{id}/signups", api.Handler(h.CreateSheet))
+	r.Method("POST", "/{id}/signups/{sheetId}/items", api.Handler(h.AddItems))
+	r.Method("DELETE", "/{id}/signups/{sheetId}", api.Handler(h.DeleteSheet))
+	r.Method("PUT", "/{id}/signup-items/{itemId}/claim", api.Handler(h.ClaimItem))
+	r.Method("DELETE", "/{id}/signup-items/{itemId}/claim", api.Handler(h.UnclaimItem))
+}
+
+func (h *SignupsHandler) ListSheets(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	sheets, err := h.Repo.ListSheets(r.Context(), inviteID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(sheets)
+}
+
+func (h *SignupsHandler) CreateSheet(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can manage sign-up sheets"); err != nil {
+		return err
+	}
+
+	var req models.CreateSignupSheetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return api.ErrBadRequest("Title is required")
+	}
+
+	sheet := &models.SignupSheet{
+		ID:          utils.GenerateID("signup"),
+		InviteID:    inviteID,
+		Title:       req.Title,
+		Description: req.Description,
+		CreatedByID: userID,
+		CreatedAt:   time.Now(),
+	}
+
+	items, err := buildSignupItems(sheet.ID, req.Items)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return api.ErrBadRequest("At least one item is required")
+	}
+
+	if err := h.Repo.CreateSheet(r.Context(), sheet, items); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(sheet)
+}
+
+func (h *SignupsHandler) AddItems(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	sheetID := chi.URLParam(r, "sheetId")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can manage sign-up sheets"); err != nil {
+		return err
+	}
+
+	if _, err := h.Repo.GetSheet(r.Context(), inviteID, sheetID); err != nil {
+		return api.ErrNotFound("Sign-up sheet not found")
+	}
+
+	var req models.AddSignupItemsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+
+	items, err := buildSignupItems(sheetID, req.Items)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return api.ErrBadRequest("At least one item is required")
+	}
+
+	if err := h.Repo.AddItems(r.Context(), sheetID, items); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *SignupsHandler) DeleteSheet(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can manage sign-up sheets"); err != nil {
+		return err
+	}
+
+	err := h.Repo.DeleteSheet(r.Context(), inviteID, chi.URLParam(r, "sheetId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Sign-up sheet not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// ClaimItem sets how many portions/slots of an item the user takes on.
+// Only attendees who answered YES or MAYBE can claim.
+func (h *SignupsHandler) ClaimItem(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequirePost(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	var req models.ClaimSignupItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+	if req.Quantity < 0 || req.Quantity > maxSignupQuantity {
+		return api.ErrBadRequest("Invalid quantity")
+	}
+
+	status, err := h.Repo.GetRSVPStatus(r.Context(), inviteID, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return api.ErrInternal(err)
+	}
+	if status != "YES" && status != "MAYBE" {
+		return api.ErrForbidden("RSVP yes or maybe to sign up for items")
+	}
+
+	claim := &models.SignupClaim{
+		ID:        utils.GenerateID("claim"),
+		ItemID:    chi.URLParam(r, "itemId"),
+		UserID:    userID,
+		Quantity:  req.Quantity,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	claimed, err := h.Repo.ClaimItem(r.Context(), inviteID, claim)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Item not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+	if !claimed {
+		return api.NewAPIError(http.StatusConflict, "Not enough left to claim", nil)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(claim)
+}
+
+func (h *SignupsHandler) UnclaimItem(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireView(r.Context(), inviteID, userID); err != nil {
+		return err
+	}
+
+	if err := h.Repo.UnclaimItem(r.Context(), inviteID, chi.URLParam(r, "itemId"), userID); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func buildSignupItems(sheetID string, reqs []models.SignupItemRequest) ([]models.SignupItem, error) {
+	if len(reqs) > maxSignupItems {
+		return nil, api.ErrBadRequest("Too many items")
+	}
+
+	items := make([]models.SignupItem, 0, len(reqs))
+	for i, req := range reqs {
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			return nil, api.ErrBadRequest("Item name is required")
+		}
+		if req.Quantity == 0 {
+			req.Quantity = 1
+		}
+		if req.Quantity < 0 || req.Quantity > maxSignupQuantity {
+			return nil, api.ErrBadRequest("Invalid quantity for " + name)
+		}
+		items = append(items, models.SignupItem{
+			ID:        utils.GenerateID("item"),
+			SheetID:   sheetID,
+			Name:      name,
+			Quantity:  req.Quantity,
+			Position:  i,
+			CreatedAt: time.Now(),
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimSignupItem(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewSignupsHandler(repository.NewSignupRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)))
+
+	expectRSVPStatus := func(status string) {
+		rows := sqlmock.NewRows([]string{"status"})
+		if status != "" {
+			rows.AddRow(status)
+		}
+		mock.ExpectQuery(`SELECT status FROM "RSVP"`).
+			WithArgs("invite-1", "user-123").
+			WillReturnRows(rows)
+	}
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: map[string]interface{}{"quantity": 2},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectRSVPStatus("MAYBE")
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT it\.quantity FROM "SignupItem"`).
+					WithArgs("item-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(3))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(quantity\), 0\) FROM "SignupClaim"`).
+					WithArgs("item-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+				mock.ExpectExec(`INSERT INTO "SignupClaim"`).
+					WithArgs(sqlmock.AnyArg(), "item-1", "user-123", 2, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Not enough left",
+			body: map[string]interface{}{"quantity": 3},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectRSVPStatus("YES")
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT it\.quantity FROM "SignupItem"`).
+					WithArgs("item-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(3))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(quantity\), 0\) FROM "SignupClaim"`).
+					WithArgs("item-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Declined RSVP",
+			body: map[string]interface{}{},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectRSVPStatus("NO")
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "No RSVP yet",
+			body: map[string]interface{}{},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectRSVPStatus("")
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "Item from another invite",
+			body: map[string]interface{}{},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+				expectRSVPStatus("YES")
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT it\.quantity FROM "SignupItem"`).
+					WithArgs("item-1", "invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"quantity"}))
+				mock.ExpectRollback()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("PUT", "/invites/invite-1/signup-items/item-1/claim", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			rctx.URLParams.Add("itemId", "item-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, "user-123")
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.ClaimItem).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
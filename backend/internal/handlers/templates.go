@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/utils"
+	"privo-club-backend/internal/vault"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TemplatesHandler struct {
+	Repo        repository.TemplateRepository
+	InvitesRepo repository.InviteRepository
+	Access      *access.Policy
+	Geocoder    geo.Geocoder
+}
+
+func NewTemplatesHandler(repo repository.TemplateRepository, invitesRepo repository.InviteRepository, policy *access.Policy, geocoder geo.Geocoder) *TemplatesHandler {
+	return &TemplatesHandler{Repo: repo, InvitesRepo: invitesRepo, Access: policy, Geocoder: geocoder}
+}
+
+func (h *TemplatesHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/", api.Handler(h.ListTemplates))
+	r.Method("POST", "/", api.Handler(h.CreateTemplate))
+	r.Method("DELETE", "/{templateId}", api.Handler(h.DeleteTemplate))
+	r.Method("POST", "/{templateId}/invites", api.Handler(h.UseTemplate))
+}
+
+func (h *TemplatesHandler) ListTemplates(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	templates, err := h.Repo.ListTemplates(r.Context(), userID)
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(templates)
+}
+
+// CreateTemplate saves a template, either from the given fields or copied
+// from an invite the user hosts.
+func (h *TemplatesHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	var req models.CreateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return api.ErrBadRequest("Template name is required")
+	}
+
+	template := &models.InviteTemplate{
+		ID:          utils.GenerateID("template"),
+		Name:        req.Name,
+		OwnerID:     userID,
+		CircleID:    req.CircleID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		Location:    req.Location,
+		MapLink:     req.MapLink,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if req.InviteID != nil {
+		if _, err := h.Access.RequireHost(r.Context(), *req.InviteID, userID, "Only the host can save this invite as a template"); err != nil {
+			return err
+		}
+		invite, err := h.InvitesRepo.GetInviteByID(r.Context(), *req.InviteID)
+		if err != nil {
+			return api.ErrNotFound("Invite not found")
+		}
+		template.Title = invite.Title
+		template.Description = invite.Description
+		template.Location = invite.Location
+		template.MapLink = invite.MapLink
+		template.Place = invite.Place
+		if template.CircleID == nil {
+			template.CircleID = invite.CircleID
+		}
+	} else {
+		place, err := resolvePlace(r.Context(), h.Geocoder, template.Location, template.MapLink, req.Place)
+		if err != nil {
+			return err
+		}
+		template.Place = place
+	}
+
+	if template.Title == "" {
+		return api.ErrBadRequest("Title is required")
+	}
+
+	// Sharing with a circle requires being part of it
+	if template.CircleID != nil {
+		isMember, err := h.Repo.IsCircleMember(r.Context(), *template.CircleID, userID)
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+		if !isMember {
+			return api.ErrForbidden("You are not a member of this circle")
+		}
+	}
+
+	if err := h.Repo.CreateTemplate(r.Context(), template); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(template)
+}
+
+func (h *TemplatesHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	err := h.Repo.DeleteTemplate(r.Context(), chi.URLParam(r, "templateId"), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Template not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// UseTemplate creates a new invite from a template for the given date. Circle
+// templates post into their circle, so only its ACTIVE members may use them,
+// even the owner.
+func (h *TemplatesHandler) UseTemplate(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	template, err := h.Repo.GetTemplate(r.Context(), chi.URLParam(r, "templateId"), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ErrNotFound("Template not found")
+	}
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	var req models.UseTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	if err := validateEventDate(req.EventDate); err != nil {
+		return err
+	}
+
+	if template.CircleID != nil {
+		isMember, err := h.Repo.IsCircleMember(r.Context(), *template.CircleID, userID)
+		if err != nil {
+			return api.ErrInternal(err)
+		}
+		if !isMember {
+			return api.ErrForbidden("You are not a member of this circle")
+		}
+	}
+
+	// Templates saved before they kept a place are resolved like a new invite
+	place, err := resolvePlace(r.Context(), h.Geocoder, template.Location, template.MapLink, template.Place)
+	if err != nil {
+		return err
+	}
+
+	title := template.Title
+	if req.Title != nil && strings.TrimSpace(*req.Title) != "" {
+		title = strings.TrimSpace(*req.Title)
+	}
+
+	invite := &models.Invite{
+		ID:          utils.GenerateID("invite"),
+		Title:       title,
+		Description: template.Description,
+		Location:    template.Location,
+		MapLink:     template.MapLink,
+		EventDate:   req.EventDate,
+		SenderID:    userID,
+		CircleID:    template.CircleID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Place:       place,
+		VaultPolicy: vault.Normalize(models.VaultPolicy{}),
+	}
+	if err := h.InvitesRepo.CreateInvite(r.Context(), invite); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(invite)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGeocoder resolves the listed addresses and nothing else
+type fakeGeocoder map[string]geo.Point
+
+func (g fakeGeocoder) Geocode(ctx context.Context, query string) (*models.Place, error) {
+	p, ok := g[query]
+	if !ok {
+		return nil, geo.ErrNoMatch
+	}
+	return &models.Place{PlaceName: &query, Latitude: &p.Lat, Longitude: &p.Lng}, nil
+}
+
+func TestUseTemplate(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewTemplatesHandler(
+		repository.NewTemplateRepository(sqlxDB),
+		repository.NewInviteRepository(sqlxDB),
+		access.NewPolicy(repository.NewAccessRepository(sqlxDB)),
+		fakeGeocoder{"Library": {Lat: 40.7532, Lng: -73.9822}},
+	)
+
+	eventDate := time.Now().Add(7 * 24 * time.Hour)
+	expectTemplate := func(userID string) {
+		mock.ExpectQuery(`SELECT t\.\* FROM "InviteTemplate" t WHERE t\.id = \$2`).
+			WithArgs(userID, "template-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "ownerId", "circleId", "title", "location"}).
+				AddRow("template-1", "Book club", "user-host", "circle-1", "Monthly book club", "Library"))
+	}
+	expectMember := func(userID string, count int) {
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "CircleMember"`).
+			WithArgs("circle-1", userID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Circle member uses shared template",
+			userID: "user-123",
+			body:   map[string]interface{}{"eventDate": eventDate},
+			mockBehavior: func() {
+				expectTemplate("user-123")
+				expectMember("user-123", 1)
+				// The template predates stored places, so the location is geocoded
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WithArgs(sqlmock.AnyArg(), "Monthly book club", sqlmock.AnyArg(), "Library", sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", "circle-1", sqlmock.AnyArg(), sqlmock.AnyArg(), false, nil, nil, "Library", nil, nil, nil, nil, nil, 40.7532, -73.9822, "DELAY", 24, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Stored place is reused",
+			userID: "user-123",
+			body:   map[string]interface{}{"eventDate": eventDate},
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT t\.\* FROM "InviteTemplate" t WHERE t\.id = \$2`).
+					WithArgs("user-123", "template-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "ownerId", "circleId", "title", "location", "placeName", "city", "latitude", "longitude"}).
+						AddRow("template-1", "Book club", "user-host", "circle-1", "Monthly book club", "Library", "Jefferson Market Library", "New York", 40.7347, -73.9990))
+				expectMember("user-123", 1)
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WithArgs(sqlmock.AnyArg(), "Monthly book club", sqlmock.AnyArg(), "Library", sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", "circle-1", sqlmock.AnyArg(), sqlmock.AnyArg(), false, nil, nil, "Jefferson Market Library", nil, "New York", nil, nil, nil, 40.7347, -73.9990, "DELAY", 24, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Title override",
+			userID: "user-123",
+			body:   map[string]interface{}{"eventDate": eventDate, "title": "Book club: Dune"},
+			mockBehavior: func() {
+				expectTemplate("user-123")
+				expectMember("user-123", 1)
+				mock.ExpectExec(`INSERT INTO "Invite"`).
+					WithArgs(sqlmock.AnyArg(), "Book club: Dune", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "user-123", "circle-1", sqlmock.AnyArg(), sqlmock.AnyArg(), false, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "DELAY", 24, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Missing date",
+			userID: "user-123",
+			body:   map[string]interface{}{},
+			mockBehavior: func() {
+				expectTemplate("user-123")
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Owner left the circle",
+			userID: "user-host",
+			body:   map[string]interface{}{"eventDate": eventDate},
+			mockBehavior: func() {
+				expectTemplate("user-host")
+				expectMember("user-host", 0)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Not visible",
+			userID: "user-456",
+			body:   map[string]interface{}{"eventDate": eventDate},
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT t\.\* FROM "InviteTemplate" t WHERE t\.id = \$2`).
+					WithArgs("user-456", "template-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/templates/template-1/invites", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("templateId", "template-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.UseTemplate).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCreateTemplate(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewTemplatesHandler(
+		repository.NewTemplateRepository(sqlxDB),
+		repository.NewInviteRepository(sqlxDB),
+		access.NewPolicy(repository.NewAccessRepository(sqlxDB)),
+		geo.NoGeocoder{},
+	)
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "From scratch",
+			userID: "user-123",
+			body:   map[string]interface{}{"name": "Game night", "title": "Game night"},
+			mockBehavior: func() {
+				mock.ExpectExec(`INSERT INTO "InviteTemplate"`).
+					WithArgs(sqlmock.AnyArg(), "Game night", "user-123", nil, "Game night", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "From a hosted invite",
+			userID: "user-123",
+			body:   map[string]interface{}{"name": "Book club", "inviteId": "invite-1"},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-123", false)
+				mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "senderId", "circleId", "location", "city", "latitude", "longitude"}).
+						AddRow("invite-1", "Monthly book club", "user-123", "circle-1", "Library", "New York", 40.7347, -73.9990))
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "CircleMember"`).
+					WithArgs("circle-1", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectExec(`INSERT INTO "InviteTemplate"`).
+					WithArgs(sqlmock.AnyArg(), "Book club", "user-123", "circle-1", "Monthly book club", nil, "Library", nil, nil, nil, "New York", nil, nil, nil, 40.7347, -73.9990, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Coordinates from the map link",
+			userID: "user-123",
+			body:   map[string]interface{}{"name": "Picnic", "title": "Picnic", "mapLink": "https://maps.google.com/?q=40.7829,-73.9654"},
+			mockBehavior: func() {
+				mock.ExpectExec(`INSERT INTO "InviteTemplate"`).
+					WithArgs(sqlmock.AnyArg(), "Picnic", "user-123", nil, "Picnic", nil, nil, "https://maps.google.com/?q=40.7829,-73.9654", nil, nil, nil, nil, nil, nil, 40.7829, -73.9654, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Not the host",
+			userID: "user-456",
+			body:   map[string]interface{}{"name": "Book club", "inviteId": "invite-1"},
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-456", "user-123", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Circle outsider",
+			userID: "user-123",
+			body:   map[string]interface{}{"name": "Game night", "title": "Game night", "circleId": "circle-2"},
+			mockBehavior: func() {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "CircleMember"`).
+					WithArgs("circle-2", "user-123").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Missing name",
+			userID:         "user-123",
+			body:           map[string]interface{}{"title": "Game night"},
+			mockBehavior:   func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/templates", bytes.NewBuffer(body))
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.CreateTemplate).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/vault"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UnlockVault lets the host open the vault now, whatever its policy. The
+// vault worker sends the unlock notifications.
+func (h *InvitesHandler) UnlockVault(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	inviteID := chi.URLParam(r, "id")
+	if _, err := h.Access.RequireHost(r.Context(), inviteID, userID, "Only the host can unlock the vault"); err != nil {
+		return err
+	}
+
+	if err := h.Repo.UnlockVault(r.Context(), inviteID, time.Now()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.NewAPIError(http.StatusConflict, "The vault is already unlocked", err)
+		}
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// sealVault hides other people's media while the vault is locked. Everyone
+// still sees how many memories are waiting and who has contributed so far.
+func sealVault(details *models.InviteDetails, userID string) {
+	contributors := map[string]bool{}
+	for _, item := range details.MediaItems {
+		contributors[item.UserID] = true
+	}
+	details.Vault = models.VaultSummary{
+		Sealed:           !details.IsVaultUnlocked,
+		ItemCount:        len(details.MediaItems),
+		ContributorCount: len(contributors),
+	}
+	if details.IsVaultUnlocked {
+		return
+	}
+
+	if unlocksAt, ok := vault.UnlockTime(details.Invite); ok {
+		details.Vault.UnlocksAt = &unlocksAt
+	}
+	own := []models.MediaItem{}
+	for _, item := range details.MediaItems {
+		if item.UserID == userID {
+			own = append(own, item)
+		}
+	}
+	details.MediaItems = own
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/access"
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnlockVault(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewInvitesHandler(repository.NewInviteRepository(sqlxDB), repository.NewVenueRepository(sqlxDB), access.NewPolicy(repository.NewAccessRepository(sqlxDB)), geo.NoGeocoder{})
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Host unlocks",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				mock.ExpectExec(`UPDATE "Invite" SET "isVaultUnlocked" = TRUE`).
+					WithArgs("invite-1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Already unlocked",
+			userID: "user-host",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-host", "user-host", false)
+				mock.ExpectExec(`UPDATE "Invite" SET "isVaultUnlocked" = TRUE`).
+					WithArgs("invite-1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Guests cannot unlock",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectInviteAccess(mock, "invite-1", "user-123", "user-host", true)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/invites/invite-1/vault/unlock", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "invite-1")
+			ctx := context.WithValue(req.Context(), auth.UserIDKey, tt.userID)
+			req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.UnlockVault).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestSealVault(t *testing.T) {
+	eventDate := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	hours := 24
+	media := func() []models.MediaItem {
+		return []models.MediaItem{
+			{ID: "media-1", UserID: "user-1"},
+			{ID: "media-2", UserID: "user-2"},
+			{ID: "media-3", UserID: "user-1"},
+		}
+	}
+
+	t.Run("Sealed vault shows own uploads and counts", func(t *testing.T) {
+		details := &models.InviteDetails{
+			Invite: models.Invite{
+				EventDate:   eventDate,
+				VaultPolicy: models.VaultPolicy{UnlockPolicy: "DELAY", UnlockAfterHours: &hours},
+			},
+			MediaItems: media(),
+		}
+		sealVault(details, "user-1")
+
+		assert.True(t, details.Vault.Sealed)
+		assert.Equal(t, 3, details.Vault.ItemCount)
+		assert.Equal(t, 2, details.Vault.ContributorCount)
+		assert.Equal(t, eventDate.Add(27*time.Hour), *details.Vault.UnlocksAt)
+		assert.Len(t, details.MediaItems, 2)
+		for _, item := range details.MediaItems {
+			assert.Equal(t, "user-1", item.UserID)
+		}
+	})
+
+	t.Run("Sealed vault hides everything from non-contributors", func(t *testing.T) {
+		details := &models.InviteDetails{
+			Invite:     models.Invite{EventDate: eventDate, VaultPolicy: models.VaultPolicy{UnlockPolicy: "MANUAL"}},
+			MediaItems: media(),
+		}
+		sealVault(details, "user-3")
+
+		assert.Equal(t, 3, details.Vault.ItemCount)
+		assert.Nil(t, details.Vault.UnlocksAt)
+		assert.Empty(t, details.MediaItems)
+	})
+
+	t.Run("Unlocked vault shows everything", func(t *testing.T) {
+		details := &models.InviteDetails{
+			Invite:     models.Invite{EventDate: eventDate, IsVaultUnlocked: true},
+			MediaItems: media(),
+		}
+		sealVault(details, "user-3")
+
+		assert.False(t, details.Vault.Sealed)
+		assert.Len(t, details.MediaItems, 3)
+	})
+}
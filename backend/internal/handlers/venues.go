@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/geo"
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type VenuesHandler struct {
+	Repo repository.VenueRepository
+}
+
+func NewVenuesHandler(repo repository.VenueRepository) *VenuesHandler {
+	return &VenuesHandler{Repo: repo}
+}
+
+// RegisterRoutes mounts venue management under /api/circles
+func (h *VenuesHandler) RegisterRoutes(r chi.Router) {
+	r.Method("GET", "/{id}/venues", api.Handler(h.ListVenues))
+	r.Method("POST", "/{id}/venues", api.Handler(h.CreateVenue))
+	r.Method("PUT", "/{id}/venues/{venueId}", api.Handler(h.UpdateVenue))
+	r.Method("DELETE", "/{id}/venues/{venueId}", api.Handler(h.DeleteVenue))
+}
+
+// ListVenues returns the circle's venues, most used first. The optional q
+// parameter filters by name for suggestions while typing.
+func (h *VenuesHandler) ListVenues(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	circleID := chi.URLParam(r, "id")
+	if _, err := h.requireMember(r.Context(), circleID, userID); err != nil {
+		return err
+	}
+
+	venues, err := h.Repo.ListVenues(r.Context(), circleID, strings.TrimSpace(r.URL.Query().Get("q")))
+	if err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(venues)
+}
+
+func (h *VenuesHandler) CreateVenue(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	circleID := chi.URLParam(r, "id")
+	if _, err := h.requireMember(r.Context(), circleID, userID); err != nil {
+		return err
+	}
+
+	var req models.VenueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	if err := validateVenue(&req); err != nil {
+		return err
+	}
+
+	venue := &models.Venue{
+		ID:          utils.GenerateID("venue"),
+		CircleID:    circleID,
+		CreatedByID: userID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	applyVenueRequest(venue, req)
+
+	if err := h.Repo.CreateVenue(r.Context(), venue); err != nil {
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(venue)
+}
+
+func (h *VenuesHandler) UpdateVenue(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	venue, err := h.editableVenue(r, userID)
+	if err != nil {
+		return err
+	}
+
+	var req models.VenueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.ErrBadRequest("Invalid request body")
+	}
+	if err := validateVenue(&req); err != nil {
+		return err
+	}
+	applyVenueRequest(venue, req)
+	venue.UpdatedAt = time.Now()
+
+	if err := h.Repo.UpdateVenue(r.Context(), venue); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.ErrNotFound("Venue not found")
+		}
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(venue)
+}
+
+func (h *VenuesHandler) DeleteVenue(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return api.ErrUnauthorized("Unauthorized")
+	}
+
+	venue, err := h.editableVenue(r, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.DeleteVenue(r.Context(), venue.CircleID, venue.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.ErrNotFound("Venue not found")
+		}
+		return api.ErrInternal(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// requireMember returns the user's role in the circle
+func (h *VenuesHandler) requireMember(ctx context.Context, circleID, userID string) (string, error) {
+	role, err := h.Repo.GetMemberRole(ctx, circleID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", api.ErrForbidden("You are not a member of this circle")
+	}
+	if err != nil {
+		return "", api.ErrInternal(err)
+	}
+	return role, nil
+}
+
+// editableVenue loads the venue in the URL if the user added it or manages the circle
+func (h *VenuesHandler) editableVenue(r *http.Request, userID string) (*models.Venue, error) {
+	circleID := chi.URLParam(r, "id")
+	role, err := h.requireMember(r.Context(), circleID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	venue, err := h.Repo.GetVenue(r.Context(), circleID, chi.URLParam(r, "venueId"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.ErrNotFound("Venue not found")
+	}
+	if err != nil {
+		return nil, api.ErrInternal(err)
+	}
+
+	if venue.CreatedByID != userID && role != "OWNER" && role != "ADMIN" {
+		return nil, api.ErrForbidden("Only the person who added this venue or a circle admin can change it")
+	}
+	return venue, nil
+}
+
+func validateVenue(req *models.VenueRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return api.ErrBadRequest("Venue name is required (max 100 characters)")
+	}
+	if req.Notes != nil && len(*req.Notes) > 1000 {
+		return api.ErrBadRequest("Notes are too long")
+	}
+	if req.Capacity != nil && *req.Capacity < 1 {
+		return api.ErrBadRequest("Capacity must be at least 1")
+	}
+	return nil
+}
+
+// applyVenueRequest copies the request onto the venue, taking coordinates from the map link
+func applyVenueRequest(venue *models.Venue, req models.VenueRequest) {
+	venue.Name = req.Name
+	venue.Address = req.Address
+	venue.MapLink = req.MapLink
+	venue.Notes = req.Notes
+	venue.Capacity = req.Capacity
+	venue.Latitude, venue.Longitude = nil, nil
+	if req.MapLink != nil {
+		if p, ok := geo.ParseMapLink(*req.MapLink); ok {
+			venue.Latitude, venue.Longitude = &p.Lat, &p.Lng
+		}
+	}
+}
+
+// venueDefaults fills the location fields an invite request left empty from
+// a saved venue.
+func venueDefaults(req *models.CreateInviteRequest, venue *models.Venue) {
+	if req.Location == nil {
+		location := venue.Name
+		if venue.Address != nil && *venue.Address != "" {
+			location += ", " + *venue.Address
+		}
+		req.Location = &location
+	}
+	if req.MapLink == nil {
+		req.MapLink = venue.MapLink
+	}
+	if req.PlaceName == nil {
+		req.PlaceName = &venue.Name
+	}
+	if req.Latitude == nil && req.Longitude == nil {
+		req.Latitude, req.Longitude = venue.Latitude, venue.Longitude
+	}
+}
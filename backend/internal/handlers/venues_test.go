@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"privo-club-backend/internal/api"
+	"privo-club-backend/internal/auth"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectCircleRole(mock sqlmock.Sqlmock, circleID, userID, role string) {
+	q := mock.ExpectQuery(`SELECT role FROM "CircleMember"`).WithArgs(circleID, userID)
+	if role == "" {
+		q.WillReturnError(sql.ErrNoRows)
+		return
+	}
+	q.WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(role))
+}
+
+func TestCreateVenue(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewVenuesHandler(repository.NewVenueRepository(sqlxDB))
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]interface{}
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Success with coordinates from map link",
+			userID: "user-123",
+			body: map[string]interface{}{
+				"name":     "Riverside Hall",
+				"address":  "1 River Rd",
+				"mapLink":  "https://maps.google.com/?q=40.7,-74.0",
+				"notes":    "Park behind the building",
+				"capacity": 40,
+			},
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-123", "MEMBER")
+				mock.ExpectExec(`INSERT INTO "Venue"`).
+					WithArgs(sqlmock.AnyArg(), "circle-1", "Riverside Hall", "1 River Rd", sqlmock.AnyArg(), "Park behind the building", 40, 40.7, -74.0, "user-123", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Not a member",
+			userID: "user-456",
+			body:   map[string]interface{}{"name": "Riverside Hall"},
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-456", "")
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Invalid capacity",
+			userID: "user-123",
+			body:   map[string]interface{}{"name": "Riverside Hall", "capacity": 0},
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-123", "MEMBER")
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/circles/circle-1/venues", bytes.NewBuffer(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "circle-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.CreateVenue).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestListVenues(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewVenuesHandler(repository.NewVenueRepository(sqlxDB))
+
+	tests := []struct {
+		name           string
+		query          string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:  "Search is matched literally",
+			query: "?q=50%25_off",
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-123", "MEMBER")
+				mock.ExpectQuery(`FROM "Venue" v .* strpos\(lower\(v\.name\), lower\(\$2\)\) > 0`).
+					WithArgs("circle-1", "50%_off").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "circleId", "name", "useCount"}).
+						AddRow("venue-1", "circle-1", "50%_off bar", 2))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Not a member",
+			query: "",
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-123", "")
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/circles/circle-1/venues"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "circle-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, "user-123")
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.ListVenues).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestDeleteVenue(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	handler := NewVenuesHandler(repository.NewVenueRepository(sqlxDB))
+
+	expectVenue := func() {
+		mock.ExpectQuery(`SELECT \* FROM "Venue" WHERE id = \$1`).
+			WithArgs("venue-1", "circle-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "circleId", "name", "createdById"}).
+				AddRow("venue-1", "circle-1", "Riverside Hall", "user-123"))
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockBehavior   func()
+		expectedStatus int
+	}{
+		{
+			name:   "Creator",
+			userID: "user-123",
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-123", "MEMBER")
+				expectVenue()
+				mock.ExpectExec(`DELETE FROM "Venue"`).
+					WithArgs("venue-1", "circle-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Circle admin",
+			userID: "user-admin",
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-admin", "ADMIN")
+				expectVenue()
+				mock.ExpectExec(`DELETE FROM "Venue"`).
+					WithArgs("venue-1", "circle-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Other member",
+			userID: "user-456",
+			mockBehavior: func() {
+				expectCircleRole(mock, "circle-1", "user-456", "MEMBER")
+				expectVenue()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("DELETE", "/circles/circle-1/venues/venue-1", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "circle-1")
+			rctx.URLParams.Add("venueId", "venue-1")
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, auth.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+
+			tt.mockBehavior()
+			rr := httptest.NewRecorder()
+			api.Handler(handler.DeleteVenue).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package ledger
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+
+	"privo-club-backend/internal/models"
+)
+
+// Split methods for an expense.
+const (
+	SplitEven   = "EVEN"   // Everyone pays the same, leftover cents go to the first participants
+	SplitShares = "SHARES" // Proportional to each participant's number of shares
+	SplitExact  = "EXACT"  // Amounts are given per participant and must add up to the total
+)
+
+// Limits that keep every amount * shares product well inside int64.
+const (
+	MaxAmountCents = 100_000_000_000 // 1 billion in the currency's main unit
+	MaxShares      = 1000
+)
+
+var (
+	ErrNoParticipants = errors.New("at least one participant is required")
+	ErrInvalidShares  = errors.New("shares must be between 1 and 1000")
+	ErrExactMismatch  = errors.New("exact amounts must add up to the total")
+	ErrAmountTooLarge = errors.New("amount must be at most 1000000000.00")
+)
+
+// Part is one participant's input to a split. Shares is used by SplitShares
+// and AmountCents by SplitExact.
+type Part struct {
+	UserID      string
+	Shares      int
+	AmountCents int64
+}
+
+// Split divides totalCents between parts and returns what each one owes, in
+// the same order. The result always adds up to totalCents exactly.
+func Split(totalCents int64, method string, parts []Part) ([]int64, error) {
+	if len(parts) == 0 {
+		return nil, ErrNoParticipants
+	}
+	if totalCents > MaxAmountCents {
+		return nil, ErrAmountTooLarge
+	}
+
+	switch method {
+	case SplitEven:
+		weights := make([]int64, len(parts))
+		for i := range weights {
+			weights[i] = 1
+		}
+		return splitWeighted(totalCents, weights), nil
+
+	case SplitShares:
+		weights := make([]int64, len(parts))
+		for i, p := range parts {
+			if p.Shares <= 0 || p.Shares > MaxShares {
+				return nil, ErrInvalidShares
+			}
+			weights[i] = int64(p.Shares)
+		}
+		return splitWeighted(totalCents, weights), nil
+
+	case SplitExact:
+		amounts := make([]int64, len(parts))
+		var sum int64
+		for i, p := range parts {
+			// No single amount can exceed the total, so the sum cannot overflow
+			if p.AmountCents < 0 || p.AmountCents > totalCents {
+				return nil, ErrExactMismatch
+			}
+			amounts[i] = p.AmountCents
+			sum += p.AmountCents
+		}
+		if sum != totalCents {
+			return nil, ErrExactMismatch
+		}
+		return amounts, nil
+	}
+	return nil, errors.New("unknown split method " + method)
+}
+
+// splitWeighted uses the largest remainder method so rounding never loses or
+// invents a cent.
+func splitWeighted(totalCents int64, weights []int64) []int64 {
+	var weightSum int64
+	for _, w := range weights {
+		weightSum += w
+	}
+
+	amounts := make([]int64, len(weights))
+	remainders := make([]int64, len(weights))
+	var assigned int64
+	for i, w := range weights {
+		amounts[i] = totalCents * w / weightSum
+		remainders[i] = totalCents * w % weightSum
+		assigned += amounts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := int64(0); i < totalCents-assigned; i++ {
+		amounts[order[i]]++
+	}
+	return amounts
+}
+
+// Balances returns each user's net position in cents: positive means the
+// group owes them money, negative means they owe the group.
+func Balances(expenses []models.ExpenseWithShares, settlements []models.Settlement) map[string]int64 {
+	balances := make(map[string]int64)
+	for _, e := range expenses {
+		balances[e.PaidByID] += e.AmountCents
+		for _, s := range e.Shares {
+			balances[s.UserID] -= s.AmountCents
+		}
+	}
+	for _, s := range settlements {
+		balances[s.FromUserID] += s.AmountCents
+		balances[s.ToUserID] -= s.AmountCents
+	}
+	return balances
+}
+
+// maxExactSettle bounds the exact search in Settle, which is exponential in
+// the number of people with a non-zero balance.
+const maxExactSettle = 16
+
+type entry struct {
+	userID string
+	cents  int64
+}
+
+// Settle turns balances into the fewest transfers that clear them. A group of
+// k people whose balances add up to zero can be settled with k-1 transfers,
+// so the fewest transfers come from splitting everyone into as many zero-sum
+// groups as possible. That search is exponential; beyond maxExactSettle
+// people everyone is settled as one group, which still takes at most n-1
+// transfers but may not be minimal.
+func Settle(balances map[string]int64) []models.SettleTransfer {
+	var entries []entry
+	for userID, cents := range balances {
+		if cents != 0 {
+			entries = append(entries, entry{userID, cents})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].userID < entries[j].userID })
+
+	transfers := []models.SettleTransfer{}
+	for _, group := range zeroSumGroups(entries) {
+		transfers = append(transfers, settleGroup(group)...)
+	}
+	return transfers
+}
+
+// zeroSumGroups partitions entries into as many groups adding up to zero as
+// possible. count[mask] is the most zero-sum groups the members in mask can
+// be ordered into, counting each prefix of the order that adds up to zero.
+func zeroSumGroups(entries []entry) [][]entry {
+	n := len(entries)
+	if n == 0 {
+		return nil
+	}
+	if n > maxExactSettle {
+		return [][]entry{entries}
+	}
+
+	size := 1 << n
+	sums := make([]int64, size)
+	count := make([]int, size)
+	for mask := 1; mask < size; mask++ {
+		sums[mask] = sums[mask&(mask-1)] + entries[bits.TrailingZeros(uint(mask))].cents
+		best := 0
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && count[mask^(1<<i)] > best {
+				best = count[mask^(1<<i)]
+			}
+		}
+		if sums[mask] == 0 {
+			best++
+		}
+		count[mask] = best
+	}
+
+	// Walk the best order backwards, closing a group at each zero-sum prefix
+	var groups [][]entry
+	var current []entry
+	for mask := size - 1; mask != 0; {
+		bonus := 0
+		if sums[mask] == 0 {
+			bonus = 1
+		}
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && count[mask^(1<<i)]+bonus == count[mask] {
+				current = append(current, entries[i])
+				mask ^= 1 << i
+				break
+			}
+		}
+		if mask != 0 && sums[mask] == 0 {
+			groups = append(groups, current)
+			current = nil
+		}
+	}
+	return append(groups, current)
+}
+
+// settleGroup repeatedly matches the largest debtor with the largest
+// creditor. Each transfer clears at least one of them, so a zero-sum group
+// of k people takes at most k-1 transfers.
+func settleGroup(group []entry) []models.SettleTransfer {
+	var debtors, creditors []entry
+	for _, e := range group {
+		switch {
+		case e.cents < 0:
+			debtors = append(debtors, entry{e.userID, -e.cents})
+		case e.cents > 0:
+			creditors = append(creditors, e)
+		}
+	}
+
+	// Sort by amount, then user ID so the result is deterministic
+	byAmount := func(list []entry) {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].cents != list[j].cents {
+				return list[i].cents > list[j].cents
+			}
+			return list[i].userID < list[j].userID
+		})
+	}
+	byAmount(debtors)
+	byAmount(creditors)
+
+	var transfers []models.SettleTransfer
+	for len(debtors) > 0 && len(creditors) > 0 {
+		d, c := &debtors[0], &creditors[0]
+		amount := d.cents
+		if c.cents < amount {
+			amount = c.cents
+		}
+		transfers = append(transfers, models.SettleTransfer{
+			FromUserID:  d.userID,
+			ToUserID:    c.userID,
+			AmountCents: amount,
+		})
+		d.cents -= amount
+		c.cents -= amount
+
+		if d.cents == 0 {
+			debtors = debtors[1:]
+		}
+		if c.cents == 0 {
+			creditors = creditors[1:]
+		}
+		byAmount(debtors)
+		byAmount(creditors)
+	}
+	return transfers
+}
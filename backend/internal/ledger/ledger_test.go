@@ -0,0 +1,167 @@
+package ledger
+
+import (
+	"testing"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int64
+		method   string
+		parts    []Part
+		expected []int64
+		err      error
+	}{
+		{
+			name:     "Even split hands out leftover cents",
+			total:    1000,
+			method:   SplitEven,
+			parts:    []Part{{UserID: "a"}, {UserID: "b"}, {UserID: "c"}},
+			expected: []int64{334, 333, 333},
+		},
+		{
+			name:     "Shares are proportional",
+			total:    1000,
+			method:   SplitShares,
+			parts:    []Part{{UserID: "a", Shares: 2}, {UserID: "b", Shares: 1}},
+			expected: []int64{667, 333},
+		},
+		{
+			name:   "Shares must be positive",
+			total:  1000,
+			method: SplitShares,
+			parts:  []Part{{UserID: "a", Shares: 0}},
+			err:    ErrInvalidShares,
+		},
+		{
+			name:     "Largest amount and share count",
+			total:    MaxAmountCents,
+			method:   SplitShares,
+			parts:    []Part{{UserID: "a", Shares: MaxShares}, {UserID: "b", Shares: 1}},
+			expected: []int64{99_900_099_900, 99_900_100},
+		},
+		{
+			name:   "Amount above the limit",
+			total:  MaxAmountCents + 1,
+			method: SplitEven,
+			parts:  []Part{{UserID: "a"}},
+			err:    ErrAmountTooLarge,
+		},
+		{
+			name:   "Shares above the limit",
+			total:  1000,
+			method: SplitShares,
+			parts:  []Part{{UserID: "a", Shares: MaxShares + 1}},
+			err:    ErrInvalidShares,
+		},
+		{
+			name:   "Exact amount above the total",
+			total:  1000,
+			method: SplitExact,
+			parts:  []Part{{UserID: "a", AmountCents: 1 << 62}, {UserID: "b", AmountCents: 1 << 62}},
+			err:    ErrExactMismatch,
+		},
+		{
+			name:     "Exact amounts",
+			total:    1000,
+			method:   SplitExact,
+			parts:    []Part{{UserID: "a", AmountCents: 250}, {UserID: "b", AmountCents: 750}},
+			expected: []int64{250, 750},
+		},
+		{
+			name:   "Exact amounts must add up",
+			total:  1000,
+			method: SplitExact,
+			parts:  []Part{{UserID: "a", AmountCents: 250}},
+			err:    ErrExactMismatch,
+		},
+		{
+			name:   "No participants",
+			total:  1000,
+			method: SplitEven,
+			err:    ErrNoParticipants,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amounts, err := Split(tt.total, tt.method, tt.parts)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, amounts)
+		})
+	}
+}
+
+func TestSettle(t *testing.T) {
+	// Ann paid 90 for dinner for three, Bob paid 30 for drinks for three,
+	// and Cat already paid Ann back 10.
+	expenses := []models.ExpenseWithShares{
+		{
+			Expense: models.Expense{PaidByID: "ann", AmountCents: 9000},
+			Shares: []models.ExpenseShare{
+				{UserID: "ann", AmountCents: 3000},
+				{UserID: "bob", AmountCents: 3000},
+				{UserID: "cat", AmountCents: 3000},
+			},
+		},
+		{
+			Expense: models.Expense{PaidByID: "bob", AmountCents: 3000},
+			Shares: []models.ExpenseShare{
+				{UserID: "ann", AmountCents: 1000},
+				{UserID: "bob", AmountCents: 1000},
+				{UserID: "cat", AmountCents: 1000},
+			},
+		},
+	}
+	settlements := []models.Settlement{{FromUserID: "cat", ToUserID: "ann", AmountCents: 1000}}
+
+	balances := Balances(expenses, settlements)
+	assert.Equal(t, map[string]int64{"ann": 4000, "bob": -1000, "cat": -3000}, balances)
+
+	assert.Equal(t, []models.SettleTransfer{
+		{FromUserID: "cat", ToUserID: "ann", AmountCents: 3000},
+		{FromUserID: "bob", ToUserID: "ann", AmountCents: 1000},
+	}, Settle(balances))
+
+	assert.Empty(t, Settle(map[string]int64{"ann": 0}))
+}
+
+func TestSettleFindsFewestTransfers(t *testing.T) {
+	// Matching the largest debtor with the largest creditor takes four
+	// transfers here, but Dan and Eve can settle between themselves.
+	balances := map[string]int64{"ann": 500, "bob": -300, "cat": -200, "dan": 400, "eve": -400}
+
+	assert.ElementsMatch(t, []models.SettleTransfer{
+		{FromUserID: "eve", ToUserID: "dan", AmountCents: 400},
+		{FromUserID: "bob", ToUserID: "ann", AmountCents: 300},
+		{FromUserID: "cat", ToUserID: "ann", AmountCents: 200},
+	}, Settle(balances))
+}
+
+func TestSettleManyPeople(t *testing.T) {
+	// Too many for the exact search, settled as one group
+	balances := make(map[string]int64)
+	for i := 0; i < maxExactSettle+4; i++ {
+		balances[string(rune('a'+i))] = int64(100 * (i%3 - 1))
+	}
+	balances["z"] = 0
+	var sum int64
+	for _, cents := range balances {
+		sum += cents
+	}
+	balances["a"] -= sum // Make them add up to zero
+
+	transfers := Settle(balances)
+	for _, tr := range transfers {
+		balances[tr.FromUserID] += tr.AmountCents
+		balances[tr.ToUserID] -= tr.AmountCents
+	}
+	for userID, cents := range balances {
+		assert.Zero(t, cents, userID)
+	}
+}
@@ -0,0 +1,117 @@
+// Package mention finds the @mentions of people in feed posts.
+package mention
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"privo-club-backend/internal/models"
+)
+
+// Find resolves the "@name" mentions in content against the candidates'
+// display names, ignoring case. The longest name wins, so "@Ann Lee" mentions
+// Ann Lee rather than Ann. A name shared by several candidates is ambiguous
+// and never resolves.
+func Find(content string, candidates []models.MentionCandidate) []models.MentionSpan {
+	byName := make(map[string]string)
+	// Names as written; lowercasing can change their length ("İ")
+	spelled := make(map[string]string)
+	for _, c := range candidates {
+		name := strings.TrimSpace(c.Name)
+		key := strings.ToLower(name)
+		if key == "" {
+			continue
+		}
+		if id, seen := byName[key]; seen && id != c.UserID {
+			byName[key] = "" // Ambiguous
+			continue
+		}
+		byName[key] = c.UserID
+		spelled[key] = name
+	}
+	if len(byName) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(spelled))
+	for _, name := range spelled {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return utf8.RuneCountInString(names[i]) > utf8.RuneCountInString(names[j])
+	})
+
+	var spans []models.MentionSpan
+	offset := 0 // In UTF-16 code units
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		if r == '@' && !followsWord(content[:i]) {
+			if name, n, ok := match(content[i+1:], names); ok && byName[strings.ToLower(name)] != "" {
+				end := i + 1 + n
+				length := utf16Len(content[i:end])
+				spans = append(spans, models.MentionSpan{UserID: byName[strings.ToLower(name)], Start: offset, Length: length})
+				offset += length
+				i = end
+				continue
+			}
+		}
+		offset += utf16.RuneLen(r)
+		i += size
+	}
+	return spans
+}
+
+// match returns the first name, longest first, that s starts with and that
+// ends on a word boundary, along with the length in bytes of the text it
+// matched, which can differ from the name's own
+func match(s string, names []string) (string, int, bool) {
+	for _, name := range names {
+		n, ok := hasPrefixFold(s, name)
+		if !ok {
+			continue
+		}
+		if next, _ := utf8.DecodeRuneInString(s[n:]); n < len(s) && isWord(next) {
+			continue
+		}
+		return name, n, true
+	}
+	return "", 0, false
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case, and how
+// many bytes of s that prefix covers
+func hasPrefixFold(s, prefix string) (int, bool) {
+	n := 0
+	for _, want := range prefix {
+		if n >= len(s) {
+			return 0, false
+		}
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r != want && !strings.EqualFold(string(r), string(want)) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+// followsWord tells "email@example.com" apart from a mention
+func followsWord(before string) bool {
+	r, _ := utf8.DecodeLastRuneInString(before)
+	return before != "" && isWord(r)
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
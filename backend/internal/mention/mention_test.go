@@ -0,0 +1,86 @@
+package mention
+
+import (
+	"testing"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	candidates := []models.MentionCandidate{
+		{UserID: "user-ann", Name: "Ann"},
+		{UserID: "user-annlee", Name: "Ann Lee"},
+		{UserID: "user-zoe", Name: "Zoë"},
+		{UserID: "user-irem", Name: "İrem"},
+		{UserID: "user-sam1", Name: "Sam"},
+		{UserID: "user-sam2", Name: "sam"},
+		{UserID: "user-blank", Name: "  "},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []models.MentionSpan
+	}{
+		{
+			name:    "Single mention",
+			content: "Thanks @Ann!",
+			want:    []models.MentionSpan{{UserID: "user-ann", Start: 7, Length: 4}},
+		},
+		{
+			name:    "Longest name wins",
+			content: "@ann lee can you bring ice?",
+			want:    []models.MentionSpan{{UserID: "user-annlee", Start: 0, Length: 8}},
+		},
+		{
+			name:    "Several mentions",
+			content: "@Ann and @Zoë, see you there",
+			want: []models.MentionSpan{
+				{UserID: "user-ann", Start: 0, Length: 4},
+				{UserID: "user-zoe", Start: 9, Length: 4},
+			},
+		},
+		{
+			name:    "Offsets count UTF-16 code units",
+			content: "🎉 @Ann",
+			want:    []models.MentionSpan{{UserID: "user-ann", Start: 3, Length: 4}},
+		},
+		{
+			// "İ" is longer in bytes once lowercased
+			name:    "Length comes from the text as written",
+			content: "@İREM and @Ann",
+			want: []models.MentionSpan{
+				{UserID: "user-irem", Start: 0, Length: 5},
+				{UserID: "user-ann", Start: 10, Length: 4},
+			},
+		},
+		{
+			name:    "Names end on a word boundary",
+			content: "@Annabel is coming",
+		},
+		{
+			name:    "Email addresses are not mentions",
+			content: "write to party@Ann.example",
+		},
+		{
+			name:    "Ambiguous names do not resolve",
+			content: "@Sam are you in?",
+		},
+		{
+			name:    "Unknown names are left alone",
+			content: "@Bob @ @",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Find(tt.content, candidates))
+		})
+	}
+}
+
+func TestFindWithoutCandidates(t *testing.T) {
+	assert.Nil(t, Find("@Ann", nil))
+}
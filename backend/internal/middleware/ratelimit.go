@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit allows at most limit requests per client IP in each window.
+// Counters are kept in memory, so the limit applies per API instance; it is
+// meant to blunt spam on public endpoints, not to be an exact quota.
+// trustedProxies is the number of reverse proxies in front of the API, see
+// clientIP.
+func RateLimit(limit int, window time.Duration, trustedProxies int) func(http.Handler) http.Handler {
+	type bucket struct {
+		count   int
+		resetAt time.Time
+	}
+
+	var mu sync.Mutex
+	buckets := make(map[string]*bucket)
+	lastSweep := time.Now()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			key := clientIP(r, trustedProxies)
+
+			mu.Lock()
+			// Drop expired buckets so the map doesn't grow forever
+			if now.Sub(lastSweep) > window {
+				for k, b := range buckets {
+					if now.After(b.resetAt) {
+						delete(buckets, k)
+					}
+				}
+				lastSweep = now
+			}
+
+			b, ok := buckets[key]
+			if !ok || now.After(b.resetAt) {
+				b = &bucket{resetAt: now.Add(window)}
+				buckets[key] = b
+			}
+			b.count++
+			allowed := b.count <= limit
+			mu.Unlock()
+
+			if !allowed {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", b.resetAt.Format(http.TimeFormat))
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(map[string]string{"error": "Too many requests"})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the address of the peer the outermost trusted proxy saw.
+// Each proxy appends its peer to X-Forwarded-For, so that is the
+// trustedProxies-th hop from the right; anything further left was sent by
+// the client and can't be trusted. Without trusted proxies, or when the
+// header has fewer hops than expected, RemoteAddr is used.
+func clientIP(r *http.Request, trustedProxies int) string {
+	if trustedProxies > 0 {
+		var hops []string
+		for _, header := range r.Header.Values("X-Forwarded-For") {
+			hops = append(hops, strings.Split(header, ",")...)
+		}
+		if len(hops) >= trustedProxies {
+			if ip := strings.TrimSpace(hops[len(hops)-trustedProxies]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
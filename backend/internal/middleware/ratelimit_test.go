@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		forwardedFor   []string
+		trustedProxies int
+		want           string
+	}{
+		{name: "No proxies ignores the header", forwardedFor: []string{"1.1.1.1"}, want: "10.0.0.9"},
+		{name: "No header", trustedProxies: 1, want: "10.0.0.9"},
+		{name: "One proxy takes the right-most hop", forwardedFor: []string{"1.1.1.1, 2.2.2.2"}, trustedProxies: 1, want: "2.2.2.2"},
+		{name: "Two proxies", forwardedFor: []string{"1.1.1.1, 2.2.2.2", "3.3.3.3"}, trustedProxies: 2, want: "2.2.2.2"},
+		{name: "Fewer hops than proxies", forwardedFor: []string{"2.2.2.2"}, trustedProxies: 2, want: "10.0.0.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = "10.0.0.9:51234"
+			for _, v := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			assert.Equal(t, tt.want, clientIP(req, tt.trustedProxies))
+		})
+	}
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	handler := RateLimit(2, time.Minute, 1)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	codes := make([]int, 3)
+	for i, spoofed := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Forwarded-For", spoofed+", 203.0.113.7")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		codes[i] = rr.Code
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
@@ -45,13 +45,86 @@ type Invite struct {
 	Description     *string    `db:"description" json:"description,omitempty"`
 	Location        *string    `db:"location" json:"location,omitempty"`
 	EventDate       time.Time  `db:"eventDate" json:"eventDate"`
+	EndDate         *time.Time `db:"endDate" json:"endDate,omitempty"` // When nil the event lasts DefaultEventDuration
 	SenderID        string     `db:"senderId" json:"senderId"`
 	CircleID        *string    `db:"circleId" json:"circleId,omitempty"`
 	MapLink         *string    `db:"map_link" json:"mapLink,omitempty"`
 	IsVaultUnlocked bool       `db:"isVaultUnlocked" json:"isVaultUnlocked"`
 	VaultUnlockDate *time.Time `db:"vaultUnlockDate" json:"vaultUnlockDate,omitempty"`
+	VaultNotifiedAt *time.Time `db:"vaultNotifiedAt" json:"-"`   // When attendees were told the vault opened
+	IsDateTBD       bool       `db:"isDateTBD" json:"isDateTBD"` // Date is being decided by a poll; EventDate is the earliest candidate
+	VenueID         *string    `db:"venueId" json:"venueId,omitempty"`
 	CreatedAt       time.Time  `db:"createdAt" json:"createdAt"`
 	UpdatedAt       time.Time  `db:"updatedAt" json:"updatedAt"`
+	Place
+	VaultPolicy
+}
+
+// VaultPolicy decides when the Memory Vault opens. See the vault package for
+// the policy names and how they are evaluated.
+type VaultPolicy struct {
+	UnlockPolicy     string     `db:"vaultPolicy" json:"vaultPolicy"`
+	UnlockAfterHours *int       `db:"vaultUnlockAfterHours" json:"vaultUnlockAfterHours,omitempty"` // DELAY: hours after the event ends
+	UnlockAt         *time.Time `db:"vaultUnlockAt" json:"vaultUnlockAt,omitempty"`                 // ON_DATE
+	MinContributors  *int       `db:"vaultMinContributors" json:"vaultMinContributors,omitempty"`   // CONTRIBUTORS: distinct people who added media
+}
+
+// DefaultEventDuration is assumed for invites without an end time
+const DefaultEventDuration = 3 * time.Hour
+
+// Ends returns the end time, falling back to DefaultEventDuration
+func (i Invite) Ends() time.Time {
+	if i.EndDate != nil {
+		return *i.EndDate
+	}
+	return i.EventDate.Add(DefaultEventDuration)
+}
+
+// Place is the structured form of an invite's location. Location stays the
+// free text shown to guests; these fields add components and coordinates.
+type Place struct {
+	PlaceName   *string  `db:"placeName" json:"placeName,omitempty"`
+	AddressLine *string  `db:"addressLine" json:"addressLine,omitempty"`
+	City        *string  `db:"city" json:"city,omitempty"`
+	Region      *string  `db:"region" json:"region,omitempty"`
+	PostalCode  *string  `db:"postalCode" json:"postalCode,omitempty"`
+	Country     *string  `db:"country" json:"country,omitempty"`
+	Latitude    *float64 `db:"latitude" json:"latitude,omitempty"`
+	Longitude   *float64 `db:"longitude" json:"longitude,omitempty"`
+}
+
+// HasCoordinates reports whether both latitude and longitude are set
+func (p Place) HasCoordinates() bool {
+	return p.Latitude != nil && p.Longitude != nil
+}
+
+// Venue is a place a circle meets at regularly
+type Venue struct {
+	ID          string    `db:"id" json:"id"`
+	CircleID    string    `db:"circleId" json:"circleId"`
+	Name        string    `db:"name" json:"name"`
+	Address     *string   `db:"address" json:"address,omitempty"`
+	MapLink     *string   `db:"mapLink" json:"mapLink,omitempty"`
+	Notes       *string   `db:"notes" json:"notes,omitempty"`
+	Capacity    *int      `db:"capacity" json:"capacity,omitempty"`
+	Latitude    *float64  `db:"latitude" json:"latitude,omitempty"`
+	Longitude   *float64  `db:"longitude" json:"longitude,omitempty"`
+	CreatedByID string    `db:"createdById" json:"createdById"`
+	CreatedAt   time.Time `db:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time `db:"updatedAt" json:"updatedAt"`
+}
+
+// VenueWithUsage adds how often the circle has used the venue
+type VenueWithUsage struct {
+	Venue
+	UseCount   int        `db:"useCount" json:"useCount"`
+	LastUsedAt *time.Time `db:"lastUsedAt" json:"lastUsedAt,omitempty"`
+}
+
+// Distance is how far an invite is from the position the user shared
+type Distance struct {
+	Km   float64 `json:"km"`
+	Text string  `json:"text"`
 }
 
 // RSVP mirrors the RSVP model in Prisma
@@ -67,16 +140,285 @@ type RSVP struct {
 	UpdatedAt  time.Time `db:"updatedAt" json:"updatedAt"`
 }
 
-// EventFeedItem mirrors the EventFeedItem model in Prisma
-type EventFeedItem struct {
+// InviteInvitee is a user invited directly to an invite, by account or by email
+type InviteInvitee struct {
+	ID        string    `db:"id" json:"id"`
+	InviteID  string    `db:"inviteId" json:"inviteId"`
+	UserID    *string   `db:"userId" json:"userId,omitempty"`
+	Email     *string   `db:"email" json:"email,omitempty"` // Set for email invites, kept after resolution
+	CreatedAt time.Time `db:"createdAt" json:"createdAt"`
+}
+
+// DatePollOption is a candidate time proposed by the host of a date-TBD invite
+type DatePollOption struct {
 	ID        string    `db:"id" json:"id"`
 	InviteID  string    `db:"inviteId" json:"inviteId"`
+	StartsAt  time.Time `db:"startsAt" json:"startsAt"`
+	CreatedAt time.Time `db:"createdAt" json:"createdAt"`
+}
+
+// DatePollVote is one user's availability for a candidate time
+type DatePollVote struct {
+	ID        string    `db:"id" json:"id"`
+	OptionID  string    `db:"optionId" json:"optionId"`
 	UserID    string    `db:"userId" json:"userId"`
-	Content   string    `db:"content" json:"content"`
-	Type      string    `db:"type" json:"type"` // UPDATE, CHAT
+	Vote      string    `db:"vote" json:"vote"` // YES, IF_NEEDED, NO
 	CreatedAt time.Time `db:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `db:"updatedAt" json:"updatedAt"`
+}
+
+// SignupSheet is a potluck or volunteer list attached to an invite
+type SignupSheet struct {
+	ID              string     `db:"id" json:"id"`
+	InviteID        string     `db:"inviteId" json:"inviteId"`
+	Title           string     `db:"title" json:"title"`
+	Description     *string    `db:"description" json:"description,omitempty"`
+	CreatedByID     string     `db:"createdById" json:"createdById"`
+	SummaryPostedAt *time.Time `db:"summaryPostedAt" json:"-"`
+	CreatedAt       time.Time  `db:"createdAt" json:"createdAt"`
+}
+
+// SignupItem is a dish or shift with the number of people/portions needed
+type SignupItem struct {
+	ID        string    `db:"id" json:"id"`
+	SheetID   string    `db:"sheetId" json:"sheetId"`
+	Name      string    `db:"name" json:"name"`
+	Quantity  int       `db:"quantity" json:"quantity"`
+	Position  int       `db:"position" json:"position"`
+	CreatedAt time.Time `db:"createdAt" json:"createdAt"`
+}
+
+type SignupClaim struct {
+	ID        string    `db:"id" json:"id"`
+	ItemID    string    `db:"itemId" json:"itemId"`
+	UserID    string    `db:"userId" json:"userId"`
+	Quantity  int       `db:"quantity" json:"quantity"`
+	CreatedAt time.Time `db:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `db:"updatedAt" json:"updatedAt"`
+}
+
+// Expense is a cost one attendee paid on behalf of the group, in cents
+type Expense struct {
+	ID          string    `db:"id" json:"id"`
+	InviteID    string    `db:"inviteId" json:"inviteId"`
+	PaidByID    string    `db:"paidById" json:"paidById"`
+	Description string    `db:"description" json:"description"`
+	AmountCents int64     `db:"amountCents" json:"amountCents"`
+	SplitType   string    `db:"splitType" json:"splitType"` // EVEN, SHARES, EXACT
+	CreatedByID string    `db:"createdById" json:"createdById"`
+	CreatedAt   time.Time `db:"createdAt" json:"createdAt"`
+}
+
+// ExpenseShare is what one participant owes for an expense
+type ExpenseShare struct {
+	ID          string `db:"id" json:"id"`
+	ExpenseID   string `db:"expenseId" json:"expenseId"`
+	UserID      string `db:"userId" json:"userId"`
+	Shares      *int   `db:"shares" json:"shares,omitempty"`
+	AmountCents int64  `db:"amountCents" json:"amountCents"`
+}
+
+// Settlement records money paid back from one attendee to another
+type Settlement struct {
+	ID          string    `db:"id" json:"id"`
+	InviteID    string    `db:"inviteId" json:"inviteId"`
+	FromUserID  string    `db:"fromUserId" json:"fromUserId"`
+	ToUserID    string    `db:"toUserId" json:"toUserId"`
+	AmountCents int64     `db:"amountCents" json:"amountCents"`
+	CreatedByID string    `db:"createdById" json:"createdById"`
+	CreatedAt   time.Time `db:"createdAt" json:"createdAt"`
+}
+
+// RideOffer is a driver offering seats to an event
+type RideOffer struct {
+	ID            string    `db:"id" json:"id"`
+	InviteID      string    `db:"inviteId" json:"inviteId"`
+	DriverID      string    `db:"driverId" json:"driverId"`
+	Seats         int       `db:"seats" json:"seats"`
+	DepartureArea string    `db:"departureArea" json:"departureArea"`
+	DepartureTime time.Time `db:"departureTime" json:"departureTime"`
+	Notes         *string   `db:"notes" json:"notes,omitempty"`
+	CreatedAt     time.Time `db:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time `db:"updatedAt" json:"updatedAt"`
+}
+
+// RideRequest is someone looking for a ride, matched once OfferID is set
+type RideRequest struct {
+	ID         string    `db:"id" json:"id"`
+	InviteID   string    `db:"inviteId" json:"inviteId"`
+	RiderID    string    `db:"riderId" json:"riderId"`
+	OfferID    *string   `db:"offerId" json:"offerId,omitempty"`
+	PickupArea *string   `db:"pickupArea" json:"pickupArea,omitempty"`
+	Notes      *string   `db:"notes" json:"notes,omitempty"`
+	CreatedAt  time.Time `db:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time `db:"updatedAt" json:"updatedAt"`
+}
+
+// CheckIn records that someone actually showed up to an event
+type CheckIn struct {
+	ID            string    `db:"id" json:"id"`
+	InviteID      string    `db:"inviteId" json:"inviteId"`
+	UserID        *string   `db:"userId" json:"userId,omitempty"`
+	GuestRSVPID   *string   `db:"guestRsvpId" json:"guestRsvpId,omitempty"`
+	Name          *string   `db:"name" json:"name,omitempty"`
+	GuestCount    int       `db:"guestCount" json:"guestCount"`
+	CheckedInByID string    `db:"checkedInById" json:"checkedInById"`
+	CheckedInAt   time.Time `db:"checkedInAt" json:"checkedInAt"`
+}
+
+// InviteTemplate is a reusable starting point for new invites
+type InviteTemplate struct {
+	ID          string    `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	OwnerID     string    `db:"ownerId" json:"ownerId"`
+	CircleID    *string   `db:"circleId" json:"circleId,omitempty"` // Shared with the circle when set
+	Title       string    `db:"title" json:"title"`
+	Description *string   `db:"description" json:"description,omitempty"`
+	Location    *string   `db:"location" json:"location,omitempty"`
+	MapLink     *string   `db:"mapLink" json:"mapLink,omitempty"`
+	CreatedAt   time.Time `db:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time `db:"updatedAt" json:"updatedAt"`
+	Place
+}
+
+// InviteShareLink is a revocable public link to an invite
+type InviteShareLink struct {
+	ID          string     `db:"id" json:"id"`
+	InviteID    string     `db:"inviteId" json:"inviteId"`
+	Token       string     `db:"token" json:"token"`
+	CreatedByID string     `db:"createdById" json:"createdById"`
+	CreatedAt   time.Time  `db:"createdAt" json:"createdAt"`
+	RevokedAt   *time.Time `db:"revokedAt" json:"revokedAt,omitempty"`
 }
 
+// GuestRSVP is an RSVP from someone without an account, made through a share link
+type GuestRSVP struct {
+	ID          string    `db:"id" json:"id"`
+	InviteID    string    `db:"inviteId" json:"inviteId"`
+	ShareLinkID *string   `db:"shareLinkId" json:"-"`
+	Name        string    `db:"name" json:"name"`
+	Email       string    `db:"email" json:"email,omitempty"` // Only shown to the host
+	Status      string    `db:"status" json:"status"`         // YES, NO, MAYBE
+	GuestCount  int       `db:"guestCount" json:"guestCount"`
+	Dietary     *string   `db:"dietary" json:"dietary,omitempty"`
+	Note        *string   `db:"note" json:"note,omitempty"`
+	CreatedAt   time.Time `db:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time `db:"updatedAt" json:"updatedAt"`
+}
+
+// InviteRecap is the stored summary of an event, generated when its vault unlocks
+type InviteRecap struct {
+	InviteID    string    `db:"inviteId" json:"inviteId"`
+	Manifest    []byte    `db:"manifest" json:"-"` // RecapManifest as JSON
+	HTML        string    `db:"html" json:"-"`
+	PublicToken *string   `db:"publicToken" json:"-"`
+	GeneratedAt time.Time `db:"generatedAt" json:"generatedAt"`
+	// Set when a post it may quote changed; the recap job rebuilds it
+	StaleAt *time.Time `db:"staleAt" json:"-"`
+}
+
+// EventFeedItem mirrors the EventFeedItem model in Prisma
+type EventFeedItem struct {
+	ID        string     `db:"id" json:"id"`
+	InviteID  string     `db:"inviteId" json:"inviteId"`
+	UserID    string     `db:"userId" json:"userId"`
+	Content   string     `db:"content" json:"content"`
+	Type      string     `db:"type" json:"type"`                   // UPDATE, CHAT, CAPSULE
+	ParentID  *string    `db:"parentId" json:"parentId,omitempty"` // Set on replies
+	CreatedAt time.Time  `db:"createdAt" json:"createdAt"`
+	EditedAt  *time.Time `db:"editedAt" json:"editedAt,omitempty"`
+
+	// Moderation. Removed posts have no content left and show as tombstones.
+	HiddenAt         *time.Time `db:"hiddenAt" json:"hiddenAt,omitempty"`
+	HiddenByID       *string    `db:"hiddenById" json:"-"`
+	RemovedAt        *time.Time `db:"removedAt" json:"removedAt,omitempty"`
+	RemovedByID      *string    `db:"removedById" json:"-"`
+	ModerationReason *string    `db:"moderationReason" json:"moderationReason,omitempty"`
+
+	Mentions []MentionSpan `db:"-" json:"mentions,omitempty"`
+}
+
+// Redact blanks what the viewer may not read. Hidden posts stay readable for
+// their author and moderators only, and only they learn why a post was
+// hidden or removed.
+func (f *EventFeedItem) Redact(viewerID string, moderator bool) {
+	if moderator || f.UserID == viewerID {
+		return
+	}
+	f.ModerationReason = nil
+	if f.HiddenAt != nil {
+		f.Content = ""
+		f.Mentions = nil
+		f.EditedAt = nil
+	}
+}
+
+// FeedPostRevision is the text a post had before an edit
+type FeedPostRevision struct {
+	ID         string    `db:"id" json:"id"`
+	FeedItemID string    `db:"feedItemId" json:"feedItemId"`
+	Content    string    `db:"content" json:"content"`
+	CreatedAt  time.Time `db:"createdAt" json:"replacedAt"` // When the edit replaced it
+}
+
+// FeedMention records that a post mentions a user
+type FeedMention struct {
+	ID         string     `db:"id" json:"id"`
+	FeedItemID string     `db:"feedItemId" json:"feedItemId"`
+	UserID     string     `db:"userId" json:"userId"`
+	Start      int        `db:"start" json:"start"`
+	Length     int        `db:"length" json:"length"`
+	CreatedAt  time.Time  `db:"createdAt" json:"createdAt"`
+	NotifiedAt *time.Time `db:"notifiedAt" json:"notifiedAt,omitempty"`
+}
+
+// MentionSpan locates an "@name" in a post's content so clients can link it.
+// Start and Length count UTF-16 code units, the way JavaScript indexes strings.
+type MentionSpan struct {
+	FeedItemID string `db:"feedItemId" json:"-"`
+	UserID     string `db:"userId" json:"userId"`
+	Start      int    `db:"start" json:"start"`
+	Length     int    `db:"length" json:"length"`
+}
+
+// MentionCandidate is someone who can see the invite, and so can be mentioned
+type MentionCandidate struct {
+	UserID string `db:"userId"`
+	Name   string `db:"name"`
+}
+
+// MentionRecipient is a mentioned user to notify about a post
+type MentionRecipient struct {
+	InviteID   string  `db:"inviteId"`
+	Title      string  `db:"title"`
+	FeedItemID string  `db:"feedItemId"`
+	Content    string  `db:"content"`
+	AuthorName *string `db:"authorName"`
+	UserID     string  `db:"userId"`
+	Name       *string `db:"name"`
+	Email      *string `db:"email"`
+}
+
+// FeedReaction is one user's emoji reaction to a feed post
+type FeedReaction struct {
+	ID         string    `db:"id" json:"id"`
+	FeedItemID string    `db:"feedItemId" json:"feedItemId"`
+	UserID     string    `db:"userId" json:"userId"`
+	Emoji      string    `db:"emoji" json:"emoji"`
+	CreatedAt  time.Time `db:"createdAt" json:"createdAt"`
+}
+
+// Feed item types that can be posted directly
+const (
+	FeedTypeUpdate = "UPDATE"
+	FeedTypeChat   = "CHAT"
+)
+
+// FeedTypeCapsule marks a time-capsule message. It stays hidden from
+// everyone, host included, until the invite's vault unlocks, and then shows
+// up in the feed at vaultUnlockDate together with the other capsule messages.
+const FeedTypeCapsule = "CAPSULE"
+
 // MediaItem mirrors the MediaItem model in Prisma
 type MediaItem struct {
 	ID        string    `db:"id" json:"id"`
@@ -88,6 +430,49 @@ type MediaItem struct {
 	CreatedAt time.Time `db:"createdAt" json:"createdAt"`
 }
 
+// StoredMedia is a media item along with whether its vault is still sealed
+type StoredMedia struct {
+	MediaItem
+	IsVaultUnlocked bool `db:"isVaultUnlocked"`
+}
+
+// InviteAccess is the raw relationship between a user and an invite, used by the access policy
+type InviteAccess struct {
+	InviteID       string  `db:"inviteId"`
+	SenderID       string  `db:"senderId"`
+	CircleID       *string `db:"circleId"`
+	IsActiveMember bool    `db:"isActiveMember"`
+	IsInvitee      bool    `db:"isInvitee"`
+	CircleRole     *string `db:"circleRole"` // Set while an ACTIVE member
+}
+
+// ReminderTarget is one recipient of a scheduled reminder for an upcoming invite
+type ReminderTarget struct {
+	InviteID   string    `db:"inviteId" json:"inviteId"`
+	Title      string    `db:"title" json:"title"`
+	EventDate  time.Time `db:"eventDate" json:"eventDate"`
+	Location   *string   `db:"location" json:"location,omitempty"`
+	UserID     string    `db:"userId" json:"userId"`
+	Name       *string   `db:"name" json:"name,omitempty"`
+	Email      *string   `db:"email" json:"email,omitempty"`
+	RSVPStatus *string   `db:"rsvpStatus" json:"rsvpStatus,omitempty"` // nil when the member hasn't answered
+}
+
+// VaultCandidate is a locked vault the unlock worker has to evaluate
+type VaultCandidate struct {
+	Invite
+	Contributors int `db:"contributors"` // Distinct people who added media
+}
+
+// VaultRecipient is an attendee to tell that a vault opened
+type VaultRecipient struct {
+	InviteID string  `db:"inviteId"`
+	Title    string  `db:"title"`
+	UserID   string  `db:"userId"`
+	Name     *string `db:"name"`
+	Email    *string `db:"email"`
+}
+
 // Composite Structs for API Responses
 
 type InviteListResponse struct {
@@ -99,6 +484,35 @@ type InviteListResponse struct {
 	} `json:"_count"`
 }
 
+// InviteListFilter narrows ListInvites; zero values don't filter
+type InviteListFilter struct {
+	When       string // InvitesUpcoming, InvitesPast or empty for both
+	CircleID   *string
+	HostedOnly bool
+	RSVPStatus string // YES, NO, MAYBE, or NONE for invites the user hasn't answered
+	From       *time.Time
+	To         *time.Time
+	After      *InviteCursor
+	Limit      int
+	Now        time.Time
+}
+
+const (
+	InvitesUpcoming = "upcoming"
+	InvitesPast     = "past" // Listed most recent first
+)
+
+// InviteCursor is the position of the last invite on the previous page
+type InviteCursor struct {
+	EventDate time.Time
+	ID        string
+}
+
+type InviteListPage struct {
+	Invites    []InviteListResponse `json:"invites"`
+	NextCursor *string              `json:"nextCursor"`
+}
+
 type CircleListResponse struct {
 	Circle
 	Owner User `json:"owner"`
@@ -124,18 +538,350 @@ type RSVPWithUser struct {
 	User User `json:"user"`
 }
 
+type InviteeWithUser struct {
+	InviteInvitee
+	User *User `json:"user,omitempty"` // nil until the email resolves to an account
+}
+
+type DatePollVoteWithUser struct {
+	DatePollVote
+	User User `json:"user"`
+}
+
+// DatePollOptionSummary is a candidate time with its tallied votes
+type DatePollOptionSummary struct {
+	DatePollOption
+	Yes      int                    `json:"yes"`
+	IfNeeded int                    `json:"ifNeeded"`
+	No       int                    `json:"no"`
+	Votes    []DatePollVoteWithUser `json:"votes"`
+}
+
+type SignupClaimWithUser struct {
+	SignupClaim
+	User User `json:"user"`
+}
+
+type SignupItemWithClaims struct {
+	SignupItem
+	Claimed int                   `json:"claimed"`
+	Claims  []SignupClaimWithUser `json:"claims"`
+}
+
+type SignupSheetWithItems struct {
+	SignupSheet
+	Items []SignupItemWithClaims `json:"items"`
+}
+
+// SignupSummaryTarget is a sheet whose event is close enough for the unclaimed summary
+type SignupSummaryTarget struct {
+	SignupSheet
+	InviteTitle string `db:"inviteTitle"`
+}
+
+// UnclaimedSignupItem is an item that still needs Remaining more claims
+type UnclaimedSignupItem struct {
+	ID        string `db:"id"`
+	Name      string `db:"name"`
+	Remaining int    `db:"remaining"`
+}
+
+type ExpenseWithShares struct {
+	Expense
+	Shares []ExpenseShare `json:"shares"`
+}
+
+type ExpenseBalance struct {
+	UserID       string `json:"userId"`
+	BalanceCents int64  `json:"balanceCents"` // Positive: is owed money, negative: owes money
+}
+
+// SettleTransfer is a suggested payment that clears balances
+type SettleTransfer struct {
+	FromUserID  string `json:"fromUserId"`
+	ToUserID    string `json:"toUserId"`
+	AmountCents int64  `json:"amountCents"`
+}
+
+// ExpenseLedger is the full expense picture of an invite
+type ExpenseLedger struct {
+	Attendees   []User              `json:"attendees"`
+	Expenses    []ExpenseWithShares `json:"expenses"`
+	Settlements []Settlement        `json:"settlements"`
+	Balances    []ExpenseBalance    `json:"balances"`
+	Transfers   []SettleTransfer    `json:"transfers"`
+}
+
+// ReportGuest is one response, from a member or a share-link guest, in the host report
+type ReportGuest struct {
+	Kind              string    `db:"kind" json:"kind"` // MEMBER, GUEST
+	Name              string    `db:"name" json:"name"`
+	Email             *string   `db:"email" json:"email,omitempty"`
+	Status            string    `db:"status" json:"status"`
+	GuestCount        int       `db:"guestCount" json:"guestCount"`
+	Dietary           *string   `db:"dietary" json:"dietary,omitempty"`
+	Note              *string   `db:"note" json:"note,omitempty"`
+	RespondedAt       time.Time `db:"respondedAt" json:"respondedAt"`
+	DietaryCategories []string  `db:"-" json:"dietaryCategories"`
+}
+
+// StatusTotal counts responses and people (guestCount included) for one RSVP status
+type StatusTotal struct {
+	Responses int `json:"responses"`
+	People    int `json:"people"`
+}
+
+// DietaryCount is a normalized dietary category among attending guests
+type DietaryCount struct {
+	Category string   `json:"category"`
+	Count    int      `json:"count"`
+	Names    []string `json:"names"`
+}
+
+// HeadcountReport is the host's headcount and dietary summary for caterers
+type HeadcountReport struct {
+	InviteID    string                 `json:"inviteId"`
+	Title       string                 `json:"title"`
+	EventDate   time.Time              `json:"eventDate"`
+	Totals      map[string]StatusTotal `json:"totals"` // Keyed by YES, MAYBE, NO
+	Dietary     []DietaryCount         `json:"dietary"`
+	Guests      []ReportGuest          `json:"guests"`
+	GeneratedAt time.Time              `json:"generatedAt"`
+}
+
+// RecapManifest summarizes a finished event for its recap page. It only holds
+// what is safe to show through the public link, so no emails or addresses.
+type RecapManifest struct {
+	InviteID    string         `json:"inviteId"`
+	Title       string         `json:"title"`
+	Description *string        `json:"description,omitempty"`
+	Place       *string        `json:"place,omitempty"` // Place name or city
+	EventDate   time.Time      `json:"eventDate"`
+	EndDate     time.Time      `json:"endDate"`
+	Host        RecapPerson    `json:"host"`
+	Attendees   []RecapPerson  `json:"attendees"`
+	Headcount   int            `json:"headcount"` // Attendees plus the guests they brought
+	Highlights  []RecapPost    `json:"highlights"`
+	Media       []RecapMedia   `json:"media"`
+	MediaCount  int            `json:"mediaCount"`
+	Signups     *RecapSignups  `json:"signups,omitempty"`  // Only when the event had sign-up sheets
+	Expenses    *RecapExpenses `json:"expenses,omitempty"` // Only when expenses were logged
+	GeneratedAt time.Time      `json:"generatedAt"`
+}
+
+type RecapPerson struct {
+	Name       string  `json:"name"`
+	Image      *string `json:"image,omitempty"`
+	GuestCount int     `json:"guestCount"` // Party size, including themselves
+}
+
+type RecapPost struct {
+	Author    string    `json:"author"`
+	Content   string    `json:"content"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type RecapMedia struct {
+	URL     string  `json:"url"`
+	Type    string  `json:"type"`
+	Caption *string `json:"caption,omitempty"`
+	Author  string  `json:"author"`
+}
+
+type RecapSignups struct {
+	Sheets      int `json:"sheets"`
+	Items       int `json:"items"`
+	FilledItems int `json:"filledItems"`
+	Claims      int `json:"claims"`
+}
+
+type RecapExpenses struct {
+	Count      int   `json:"count"`
+	TotalCents int64 `json:"totalCents"`
+}
+
+// RecapView is the recap as returned to attendees
+type RecapView struct {
+	Manifest    RecapManifest `json:"manifest"`
+	PublicToken *string       `json:"publicToken,omitempty"` // Only shown to the host
+	GeneratedAt time.Time     `json:"generatedAt"`
+}
+
+type RideOfferWithDriver struct {
+	RideOffer
+	Driver User `json:"driver"`
+}
+
+type RideRequestWithRider struct {
+	RideRequest
+	Rider User `json:"rider"`
+}
+
+// RideOfferView is an offer as seen by one user. Driver, notes and riders are
+// only filled in for the driver and the riders who joined.
+type RideOfferView struct {
+	ID            string    `json:"id"`
+	Seats         int       `json:"seats"`
+	SeatsTaken    int       `json:"seatsTaken"`
+	DepartureArea string    `json:"departureArea"`
+	DepartureTime time.Time `json:"departureTime"`
+	Driver        *User     `json:"driver,omitempty"`
+	Notes         *string   `json:"notes,omitempty"`
+	Riders        []User    `json:"riders,omitempty"`
+	IsMine        bool      `json:"isMine"`
+	Joined        bool      `json:"joined"`
+}
+
+// RideRequestView is a request as seen by one user. The rider is only shown
+// to themselves and to the driver they are matched with.
+type RideRequestView struct {
+	ID         string  `json:"id"`
+	PickupArea *string `json:"pickupArea,omitempty"`
+	Matched    bool    `json:"matched"`
+	Rider      *User   `json:"rider,omitempty"`
+	Notes      *string `json:"notes,omitempty"`
+	IsMine     bool    `json:"isMine"`
+}
+
+type RideBoard struct {
+	Offers   []RideOfferView   `json:"offers"`
+	Requests []RideRequestView `json:"requests"`
+}
+
+// TicketHolder is the RSVP (member or guest) a check-in token was issued for
+type TicketHolder struct {
+	UserID      *string `db:"userId"`
+	GuestRSVPID *string `db:"guestRsvpId"`
+	Name        *string `db:"name"`
+	Status      string  `db:"status"`
+	GuestCount  int     `db:"guestCount"`
+}
+
+// AttendanceCounts compares who said YES with who actually showed up (headcounts)
+type AttendanceCounts struct {
+	Expected  int `db:"expected" json:"expected"`
+	CheckedIn int `db:"checkedIn" json:"checkedIn"`
+	WalkIns   int `db:"walkIns" json:"walkIns"`
+}
+
+type CheckInTicket struct {
+	Token  string `json:"token"`
+	QRCode string `json:"qrCode"` // PNG data URL
+}
+
+type CheckInResponse struct {
+	CheckIn          CheckIn          `json:"checkIn"`
+	AlreadyCheckedIn bool             `json:"alreadyCheckedIn"`
+	Attendance       AttendanceCounts `json:"attendance"`
+}
+
+type CheckInList struct {
+	Attendance AttendanceCounts `json:"attendance"`
+	CheckIns   []CheckIn        `json:"checkIns"`
+}
+
 type FeedWithUser struct {
 	EventFeedItem
-	User User `json:"user"`
+	User       User            `json:"user"`
+	ReplyCount int             `db:"replyCount" json:"replyCount"`
+	Reactions  []ReactionCount `db:"-" json:"reactions"`
+}
+
+// ReactionCount is how many people reacted to a post with one emoji
+type ReactionCount struct {
+	FeedItemID string `db:"feedItemId" json:"-"`
+	Emoji      string `db:"emoji" json:"emoji"`
+	Count      int    `db:"count" json:"count"`
+	Reacted    bool   `db:"reacted" json:"reacted"` // Whether the viewer is one of them
+}
+
+// FeedPost is a feed item as listed in the feed, where released capsule
+// messages are placed at the moment the vault opened
+type FeedPost struct {
+	FeedWithUser
+	PostedAt time.Time `db:"postedAt" json:"postedAt"`
+}
+
+// FeedListFilter selects one page of an invite's feed
+type FeedListFilter struct {
+	Type  string // UPDATE or CHAT; empty lists everything
+	After *FeedCursor
+	Limit int
+}
+
+// FeedCursor is the position of the last post on the previous page
+type FeedCursor struct {
+	PostedAt time.Time
+	ID       string
+}
+
+type FeedPage struct {
+	Items      []FeedPost `json:"items"`
+	NextCursor *string    `json:"nextCursor"`
+}
+
+// MentionedPost is a post that mentions the viewer, wherever it was posted
+type MentionedPost struct {
+	FeedPost
+	InviteTitle string `db:"inviteTitle" json:"inviteTitle"`
+}
+
+type MentionPage struct {
+	Items      []MentionedPost `json:"items"`
+	NextCursor *string         `json:"nextCursor"`
 }
 
 type InviteDetails struct {
 	Invite
-	Sender     User               `json:"sender"`
-	Circle     *CircleWithMembers `json:"circle,omitempty"`
-	RSVPs      []RSVPWithUser     `json:"rsvps"`
-	FeedItems  []FeedWithUser     `json:"feedItems"`
-	MediaItems []MediaItem        `json:"mediaItems"`
+	Sender       User                    `json:"sender"`
+	Circle       *CircleWithMembers      `json:"circle,omitempty"`
+	RSVPs        []RSVPWithUser          `json:"rsvps"`
+	Invitees     []InviteeWithUser       `json:"invitees"`
+	GuestRSVPs   []GuestRSVP             `json:"guestRsvps"`
+	DatePoll     []DatePollOptionSummary `json:"datePoll,omitempty"` // Only while the date is TBD
+	SignupSheets []SignupSheetWithItems  `json:"signupSheets"`
+	FeedItems    []FeedWithUser          `json:"feedItems"`
+	MediaItems   []MediaItem             `json:"mediaItems"` // Only the viewer's own uploads while the vault is sealed
+	Vault        VaultSummary            `json:"vault"`
+	Distance     *Distance               `json:"distance,omitempty"` // Only when the request includes lat/lng
+}
+
+// VaultSummary describes the Memory Vault without revealing sealed media
+type VaultSummary struct {
+	Sealed           bool       `json:"sealed"`
+	ItemCount        int        `json:"itemCount"`
+	ContributorCount int        `json:"contributorCount"`
+	UnlocksAt        *time.Time `json:"unlocksAt,omitempty"` // Only for time-based policies
+}
+
+// NearbyInvite is an upcoming invite returned by the "near me" search
+type NearbyInvite struct {
+	Invite
+	Distance Distance `json:"distance"`
+}
+
+// PublicInviteView is the limited view of an invite shown through a share link
+type PublicInviteView struct {
+	ID          string     `db:"id" json:"id"`
+	Title       string     `db:"title" json:"title"`
+	Description *string    `db:"description" json:"description,omitempty"`
+	Location    *string    `db:"location" json:"location,omitempty"`
+	MapLink     *string    `db:"map_link" json:"mapLink,omitempty"`
+	EventDate   time.Time  `db:"eventDate" json:"eventDate"`
+	EndDate     *time.Time `db:"endDate" json:"endDate,omitempty"`
+	HostName    *string    `db:"hostName" json:"hostName,omitempty"`
+	ShareLinkID *string    `db:"shareLinkId" json:"-"`
+	Going       int        `db:"going" json:"going"` // Headcount including guests
+	Maybe       int        `db:"maybe" json:"maybe"`
+}
+
+// GuestRSVPResponse is returned to a guest after responding, with the link they can use to change their answer
+type GuestRSVPResponse struct {
+	RSVP        GuestRSVP        `json:"rsvp"`
+	Invite      PublicInviteView `json:"invite"`
+	ManageToken string           `json:"manageToken"`
+	ManageURL   string           `json:"manageUrl"`
 }
 
 type InviteWithCount struct {
@@ -165,12 +911,55 @@ type JoinCircleRequest struct {
 }
 
 type CreateInviteRequest struct {
-	Title       string    `json:"title"`
-	Description *string   `json:"description"`
-	Location    *string   `json:"location"`
-	EventDate   time.Time `json:"eventDate"`
-	CircleID    *string   `json:"circleId"`
-	MapLink     *string   `json:"mapLink"`
+	Title       string           `json:"title"`
+	Description *string          `json:"description"`
+	Location    *string          `json:"location"`
+	EventDate   time.Time        `json:"eventDate"`
+	EndDate     *time.Time       `json:"endDate"`
+	CircleID    *string          `json:"circleId"`
+	MapLink     *string          `json:"mapLink"`
+	Invitees    []InviteeRequest `json:"invitees"`
+	DateOptions []time.Time      `json:"dateOptions"` // Two or more candidates start a date poll instead of a fixed eventDate
+	VenueID     *string          `json:"venueId"`     // Fills location, mapLink and place from a saved circle venue
+	Place                        // Optional; coordinates are otherwise taken from mapLink or geocoded
+	VaultPolicy                  // Optional; defaults to opening 24 hours after the event
+}
+
+type VenueRequest struct {
+	Name     string  `json:"name"`
+	Address  *string `json:"address"`
+	MapLink  *string `json:"mapLink"`
+	Notes    *string `json:"notes"`
+	Capacity *int    `json:"capacity"`
+}
+
+// UpdateLocationRequest replaces an invite's location
+type UpdateLocationRequest struct {
+	Location *string `json:"location"`
+	MapLink  *string `json:"mapLink"`
+	Place
+}
+
+// InviteRelations are rows created together with a new invite
+type InviteRelations struct {
+	Invitees     []InviteInvitee
+	DateOptions  []DatePollOption
+	SignupSheets []SignupSheet
+	SignupItems  []SignupItem // Items reference their sheet through SheetID
+}
+
+func (r InviteRelations) IsEmpty() bool {
+	return len(r.Invitees) == 0 && len(r.DateOptions) == 0 && len(r.SignupSheets) == 0
+}
+
+// InviteeRequest identifies a direct invitee by user ID or by email address
+type InviteeRequest struct {
+	UserID *string `json:"userId"`
+	Email  *string `json:"email"`
+}
+
+type AddInviteesRequest struct {
+	Invitees []InviteeRequest `json:"invitees"`
 }
 
 type RSVPRequest struct {
@@ -180,18 +969,194 @@ type RSVPRequest struct {
 	Note       *string `json:"note"`
 }
 
+// RSVPResponse lists the user's other YES events that overlap this one when
+// they answer YES, so the client can warn about double booking.
+type RSVPResponse struct {
+	Success  bool             `json:"success"`
+	Overlaps []CalendarInvite `json:"overlaps,omitempty"`
+}
+
+// CalendarInvite is the compact form of an invite used by calendar views
+type CalendarInvite struct {
+	ID         string    `db:"id" json:"id"`
+	Title      string    `db:"title" json:"title"`
+	EventDate  time.Time `db:"eventDate" json:"eventDate"`
+	EndDate    time.Time `db:"endDate" json:"endDate"` // Effective end, with the default duration applied
+	Location   *string   `db:"location" json:"location,omitempty"`
+	SenderID   string    `db:"senderId" json:"senderId"`
+	CircleID   *string   `db:"circleId" json:"circleId,omitempty"`
+	IsDateTBD  bool      `db:"isDateTBD" json:"isDateTBD"`
+	RSVPStatus *string   `db:"rsvpStatus" json:"rsvpStatus,omitempty"` // The requesting user's answer
+}
+
+// CalendarDay holds the invites happening on one local date
+type CalendarDay struct {
+	Date    string           `json:"date"` // YYYY-MM-DD in the requested time zone
+	Invites []CalendarInvite `json:"invites"`
+}
+
+type CalendarResponse struct {
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	TimeZone string        `json:"timeZone"`
+	Days     []CalendarDay `json:"days"`
+}
+
+type GuestRSVPRequest struct {
+	Name       string  `json:"name"`
+	Email      string  `json:"email"`
+	Status     string  `json:"status"` // YES, NO, MAYBE
+	GuestCount int     `json:"guestCount"`
+	Dietary    *string `json:"dietary"`
+	Note       *string `json:"note"`
+	Website    string  `json:"website"` // Honeypot, must stay empty
+}
+
+type AddDateOptionsRequest struct {
+	DateOptions []time.Time `json:"dateOptions"`
+}
+
+type DatePollVoteRequest struct {
+	Votes []struct {
+		OptionID string `json:"optionId"`
+		Vote     string `json:"vote"` // YES, IF_NEEDED, NO
+	} `json:"votes"`
+}
+
+type FinalizeDatePollRequest struct {
+	OptionID string `json:"optionId"`
+}
+
+type SignupItemRequest struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+type CreateSignupSheetRequest struct {
+	Title       string              `json:"title"`
+	Description *string             `json:"description"`
+	Items       []SignupItemRequest `json:"items"`
+}
+
+type AddSignupItemsRequest struct {
+	Items []SignupItemRequest `json:"items"`
+}
+
+type ClaimSignupItemRequest struct {
+	Quantity int `json:"quantity"`
+}
+
+type ExpenseSplitRequest struct {
+	UserID      string `json:"userId"`
+	Shares      int    `json:"shares"`      // SHARES splits
+	AmountCents int64  `json:"amountCents"` // EXACT splits
+}
+
+type CreateExpenseRequest struct {
+	Description string                `json:"description"`
+	AmountCents int64                 `json:"amountCents"`
+	PaidByID    *string               `json:"paidById"` // Defaults to the current user
+	SplitType   string                `json:"splitType"`
+	Splits      []ExpenseSplitRequest `json:"splits"` // Empty EVEN split means all attendees
+}
+
+type CreateSettlementRequest struct {
+	FromUserID  *string `json:"fromUserId"` // Defaults to the current user
+	ToUserID    string  `json:"toUserId"`
+	AmountCents int64   `json:"amountCents"`
+}
+
+type RideOfferRequest struct {
+	Seats         int       `json:"seats"`
+	DepartureArea string    `json:"departureArea"`
+	DepartureTime time.Time `json:"departureTime"`
+	Notes         *string   `json:"notes"`
+}
+
+type RideRequestRequest struct {
+	PickupArea *string `json:"pickupArea"`
+	Notes      *string `json:"notes"`
+}
+
+type WalkInRequest struct {
+	Name       string  `json:"name"`
+	UserID     *string `json:"userId"` // Set for members who never RSVP'd
+	GuestCount int     `json:"guestCount"`
+}
+
+// CheckInRequest carries either a scanned ticket token or a walk-in
+type CheckInRequest struct {
+	Token  string         `json:"token"`
+	WalkIn *WalkInRequest `json:"walkIn"`
+}
+
+// CreateTemplateRequest saves a template from scratch or, with InviteID, from an existing invite
+type CreateTemplateRequest struct {
+	Name        string  `json:"name"`
+	InviteID    *string `json:"inviteId"`
+	CircleID    *string `json:"circleId"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	Location    *string `json:"location"`
+	MapLink     *string `json:"mapLink"`
+	Place               // Optional; coordinates are otherwise taken from mapLink or geocoded
+}
+
+type UseTemplateRequest struct {
+	Title     *string   `json:"title"` // Overrides the template title
+	EventDate time.Time `json:"eventDate"`
+}
+
+// DuplicateInviteRequest copies an invite to a new date. RSVPs, feed and media are never copied.
+type DuplicateInviteRequest struct {
+	EventDate           time.Time `json:"eventDate"`
+	IncludeSignupSheets bool      `json:"includeSignupSheets"`
+	IncludeInvitees     bool      `json:"includeInvitees"`
+}
+
 type CreatePostRequest struct {
-	InviteID string `json:"inviteId"`
-	Content  string `json:"content"`
-	Type     string `json:"type"` // UPDATE, CHAT
+	Content  string  `json:"content"`
+	Type     string  `json:"type"`     // UPDATE (host only) or CHAT, defaults to CHAT
+	ParentID *string `json:"parentId"` // Replies to a top-level post; replies are CHAT
+}
+
+type EditPostRequest struct {
+	Content string `json:"content"`
+}
+
+// Moderation actions
+const (
+	ModerationHide   = "HIDE"
+	ModerationUnhide = "UNHIDE"
+	ModerationRemove = "REMOVE"
+)
+
+type ModeratePostRequest struct {
+	Action string `json:"action"` // HIDE, UNHIDE or REMOVE
+	Reason string `json:"reason"` // Required to hide or remove
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+type CapsuleMessageRequest struct {
+	Content string `json:"content"`
+}
+
+// CapsuleView is the time capsule as seen by one attendee. While sealed,
+// Messages holds only the viewer's own messages.
+type CapsuleView struct {
+	Sealed       bool           `json:"sealed"`
+	MessageCount int            `json:"messageCount"`
+	Messages     []FeedWithUser `json:"messages"`
 }
 
 type SyncUserRequest struct {
-	ID            string     `json:"id"`
-	Name          *string    `json:"name"`
-	Email         string     `json:"email"`
-	Image         *string    `json:"image"`
-	EmailVerified *time.Time `json:"emailVerified"`
+	ID    string  `json:"id"`
+	Name  *string `json:"name"`
+	Email string  `json:"email"`
+	Image *string `json:"image"`
 }
 
 // User Profile Structs
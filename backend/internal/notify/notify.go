@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Notification kinds
+const (
+	KindEventReminder = "EVENT_REMINDER"
+	KindVaultUnlocked = "VAULT_UNLOCKED"
+	KindMention       = "MENTION"
+)
+
+// Notification is a single message addressed to one user.
+type Notification struct {
+	Kind     string  `json:"kind"`
+	UserID   string  `json:"userId"`
+	Name     *string `json:"name,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	InviteID string  `json:"inviteId,omitempty"`
+	Subject  string  `json:"subject"`
+	Body     string  `json:"body"`
+}
+
+// Notifier delivers notifications to users. Implementations decide the channel
+// (email, push, webhook...); callers only describe what should be said.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// LogNotifier writes notifications to the structured log. It is the default
+// notifier for local development.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, msg Notification) error {
+	slog.Info("notification",
+		"kind", msg.Kind,
+		"user_id", msg.UserID,
+		"invite_id", msg.InviteID,
+		"subject", msg.Subject,
+	)
+	return nil
+}
+
+// WebhookNotifier POSTs each notification as JSON to a configured URL, leaving
+// delivery (email, push) to an external service.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, msg Notification) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
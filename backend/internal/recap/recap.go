@@ -0,0 +1,264 @@
+// Package recap builds the shareable summary of a finished event.
+package recap
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/models"
+)
+
+const (
+	maxHighlights = 6
+	maxMedia      = 12
+)
+
+// Feed types worth repeating in a recap; chat is left out. Capsules were
+// written for the people who came, so Public drops them.
+var highlightTypes = map[string]bool{
+	"UPDATE":               true,
+	models.FeedTypeCapsule: true,
+}
+
+// Build summarizes the invite's attendance, feed, media, sign-ups and
+// expenses. details must be the unsealed view, as seen once the vault is open.
+// Attendees are the people checked in; when nobody was, YES RSVPs stand in.
+func Build(details *models.InviteDetails, checkIns []models.CheckIn, expenses []models.ExpenseWithShares, now time.Time) models.RecapManifest {
+	m := models.RecapManifest{
+		InviteID:    details.ID,
+		Title:       details.Title,
+		Description: details.Description,
+		Place:       place(details.Place),
+		EventDate:   details.EventDate,
+		EndDate:     details.Ends(),
+		Host:        models.RecapPerson{Name: name(details.Sender), Image: details.Sender.Image, GuestCount: 1},
+		Attendees:   []models.RecapPerson{},
+		Highlights:  []models.RecapPost{},
+		Media:       []models.RecapMedia{},
+		MediaCount:  len(details.MediaItems),
+		GeneratedAt: now,
+	}
+
+	if len(checkIns) > 0 {
+		m.Attendees = checkedIn(details, checkIns)
+	} else {
+		for _, r := range details.RSVPs {
+			if r.Status == "YES" {
+				m.Attendees = append(m.Attendees, models.RecapPerson{Name: name(r.User), Image: r.User.Image, GuestCount: partySize(r.GuestCount)})
+			}
+		}
+		for _, g := range details.GuestRSVPs {
+			if g.Status == "YES" {
+				m.Attendees = append(m.Attendees, models.RecapPerson{Name: g.Name, GuestCount: partySize(g.GuestCount)})
+			}
+		}
+	}
+	sort.SliceStable(m.Attendees, func(i, j int) bool {
+		return strings.ToLower(m.Attendees[i].Name) < strings.ToLower(m.Attendees[j].Name)
+	})
+	for _, a := range m.Attendees {
+		m.Headcount += a.GuestCount
+	}
+
+	posts := make([]models.FeedWithUser, 0, len(details.FeedItems))
+	for _, f := range details.FeedItems {
+		// Moderated posts are left out of the shareable page
+		if highlightTypes[f.Type] && f.HiddenAt == nil && f.RemovedAt == nil {
+			posts = append(posts, f)
+		}
+	}
+	// Keep the latest posts, shown oldest first
+	sort.SliceStable(posts, func(i, j int) bool { return posts[i].CreatedAt.Before(posts[j].CreatedAt) })
+	if len(posts) > maxHighlights {
+		posts = posts[len(posts)-maxHighlights:]
+	}
+	for _, f := range posts {
+		m.Highlights = append(m.Highlights, models.RecapPost{Author: name(f.User), Content: f.Content, Type: f.Type, CreatedAt: f.CreatedAt})
+	}
+
+	authors := map[string]string{details.SenderID: m.Host.Name}
+	for _, r := range details.RSVPs {
+		authors[r.UserID] = name(r.User)
+	}
+	for _, item := range details.MediaItems {
+		if item.Type != "IMAGE" || len(m.Media) == maxMedia {
+			continue
+		}
+		author, ok := authors[item.UserID]
+		if !ok {
+			author = "Guest"
+		}
+		m.Media = append(m.Media, models.RecapMedia{URL: item.URL, Type: item.Type, Caption: item.Caption, Author: author})
+	}
+
+	if len(details.SignupSheets) > 0 {
+		s := &models.RecapSignups{Sheets: len(details.SignupSheets)}
+		for _, sheet := range details.SignupSheets {
+			for _, item := range sheet.Items {
+				s.Items++
+				s.Claims += len(item.Claims)
+				if item.Claimed >= item.Quantity {
+					s.FilledItems++
+				}
+			}
+		}
+		m.Signups = s
+	}
+
+	if len(expenses) > 0 {
+		e := &models.RecapExpenses{Count: len(expenses)}
+		for _, expense := range expenses {
+			e.TotalCents += expense.AmountCents
+		}
+		m.Expenses = e
+	}
+
+	return m
+}
+
+// Public returns the manifest as shown through the public link, which leaves
+// out time-capsule messages.
+func Public(m models.RecapManifest) models.RecapManifest {
+	highlights := []models.RecapPost{}
+	for _, post := range m.Highlights {
+		if post.Type != models.FeedTypeCapsule {
+			highlights = append(highlights, post)
+		}
+	}
+	m.Highlights = highlights
+	return m
+}
+
+// checkedIn lists who was checked in, without the host, who is shown apart.
+// Walk-ins without an account only have the name given at the door.
+func checkedIn(details *models.InviteDetails, checkIns []models.CheckIn) []models.RecapPerson {
+	users := map[string]models.User{details.SenderID: details.Sender}
+	for _, r := range details.RSVPs {
+		users[r.UserID] = r.User
+	}
+	for _, inv := range details.Invitees {
+		if inv.User != nil {
+			users[inv.User.ID] = *inv.User
+		}
+	}
+	guests := map[string]string{}
+	for _, g := range details.GuestRSVPs {
+		guests[g.ID] = g.Name
+	}
+
+	people := []models.RecapPerson{}
+	for _, c := range checkIns {
+		person := models.RecapPerson{Name: "Guest", GuestCount: partySize(c.GuestCount)}
+		switch {
+		case c.UserID != nil:
+			if *c.UserID == details.SenderID {
+				continue
+			}
+			if u, ok := users[*c.UserID]; ok {
+				person.Name, person.Image = name(u), u.Image
+			} else if c.Name != nil && *c.Name != "" {
+				person.Name = *c.Name
+			}
+		case c.GuestRSVPID != nil && guests[*c.GuestRSVPID] != "":
+			person.Name = guests[*c.GuestRSVPID]
+		case c.Name != nil && *c.Name != "":
+			person.Name = *c.Name
+		}
+		people = append(people, person)
+	}
+	return people
+}
+
+// Render turns a manifest into a standalone HTML page.
+func Render(m models.RecapManifest) (string, error) {
+	var b strings.Builder
+	if err := page.Execute(&b, m); err != nil {
+		return "", fmt.Errorf("render recap: %w", err)
+	}
+	return b.String(), nil
+}
+
+// Recaps can be public, so people are never identified by email
+func name(u models.User) string {
+	if u.Name != nil && strings.TrimSpace(*u.Name) != "" {
+		return *u.Name
+	}
+	return "Guest"
+}
+
+// Only the place name or city; street addresses stay private
+func place(p models.Place) *string {
+	if p.PlaceName != nil && *p.PlaceName != "" {
+		return p.PlaceName
+	}
+	if p.City != nil && *p.City != "" {
+		return p.City
+	}
+	return nil
+}
+
+func partySize(guestCount int) int {
+	if guestCount < 1 {
+		return 1
+	}
+	return guestCount
+}
+
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+var page = template.Must(template.New("recap").Funcs(template.FuncMap{
+	"date":  func(t time.Time) string { return t.Format("Monday, January 2, 2006") },
+	"money": formatCents,
+	"extra": func(partySize int) int { return partySize - 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · Recap</title>
+<style>
+body{margin:0;font-family:system-ui,sans-serif;background:#0b0b12;color:#e8e8f0}
+main{max-width:760px;margin:0 auto;padding:40px 20px}
+h1{margin:0 0 4px;font-size:2rem}
+h2{margin:32px 0 12px;font-size:1rem;text-transform:uppercase;letter-spacing:.1em;color:#a9a3ff}
+.muted{color:#9a9aae}
+.people{display:flex;flex-wrap:wrap;gap:8px;padding:0;list-style:none}
+.people li{background:#1a1a26;border-radius:999px;padding:6px 12px}
+.post{background:#1a1a26;border-radius:16px;padding:12px 16px;margin-bottom:8px}
+.grid{display:grid;grid-template-columns:repeat(auto-fill,minmax(160px,1fr));gap:8px}
+.grid img{width:100%;aspect-ratio:1;object-fit:cover;border-radius:12px}
+.stats{display:flex;gap:24px}
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<p class="muted">{{date .EventDate}}{{with .Place}} · {{.}}{{end}} · Hosted by {{.Host.Name}}</p>
+{{with .Description}}<p>{{.}}</p>{{end}}
+
+<h2>Who came</h2>
+{{if .Attendees}}<p class="muted">{{.Headcount}} people</p>
+<ul class="people">{{range .Attendees}}<li>{{.Name}}{{with extra .GuestCount}} +{{.}}{{end}}</li>{{end}}</ul>
+{{else}}<p class="muted">No RSVPs were recorded.</p>{{end}}
+
+{{if .Highlights}}<h2>Highlights</h2>
+{{range .Highlights}}<div class="post"><p>{{.Content}}</p><p class="muted">{{.Author}}</p></div>{{end}}{{end}}
+
+{{if .Media}}<h2>Memories</h2>
+<div class="grid">{{range .Media}}<img src="{{.URL}}" alt="{{if .Caption}}{{.Caption}}{{else}}Photo by {{.Author}}{{end}}" loading="lazy">{{end}}</div>
+{{if gt .MediaCount (len .Media)}}<p class="muted">{{.MediaCount}} memories in the vault</p>{{end}}{{end}}
+
+{{if or .Signups .Expenses}}<h2>By the numbers</h2>
+<div class="stats">
+{{with .Signups}}<p>{{.FilledItems}} of {{.Items}} sign-up items covered</p>{{end}}
+{{with .Expenses}}<p>{{money .TotalCents}} shared across {{.Count}} expenses</p>{{end}}
+</div>{{end}}
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,196 @@
+package recap
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	str := func(s string) *string { return &s }
+	start := time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC)
+	user := func(id, name string) models.User {
+		return models.User{ID: id, Name: str(name), Email: str(id + "@example.com")}
+	}
+
+	details := &models.InviteDetails{
+		Invite: models.Invite{
+			ID:        "invite-1",
+			Title:     "Summer BBQ",
+			EventDate: start,
+			SenderID:  "user-host",
+			Place:     models.Place{AddressLine: str("5 Main St"), City: str("Portland")},
+		},
+		Sender: user("user-host", "Hana"),
+		RSVPs: []models.RSVPWithUser{
+			{RSVP: models.RSVP{UserID: "user-2", Status: "YES", GuestCount: 2}, User: user("user-2", "zoe")},
+			{RSVP: models.RSVP{UserID: "user-3", Status: "NO", GuestCount: 1}, User: user("user-3", "Bob")},
+			// No name set, must not fall back to the email
+			{RSVP: models.RSVP{UserID: "user-4", Status: "YES", GuestCount: 0}, User: models.User{ID: "user-4", Email: str("x@example.com")}},
+		},
+		GuestRSVPs: []models.GuestRSVP{
+			{Name: "Adam", Email: "adam@example.com", Status: "YES", GuestCount: 1},
+			{Name: "Mia", Email: "mia@example.com", Status: "MAYBE", GuestCount: 3},
+		},
+		FeedItems: []models.FeedWithUser{
+			{EventFeedItem: models.EventFeedItem{Content: "Bring sunscreen", Type: "UPDATE", CreatedAt: start.Add(-time.Hour)}, User: user("user-host", "Hana")},
+			{EventFeedItem: models.EventFeedItem{Content: "lol", Type: "CHAT", CreatedAt: start}, User: user("user-2", "zoe")},
+			{EventFeedItem: models.EventFeedItem{Content: "Same time next year", Type: "CAPSULE", CreatedAt: start.Add(-2 * time.Hour)}, User: user("user-2", "zoe")},
+			// Moderated
+			{EventFeedItem: models.EventFeedItem{Content: "Hidden", Type: "UPDATE", CreatedAt: start, HiddenAt: &start}, User: user("user-host", "Hana")},
+			{EventFeedItem: models.EventFeedItem{Type: "UPDATE", CreatedAt: start, RemovedAt: &start}, User: user("user-host", "Hana")},
+		},
+		MediaItems: []models.MediaItem{
+			{UserID: "user-2", URL: "/uploads/invite-1/a.jpg", Type: "IMAGE"},
+			{UserID: "user-9", URL: "/uploads/invite-1/b.mp4", Type: "VIDEO"},
+			{UserID: "user-host", URL: "/uploads/invite-1/c.jpg", Type: "IMAGE", Caption: str("Sunset")},
+		},
+		SignupSheets: []models.SignupSheetWithItems{{
+			Items: []models.SignupItemWithClaims{
+				{SignupItem: models.SignupItem{Quantity: 2}, Claimed: 2, Claims: make([]models.SignupClaimWithUser, 2)},
+				{SignupItem: models.SignupItem{Quantity: 3}, Claimed: 1, Claims: make([]models.SignupClaimWithUser, 1)},
+			},
+		}},
+	}
+	expenses := []models.ExpenseWithShares{
+		{Expense: models.Expense{AmountCents: 4599}},
+		{Expense: models.Expense{AmountCents: 1001}},
+	}
+	now := start.Add(48 * time.Hour)
+
+	m := Build(details, nil, expenses, now)
+
+	assert.Equal(t, "Portland", *m.Place)
+	assert.Equal(t, start.Add(models.DefaultEventDuration), m.EndDate)
+	assert.Equal(t, "Hana", m.Host.Name)
+	assert.Equal(t, []models.RecapPerson{
+		{Name: "Adam", GuestCount: 1},
+		{Name: "Guest", GuestCount: 1},
+		{Name: "zoe", GuestCount: 2},
+	}, m.Attendees)
+	assert.Equal(t, 4, m.Headcount)
+
+	assert.Len(t, m.Highlights, 2)
+	assert.Equal(t, "Same time next year", m.Highlights[0].Content)
+	assert.Equal(t, "Bring sunscreen", m.Highlights[1].Content)
+
+	assert.Equal(t, 3, m.MediaCount)
+	assert.Equal(t, []models.RecapMedia{
+		{URL: "/uploads/invite-1/a.jpg", Type: "IMAGE", Author: "zoe"},
+		{URL: "/uploads/invite-1/c.jpg", Type: "IMAGE", Caption: str("Sunset"), Author: "Hana"},
+	}, m.Media)
+
+	assert.Equal(t, &models.RecapSignups{Sheets: 1, Items: 2, FilledItems: 1, Claims: 3}, m.Signups)
+	assert.Equal(t, &models.RecapExpenses{Count: 2, TotalCents: 5600}, m.Expenses)
+	assert.Equal(t, now, m.GeneratedAt)
+}
+
+func TestBuildFromCheckIns(t *testing.T) {
+	str := func(s string) *string { return &s }
+	details := &models.InviteDetails{
+		Invite: models.Invite{ID: "invite-1", Title: "Summer BBQ", SenderID: "user-host"},
+		Sender: models.User{ID: "user-host", Name: str("Hana")},
+		RSVPs: []models.RSVPWithUser{
+			{RSVP: models.RSVP{UserID: "user-2", Status: "YES", GuestCount: 2}, User: models.User{ID: "user-2", Name: str("Zoe")}},
+			// Said yes but never showed up
+			{RSVP: models.RSVP{UserID: "user-3", Status: "YES", GuestCount: 1}, User: models.User{ID: "user-3", Name: str("Bob")}},
+		},
+		Invitees: []models.InviteeWithUser{
+			{User: &models.User{ID: "user-4", Name: str("Ivy"), Image: str("/ivy.png")}},
+		},
+		GuestRSVPs: []models.GuestRSVP{{ID: "guest-1", Name: "Adam", Status: "YES", GuestCount: 1}},
+	}
+	checkIns := []models.CheckIn{
+		{UserID: str("user-host"), GuestCount: 1},
+		{UserID: str("user-2"), GuestCount: 3},
+		// Changed their mind without updating the RSVP
+		{UserID: str("user-4"), GuestCount: 1},
+		{GuestRSVPID: str("guest-1"), GuestCount: 1},
+		{Name: str("Walk-in Walt"), GuestCount: 1},
+		{GuestCount: 1},
+	}
+
+	m := Build(details, checkIns, nil, time.Now())
+
+	assert.Equal(t, []models.RecapPerson{
+		{Name: "Adam", GuestCount: 1},
+		{Name: "Guest", GuestCount: 1},
+		{Name: "Ivy", Image: str("/ivy.png"), GuestCount: 1},
+		{Name: "Walk-in Walt", GuestCount: 1},
+		{Name: "Zoe", GuestCount: 3},
+	}, m.Attendees)
+	assert.Equal(t, 7, m.Headcount)
+}
+
+func TestPublic(t *testing.T) {
+	m := models.RecapManifest{Highlights: []models.RecapPost{
+		{Type: models.FeedTypeCapsule, Content: "Same time next year"},
+		{Type: "UPDATE", Content: "Bring sunscreen"},
+	}}
+
+	public := Public(m)
+
+	assert.Equal(t, []models.RecapPost{{Type: "UPDATE", Content: "Bring sunscreen"}}, public.Highlights)
+	assert.Len(t, m.Highlights, 2)
+}
+
+func TestBuildWithoutExtras(t *testing.T) {
+	details := &models.InviteDetails{Invite: models.Invite{ID: "invite-1", Title: "Quiet night"}}
+
+	m := Build(details, nil, nil, time.Now())
+
+	assert.Nil(t, m.Signups)
+	assert.Nil(t, m.Expenses)
+	assert.Nil(t, m.Place)
+	assert.Empty(t, m.Attendees)
+	assert.Empty(t, m.Highlights)
+	assert.Empty(t, m.Media)
+}
+
+func TestBuildKeepsLatestHighlights(t *testing.T) {
+	start := time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC)
+	details := &models.InviteDetails{}
+	for i := 0; i < maxHighlights+2; i++ {
+		details.FeedItems = append(details.FeedItems, models.FeedWithUser{
+			EventFeedItem: models.EventFeedItem{Content: fmt.Sprintf("Update %d", i), Type: "UPDATE", CreatedAt: start.Add(time.Duration(i) * time.Minute)},
+		})
+	}
+
+	m := Build(details, nil, nil, time.Now())
+
+	assert.Len(t, m.Highlights, maxHighlights)
+	assert.Equal(t, "Update 2", m.Highlights[0].Content)
+	assert.Equal(t, fmt.Sprintf("Update %d", maxHighlights+1), m.Highlights[maxHighlights-1].Content)
+}
+
+func TestRender(t *testing.T) {
+	str := func(s string) *string { return &s }
+	m := models.RecapManifest{
+		Title:      "<b>Dinner</b>",
+		Place:      str("Portland"),
+		EventDate:  time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC),
+		Host:       models.RecapPerson{Name: "Hana"},
+		Attendees:  []models.RecapPerson{{Name: "Zoe", GuestCount: 3}},
+		Headcount:  3,
+		Highlights: []models.RecapPost{{Author: "Hana", Content: "<script>alert(1)</script>"}},
+		Media:      []models.RecapMedia{{URL: "/uploads/invite-1/a.jpg", Type: "IMAGE", Author: "Zoe"}},
+		MediaCount: 5,
+		Expenses:   &models.RecapExpenses{Count: 2, TotalCents: 5600},
+	}
+
+	html, err := Render(m)
+
+	assert.NoError(t, err)
+	assert.Contains(t, html, "&lt;b&gt;Dinner&lt;/b&gt;")
+	assert.NotContains(t, html, "<script>alert(1)</script>")
+	assert.Contains(t, html, "Monday, June 1, 2026 · Portland · Hosted by Hana")
+	assert.Contains(t, html, "Zoe +2")
+	assert.Contains(t, html, `src="/uploads/invite-1/a.jpg"`)
+	assert.Contains(t, html, "5 memories in the vault")
+	assert.Contains(t, html, "56.00 shared across 2 expenses")
+	assert.NotContains(t, html, "sign-up items")
+}
@@ -0,0 +1,147 @@
+// Package report builds the host's headcount and dietary report.
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"privo-club-backend/internal/models"
+)
+
+// Dietary categories, in report order
+const (
+	Vegan       = "Vegan"
+	Vegetarian  = "Vegetarian"
+	Pescatarian = "Pescatarian"
+	GlutenFree  = "Gluten-free"
+	DairyFree   = "Dairy-free"
+	NutAllergy  = "Nut allergy"
+	Shellfish   = "Shellfish allergy"
+	EggAllergy  = "Egg allergy"
+	Halal       = "Halal"
+	Kosher      = "Kosher"
+	NoPork      = "No pork"
+	Other       = "Other"
+)
+
+var categories = []struct {
+	name     string
+	keywords []string
+}{
+	{Vegan, []string{"vegan", "plant based"}},
+	{Vegetarian, []string{"vegetarian", "veggie", "veg", "no meat"}},
+	{Pescatarian, []string{"pescatarian", "pescetarian"}},
+	{GlutenFree, []string{"gluten", "celiac", "coeliac"}},
+	{DairyFree, []string{"dairy", "lactose", "milk"}},
+	{NutAllergy, []string{"nut", "nuts", "peanut", "peanuts", "tree nut", "tree nuts", "almond", "almonds", "cashew", "cashews"}},
+	{Shellfish, []string{"shellfish", "shrimp", "prawn", "prawns", "crab", "lobster", "seafood"}},
+	{EggAllergy, []string{"egg", "eggs"}},
+	{Halal, []string{"halal"}},
+	{Kosher, []string{"kosher"}},
+	{NoPork, []string{"pork"}},
+}
+
+// Answers that mean "no requirements"
+var noneAnswers = map[string]bool{
+	"": true, "none": true, "no": true, "n a": true, "na": true, "nope": true, "nothing": true, "nil": true, "no restrictions": true,
+}
+
+// Categorize maps a free-text dietary answer to normalized categories.
+// Text that matches no known category is reported as Other.
+func Categorize(dietary string) []string {
+	normalized := normalize(dietary)
+	if noneAnswers[normalized] {
+		return []string{}
+	}
+
+	padded := " " + normalized + " "
+	found := []string{}
+	for _, c := range categories {
+		for _, kw := range c.keywords {
+			if strings.Contains(padded, " "+kw+" ") {
+				found = append(found, c.name)
+				break
+			}
+		}
+	}
+	if len(found) == 0 {
+		found = append(found, Other)
+	}
+	return found
+}
+
+// normalize lower-cases the text and turns punctuation into single spaces
+func normalize(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// Build totals the responses and groups dietary needs of YES and MAYBE
+// guests. Guests are sorted by status, then name.
+func Build(invite *models.Invite, guests []models.ReportGuest, now time.Time) models.HeadcountReport {
+	rep := models.HeadcountReport{
+		InviteID:    invite.ID,
+		Title:       invite.Title,
+		EventDate:   invite.EventDate,
+		Totals:      map[string]models.StatusTotal{"YES": {}, "MAYBE": {}, "NO": {}},
+		Dietary:     []models.DietaryCount{},
+		Guests:      guests,
+		GeneratedAt: now,
+	}
+	if rep.Guests == nil {
+		rep.Guests = []models.ReportGuest{}
+	}
+
+	statusOrder := map[string]int{"YES": 0, "MAYBE": 1, "NO": 2}
+	sort.SliceStable(rep.Guests, func(i, j int) bool {
+		a, b := rep.Guests[i], rep.Guests[j]
+		if statusOrder[a.Status] != statusOrder[b.Status] {
+			return statusOrder[a.Status] < statusOrder[b.Status]
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	byCategory := map[string]*models.DietaryCount{}
+	for i := range rep.Guests {
+		g := &rep.Guests[i]
+		people := g.GuestCount
+		if people < 1 {
+			people = 1
+		}
+		total := rep.Totals[g.Status]
+		total.Responses++
+		total.People += people
+		rep.Totals[g.Status] = total
+
+		g.DietaryCategories = []string{}
+		if g.Dietary != nil {
+			g.DietaryCategories = Categorize(*g.Dietary)
+		}
+		if g.Status == "NO" {
+			continue
+		}
+		for _, c := range g.DietaryCategories {
+			dc, ok := byCategory[c]
+			if !ok {
+				dc = &models.DietaryCount{Category: c, Names: []string{}}
+				byCategory[c] = dc
+			}
+			dc.Count++
+			dc.Names = append(dc.Names, g.Name)
+		}
+	}
+
+	for _, c := range categories {
+		if dc, ok := byCategory[c.name]; ok {
+			rep.Dietary = append(rep.Dietary, *dc)
+		}
+	}
+	if dc, ok := byCategory[Other]; ok {
+		rep.Dietary = append(rep.Dietary, *dc)
+	}
+	return rep
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"Vegan", []string{Vegan}},
+		{"vegetarian, no nuts please", []string{Vegetarian, NutAllergy}},
+		{"Gluten-free / lactose intolerant", []string{GlutenFree, DairyFree}},
+		{"Celiac", []string{GlutenFree}},
+		{"Peanut allergy!!", []string{NutAllergy}},
+		{"HALAL", []string{Halal}},
+		{"allergic to shrimp", []string{Shellfish}},
+		{"low FODMAP", []string{Other}},
+		{"N/A", []string{}},
+		{"none", []string{}},
+		{"  ", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, Categorize(tt.input))
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	str := func(s string) *string { return &s }
+	invite := &models.Invite{ID: "invite-1", Title: "Dinner"}
+	guests := []models.ReportGuest{
+		{Kind: "MEMBER", Name: "Zoe", Status: "YES", GuestCount: 2, Dietary: str("vegan")},
+		{Kind: "GUEST", Name: "Adam", Status: "YES", GuestCount: 1, Dietary: str("Vegan, gluten free")},
+		{Kind: "MEMBER", Name: "Mia", Status: "MAYBE", GuestCount: 3, Dietary: str("keto")},
+		{Kind: "GUEST", Name: "Bob", Status: "NO", GuestCount: 1, Dietary: str("vegetarian")},
+	}
+
+	rep := Build(invite, guests, time.Now())
+
+	assert.Equal(t, models.StatusTotal{Responses: 2, People: 3}, rep.Totals["YES"])
+	assert.Equal(t, models.StatusTotal{Responses: 1, People: 3}, rep.Totals["MAYBE"])
+	assert.Equal(t, models.StatusTotal{Responses: 1, People: 1}, rep.Totals["NO"])
+
+	// Declined guests don't count towards catering needs
+	assert.Equal(t, []models.DietaryCount{
+		{Category: Vegan, Count: 2, Names: []string{"Adam", "Zoe"}},
+		{Category: GlutenFree, Count: 1, Names: []string{"Adam"}},
+		{Category: Other, Count: 1, Names: []string{"Mia"}},
+	}, rep.Dietary)
+
+	var order []string
+	for _, g := range rep.Guests {
+		order = append(order, g.Name)
+	}
+	assert.Equal(t, []string{"Adam", "Zoe", "Mia", "Bob"}, order)
+}
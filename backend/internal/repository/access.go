@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type accessRepository struct {
+	db *sqlx.DB
+}
+
+func NewAccessRepository(db *sqlx.DB) AccessRepository {
+	return &accessRepository{db: db}
+}
+
+func (r *accessRepository) GetInviteAccess(ctx context.Context, inviteID, userID string) (*models.InviteAccess, error) {
+	var row models.InviteAccess
+	if err := r.db.GetContext(ctx, &row, QueryGetInviteAccess, inviteID, userID); err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
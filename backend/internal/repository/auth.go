@@ -17,6 +17,26 @@ func NewAuthRepository(db *sqlx.DB) AuthRepository {
 }
 
 func (r *authRepository) SyncUser(ctx context.Context, user *models.User) error {
-	_, err := r.db.ExecContext(ctx, QuerySyncUser, user.ID, user.Name, user.Email, user.Image, user.EmailVerified)
-	return err
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QuerySyncUser, user.ID, user.Name, user.Email, user.Image, user.EmailVerified)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Link any direct invites that were sent to this email before the account
+	// existed, once the address is known to belong to this user
+	if user.Email != nil && user.EmailVerified != nil {
+		_, err = tx.ExecContext(ctx, QueryResolveInviteeEmails, user.ID, *user.Email)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
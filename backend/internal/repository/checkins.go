@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type checkInRepository struct {
+	db *sqlx.DB
+}
+
+func NewCheckInRepository(db *sqlx.DB) CheckInRepository {
+	return &checkInRepository{db: db}
+}
+
+func (r *checkInRepository) GetUserRSVP(ctx context.Context, inviteID, userID string) (*models.RSVP, error) {
+	var rsvp models.RSVP
+	if err := r.db.GetContext(ctx, &rsvp, QueryGetUserRSVP, inviteID, userID); err != nil {
+		return nil, err
+	}
+	return &rsvp, nil
+}
+
+// GetEventEnd returns when the invite's event ends, or its start date when no
+// end date is set.
+func (r *checkInRepository) GetEventEnd(ctx context.Context, inviteID string) (time.Time, error) {
+	var end time.Time
+	err := r.db.GetContext(ctx, &end, QueryGetInviteEventEnd, inviteID)
+	return end, err
+}
+
+// GetTicketHolder resolves a ticket to the member RSVP or, with guest set,
+// the guest RSVP it was issued for.
+func (r *checkInRepository) GetTicketHolder(ctx context.Context, inviteID, rsvpID string, guest bool) (*models.TicketHolder, error) {
+	query := QueryGetRSVPTicketHolder
+	if guest {
+		query = QueryGetGuestTicketHolder
+	}
+
+	var holder models.TicketHolder
+	if err := r.db.GetContext(ctx, &holder, query, rsvpID, inviteID); err != nil {
+		return nil, err
+	}
+	return &holder, nil
+}
+
+// CreateCheckIn records attendance and returns false if the member or guest
+// was already checked in, in which case checkIn is replaced by the stored one.
+func (r *checkInRepository) CreateCheckIn(ctx context.Context, checkIn *models.CheckIn) (bool, error) {
+	res, err := r.db.ExecContext(ctx, QueryCreateCheckIn, checkIn.ID, checkIn.InviteID, checkIn.UserID, checkIn.GuestRSVPID, checkIn.Name, checkIn.GuestCount, checkIn.CheckedInByID, checkIn.CheckedInAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 1 {
+		return true, nil
+	}
+	return false, r.db.GetContext(ctx, checkIn, QueryGetExistingCheckIn, checkIn.InviteID, checkIn.UserID, checkIn.GuestRSVPID)
+}
+
+func (r *checkInRepository) ListCheckIns(ctx context.Context, inviteID string) ([]models.CheckIn, error) {
+	var checkIns []models.CheckIn
+	err := r.db.SelectContext(ctx, &checkIns, QueryListCheckIns, inviteID)
+	if checkIns == nil {
+		checkIns = []models.CheckIn{}
+	}
+	return checkIns, err
+}
+
+func (r *checkInRepository) GetAttendanceCounts(ctx context.Context, inviteID string) (*models.AttendanceCounts, error) {
+	var counts models.AttendanceCounts
+	if err := r.db.GetContext(ctx, &counts, QueryGetAttendanceCounts, inviteID); err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type expenseRepository struct {
+	db *sqlx.DB
+}
+
+func NewExpenseRepository(db *sqlx.DB) ExpenseRepository {
+	return &expenseRepository{db: db}
+}
+
+func (r *expenseRepository) ListAttendees(ctx context.Context, inviteID string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.SelectContext(ctx, &users, QueryListAttendees, inviteID)
+	if users == nil {
+		users = []models.User{}
+	}
+	return users, err
+}
+
+func (r *expenseRepository) CreateExpense(ctx context.Context, expense *models.Expense, shares []models.ExpenseShare) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryCreateExpense, expense.ID, expense.InviteID, expense.PaidByID, expense.Description, expense.AmountCents, expense.SplitType, expense.CreatedByID, expense.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, share := range shares {
+		_, err = tx.ExecContext(ctx, QueryCreateExpenseShare, share.ID, share.ExpenseID, share.UserID, share.Shares, share.AmountCents)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *expenseRepository) GetExpense(ctx context.Context, inviteID, expenseID string) (*models.Expense, error) {
+	var expense models.Expense
+	if err := r.db.GetContext(ctx, &expense, QueryGetExpense, expenseID, inviteID); err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+func (r *expenseRepository) DeleteExpense(ctx context.Context, inviteID, expenseID string) error {
+	_, err := r.db.ExecContext(ctx, QueryDeleteExpense, expenseID, inviteID)
+	return err
+}
+
+func (r *expenseRepository) ListExpenses(ctx context.Context, inviteID string) ([]models.ExpenseWithShares, error) {
+	var expenses []models.Expense
+	if err := r.db.SelectContext(ctx, &expenses, QueryListExpenses, inviteID); err != nil {
+		return nil, err
+	}
+
+	var shares []models.ExpenseShare
+	if err := r.db.SelectContext(ctx, &shares, QueryListExpenseShares, inviteID); err != nil {
+		return nil, err
+	}
+
+	sharesByExpense := make(map[string][]models.ExpenseShare)
+	for _, share := range shares {
+		sharesByExpense[share.ExpenseID] = append(sharesByExpense[share.ExpenseID], share)
+	}
+
+	result := make([]models.ExpenseWithShares, len(expenses))
+	for i, expense := range expenses {
+		result[i] = models.ExpenseWithShares{Expense: expense, Shares: sharesByExpense[expense.ID]}
+		if result[i].Shares == nil {
+			result[i].Shares = []models.ExpenseShare{}
+		}
+	}
+	return result, nil
+}
+
+func (r *expenseRepository) CreateSettlement(ctx context.Context, settlement *models.Settlement) error {
+	_, err := r.db.ExecContext(ctx, QueryCreateSettlement, settlement.ID, settlement.InviteID, settlement.FromUserID, settlement.ToUserID, settlement.AmountCents, settlement.CreatedByID, settlement.CreatedAt)
+	return err
+}
+
+func (r *expenseRepository) GetSettlement(ctx context.Context, inviteID, settlementID string) (*models.Settlement, error) {
+	var settlement models.Settlement
+	if err := r.db.GetContext(ctx, &settlement, QueryGetSettlement, settlementID, inviteID); err != nil {
+		return nil, err
+	}
+	return &settlement, nil
+}
+
+func (r *expenseRepository) DeleteSettlement(ctx context.Context, inviteID, settlementID string) error {
+	_, err := r.db.ExecContext(ctx, QueryDeleteSettlement, settlementID, inviteID)
+	return err
+}
+
+func (r *expenseRepository) ListSettlements(ctx context.Context, inviteID string) ([]models.Settlement, error) {
+	var settlements []models.Settlement
+	err := r.db.SelectContext(ctx, &settlements, QueryListSettlements, inviteID)
+	if settlements == nil {
+		settlements = []models.Settlement{}
+	}
+	return settlements, err
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/utils"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type feedRepository struct {
@@ -16,17 +20,393 @@ func NewFeedRepository(db *sqlx.DB) FeedRepository {
 	return &feedRepository{db: db}
 }
 
-func (r *feedRepository) CreatePost(ctx context.Context, item *models.EventFeedItem) error {
-	_, err := r.db.ExecContext(ctx, QueryCreatePost, item.ID, item.InviteID, item.UserID, item.Content, item.Type, item.CreatedAt)
+// CreatePost stores a post together with the mentions found in it
+func (r *feedRepository) CreatePost(ctx context.Context, item *models.EventFeedItem, mentions []models.FeedMention) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryCreatePost, item.ID, item.InviteID, item.UserID, item.Content, item.Type, item.CreatedAt, item.ParentID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, m := range mentions {
+		_, err = tx.ExecContext(ctx, QueryCreateMention, m.ID, m.FeedItemID, m.UserID, m.Start, m.Length, m.CreatedAt, m.NotifiedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// EditPost replaces the content of an author's post, keeping the previous
+// text as a revision, and replaces its mentions. People who were already
+// told about a mention are not told again. It returns sql.ErrNoRows if the
+// post is not the author's or was removed.
+func (r *feedRepository) EditPost(ctx context.Context, item *models.EventFeedItem, mentions []models.FeedMention) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Copied before the update below replaces it
+	res, err := tx.ExecContext(ctx, QueryCreatePostRevision, utils.GenerateID("revision"), item.ID, item.EditedAt, item.UserID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rows == 0 {
+		tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.ExecContext(ctx, QueryEditPost, item.ID, item.UserID, item.Content, item.EditedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var previous []models.FeedMention
+	if err := tx.SelectContext(ctx, &previous, QueryDeleteMentions, item.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	notified := make(map[string]*time.Time)
+	for _, m := range previous {
+		if m.NotifiedAt != nil {
+			notified[m.UserID] = m.NotifiedAt
+		}
+	}
+	for _, m := range mentions {
+		if m.NotifiedAt == nil {
+			m.NotifiedAt = notified[m.UserID]
+		}
+		_, err = tx.ExecContext(ctx, QueryCreateMention, m.ID, m.FeedItemID, m.UserID, m.Start, m.Length, m.CreatedAt, m.NotifiedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListRevisions returns the earlier texts of a post, oldest first
+func (r *feedRepository) ListRevisions(ctx context.Context, postID string) ([]models.FeedPostRevision, error) {
+	var revisions []models.FeedPostRevision
+	err := r.db.SelectContext(ctx, &revisions, QueryListPostRevisions, postID)
+	if revisions == nil {
+		revisions = []models.FeedPostRevision{}
+	}
+	return revisions, err
+}
+
+// HidePost returns sql.ErrNoRows if the post is already hidden or removed
+func (r *feedRepository) HidePost(ctx context.Context, postID, moderatorID, reason string, now time.Time) error {
+	res, err := r.db.ExecContext(ctx, QueryHidePost, postID, now, moderatorID, reason)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UnhidePost returns sql.ErrNoRows if the post is not hidden
+func (r *feedRepository) UnhidePost(ctx context.Context, postID string) error {
+	res, err := r.db.ExecContext(ctx, QueryUnhidePost, postID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RemovePost turns a post into a tombstone: its text, revisions, reactions
+// and mentions are deleted, while replies stay attached. It returns
+// sql.ErrNoRows if the post was already removed.
+func (r *feedRepository) RemovePost(ctx context.Context, postID, removedByID string, reason *string, now time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, QueryRemovePost, postID, now, removedByID, reason)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rows == 0 {
+		tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	for _, query := range []string{QueryDeletePostRevisions, QueryDeletePostReactions, QueryDeleteMentions} {
+		if _, err := tx.ExecContext(ctx, query, postID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *feedRepository) ListMentionCandidates(ctx context.Context, inviteID string) ([]models.MentionCandidate, error) {
+	var candidates []models.MentionCandidate
+	err := r.db.SelectContext(ctx, &candidates, QueryListMentionCandidates, inviteID)
+	return candidates, err
+}
+
+// ListMentionsOfUser returns up to filter.Limit posts mentioning userID,
+// newest first, leaving out invites the user can no longer see
+func (r *feedRepository) ListMentionsOfUser(ctx context.Context, userID string, filter models.FeedListFilter) ([]models.MentionedPost, error) {
+	var after *time.Time
+	var afterID string
+	if filter.After != nil {
+		after, afterID = &filter.After.PostedAt, filter.After.ID
+	}
+
+	var posts []models.MentionedPost
+	if err := r.db.SelectContext(ctx, &posts, QueryListMentionsOfUser, userID, after, afterID, filter.Limit); err != nil {
+		return nil, err
+	}
+	if posts == nil {
+		posts = []models.MentionedPost{}
+	}
+
+	items := make([]*models.FeedWithUser, len(posts))
+	for i := range posts {
+		items[i] = &posts[i].FeedWithUser
+	}
+	if err := attachReactions(ctx, r.db, userID, items); err != nil {
+		return nil, err
+	}
+	return posts, attachMentions(ctx, r.db, items)
+}
+
+// ClaimMentionNotifications marks every pending mention as notified and
+// returns who to tell about which post
+func (r *feedRepository) ClaimMentionNotifications(ctx context.Context, now time.Time) ([]models.MentionRecipient, error) {
+	var recipients []models.MentionRecipient
+	err := r.db.SelectContext(ctx, &recipients, QueryClaimMentionNotifications, now)
+	return recipients, err
+}
+
+// ReleaseMentionNotification un-claims a mention so it is retried on the next run.
+func (r *feedRepository) ReleaseMentionNotification(ctx context.Context, feedItemID, userID string) error {
+	_, err := r.db.ExecContext(ctx, QueryReleaseMentionNotification, feedItemID, userID)
 	return err
 }
 
-func (r *feedRepository) GetFeed(ctx context.Context, inviteID string) ([]models.EventFeedItem, error) {
-	var items []models.EventFeedItem
-	err := r.db.SelectContext(ctx, &items, QueryGetFeed, inviteID)
+// GetPost returns a post of the invite, unless it is a still-sealed capsule message
+func (r *feedRepository) GetPost(ctx context.Context, inviteID, postID string) (*models.EventFeedItem, error) {
+	var item models.EventFeedItem
+	if err := r.db.GetContext(ctx, &item, QueryGetFeedPost, postID, inviteID); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListFeed returns up to filter.Limit top-level posts, newest first, with
+// their reply counts and reactions as seen by viewerID.
+func (r *feedRepository) ListFeed(ctx context.Context, inviteID, viewerID string, filter models.FeedListFilter) ([]models.FeedPost, error) {
+	var after *time.Time
+	var afterID string
+	if filter.After != nil {
+		after, afterID = &filter.After.PostedAt, filter.After.ID
+	}
+
+	var posts []models.FeedPost
+	if err := r.db.SelectContext(ctx, &posts, QueryListFeed, inviteID, filter.Type, after, afterID, filter.Limit); err != nil {
+		return nil, err
+	}
+	if posts == nil {
+		posts = []models.FeedPost{}
+	}
+
+	items := make([]*models.FeedWithUser, len(posts))
+	for i := range posts {
+		items[i] = &posts[i].FeedWithUser
+	}
+	if err := attachReactions(ctx, r.db, viewerID, items); err != nil {
+		return nil, err
+	}
+	return posts, attachMentions(ctx, r.db, items)
+}
+
+func (r *feedRepository) ListReplies(ctx context.Context, postID, viewerID string) ([]models.FeedWithUser, error) {
+	var replies []models.FeedWithUser
+	if err := r.db.SelectContext(ctx, &replies, QueryListReplies, postID); err != nil {
+		return nil, err
+	}
+	if replies == nil {
+		replies = []models.FeedWithUser{}
+	}
+
+	items := make([]*models.FeedWithUser, len(replies))
+	for i := range replies {
+		items[i] = &replies[i]
+	}
+	if err := attachReactions(ctx, r.db, viewerID, items); err != nil {
+		return nil, err
+	}
+	return replies, attachMentions(ctx, r.db, items)
+}
+
+// AddReaction is idempotent; reacting twice with the same emoji counts once
+func (r *feedRepository) AddReaction(ctx context.Context, reaction *models.FeedReaction) error {
+	_, err := r.db.ExecContext(ctx, QueryAddReaction, reaction.ID, reaction.FeedItemID, reaction.UserID, reaction.Emoji, reaction.CreatedAt)
+	return err
+}
+
+// RemoveReaction returns sql.ErrNoRows if the user had not reacted with emoji
+func (r *feedRepository) RemoveReaction(ctx context.Context, postID, userID, emoji string) error {
+	res, err := r.db.ExecContext(ctx, QueryRemoveReaction, postID, userID, emoji)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *feedRepository) ListReactions(ctx context.Context, postID, viewerID string) ([]models.ReactionCount, error) {
+	byPost, err := listReactions(ctx, r.db, viewerID, []string{postID})
+	if err != nil {
+		return nil, err
+	}
+	if byPost[postID] == nil {
+		return []models.ReactionCount{}, nil
+	}
+	return byPost[postID], nil
+}
+
+// listReactions loads the reactions of many posts in one query
+func listReactions(ctx context.Context, db *sqlx.DB, viewerID string, postIDs []string) (map[string][]models.ReactionCount, error) {
+	byPost := make(map[string][]models.ReactionCount)
+	if len(postIDs) == 0 {
+		return byPost, nil
+	}
+
+	var counts []models.ReactionCount
+	if err := db.SelectContext(ctx, &counts, QueryListReactions, pq.Array(postIDs), viewerID); err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		byPost[c.FeedItemID] = append(byPost[c.FeedItemID], c)
+	}
+	return byPost, nil
+}
+
+func attachReactions(ctx context.Context, db *sqlx.DB, viewerID string, items []*models.FeedWithUser) error {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	byPost, err := listReactions(ctx, db, viewerID, ids)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		item.Reactions = byPost[item.ID]
+		if item.Reactions == nil {
+			item.Reactions = []models.ReactionCount{}
+		}
+	}
+	return nil
+}
+
+// attachMentions loads the mention spans of many posts in one query
+func attachMentions(ctx context.Context, db *sqlx.DB, items []*models.FeedWithUser) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
 
-	if items == nil {
-		items = []models.EventFeedItem{}
+	var spans []models.MentionSpan
+	if err := db.SelectContext(ctx, &spans, QueryListMentions, pq.Array(ids)); err != nil {
+		return err
 	}
-	return items, err
+	byPost := make(map[string][]models.MentionSpan)
+	for _, span := range spans {
+		byPost[span.FeedItemID] = append(byPost[span.FeedItemID], span)
+	}
+	for _, item := range items {
+		item.Mentions = byPost[item.ID]
+	}
+	return nil
+}
+
+// CreateCapsuleMessage seals a message in the invite's time capsule. It
+// returns sql.ErrNoRows when the vault is already unlocked.
+func (r *feedRepository) CreateCapsuleMessage(ctx context.Context, item *models.EventFeedItem) error {
+	res, err := r.db.ExecContext(ctx, QueryCreateCapsuleMessage, item.ID, item.InviteID, item.UserID, item.Content, item.CreatedAt)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListCapsuleMessages returns the capsule messages of one author, or of
+// everyone when authorID is empty.
+func (r *feedRepository) ListCapsuleMessages(ctx context.Context, inviteID, authorID string) ([]models.FeedWithUser, error) {
+	var messages []models.FeedWithUser
+	err := r.db.SelectContext(ctx, &messages, QueryListCapsuleMessages, inviteID, authorID)
+	if messages == nil {
+		messages = []models.FeedWithUser{}
+	}
+	return messages, err
+}
+
+func (r *feedRepository) CountCapsuleMessages(ctx context.Context, inviteID string) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, QueryCountCapsuleMessages, inviteID)
+	return count, err
+}
+
+func (r *feedRepository) IsVaultUnlocked(ctx context.Context, inviteID string) (bool, error) {
+	var unlocked bool
+	err := r.db.GetContext(ctx, &unlocked, QueryGetVaultUnlocked, inviteID)
+	return unlocked, err
 }
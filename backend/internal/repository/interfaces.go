@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"privo-club-backend/internal/geo"
 	"privo-club-backend/internal/models"
 	"time"
 )
@@ -26,19 +27,138 @@ type CircleRepository interface {
 
 type InviteRepository interface {
 	CreateInvite(ctx context.Context, invite *models.Invite) error
-	ListInvites(ctx context.Context, userID string) ([]models.InviteListResponse, error)
+	CreateInviteWithRelations(ctx context.Context, invite *models.Invite, rel models.InviteRelations) error
+	AddInvitees(ctx context.Context, invitees []models.InviteInvitee) error
+	RemoveInvitee(ctx context.Context, inviteID, inviteeID string) error
+	CountUsers(ctx context.Context, userIDs []string) (int, error)
+	IsCircleMember(ctx context.Context, circleID, userID string) (bool, error)
+	ListInvites(ctx context.Context, userID string, filter models.InviteListFilter) ([]models.InviteListResponse, error)
 	GetInviteByID(ctx context.Context, id string) (*models.Invite, error)
 	GetSenderID(ctx context.Context, inviteID string) (string, error)
 	DeleteInvite(ctx context.Context, inviteID string) error
 	UpsertRSVP(ctx context.Context, rsvp *models.RSVP) error
-	GetInviteDetails(ctx context.Context, inviteID string) (*models.InviteDetails, error)
-	UpdateVaultStatus(ctx context.Context, inviteID string, isUnlocked bool, unlockDate time.Time) error
-	UpdateInvite(ctx context.Context, inviteID string, location, mapLink *string) error
+	GetInviteDetails(ctx context.Context, inviteID, viewerID string) (*models.InviteDetails, error)
+	UnlockVault(ctx context.Context, inviteID string, unlockDate time.Time) error
+	UpdateInvite(ctx context.Context, inviteID string, location, mapLink *string, place models.Place) error
+	ListCalendarInvites(ctx context.Context, userID string, from, to time.Time) ([]models.CalendarInvite, error)
+	ListOverlappingInvites(ctx context.Context, inviteID, userID string) ([]models.CalendarInvite, error)
+	ListNearbyInvites(ctx context.Context, userID string, center geo.Point, radiusKm float64) ([]models.Invite, error)
+}
+
+type PollRepository interface {
+	ListDatePoll(ctx context.Context, inviteID string) ([]models.DatePollOptionSummary, error)
+	AddDateOptions(ctx context.Context, options []models.DatePollOption) error
+	UpsertVotes(ctx context.Context, inviteID string, votes []models.DatePollVote) error
+	GetDateOption(ctx context.Context, inviteID, optionID string) (*models.DatePollOption, error)
+	FinalizeDatePoll(ctx context.Context, inviteID string, option *models.DatePollOption) error
+}
+
+type SignupRepository interface {
+	CreateSheet(ctx context.Context, sheet *models.SignupSheet, items []models.SignupItem) error
+	GetSheet(ctx context.Context, inviteID, sheetID string) (*models.SignupSheet, error)
+	AddItems(ctx context.Context, sheetID string, items []models.SignupItem) error
+	DeleteSheet(ctx context.Context, inviteID, sheetID string) error
+	ListSheets(ctx context.Context, inviteID string) ([]models.SignupSheetWithItems, error)
+	ClaimItem(ctx context.Context, inviteID string, claim *models.SignupClaim) (bool, error)
+	UnclaimItem(ctx context.Context, inviteID, itemID, userID string) error
+	GetRSVPStatus(ctx context.Context, inviteID, userID string) (string, error)
+	ListSummaryTargets(ctx context.Context, windowStart, windowEnd time.Time) ([]models.SignupSummaryTarget, error)
+	ListUnclaimedItems(ctx context.Context, sheetID string) ([]models.UnclaimedSignupItem, error)
+	PostSummary(ctx context.Context, sheetID string, post *models.EventFeedItem) (bool, error)
+}
+
+type ExpenseRepository interface {
+	ListAttendees(ctx context.Context, inviteID string) ([]models.User, error)
+	CreateExpense(ctx context.Context, expense *models.Expense, shares []models.ExpenseShare) error
+	GetExpense(ctx context.Context, inviteID, expenseID string) (*models.Expense, error)
+	DeleteExpense(ctx context.Context, inviteID, expenseID string) error
+	ListExpenses(ctx context.Context, inviteID string) ([]models.ExpenseWithShares, error)
+	CreateSettlement(ctx context.Context, settlement *models.Settlement) error
+	GetSettlement(ctx context.Context, inviteID, settlementID string) (*models.Settlement, error)
+	DeleteSettlement(ctx context.Context, inviteID, settlementID string) error
+	ListSettlements(ctx context.Context, inviteID string) ([]models.Settlement, error)
+}
+
+type RideRepository interface {
+	GetRSVPStatus(ctx context.Context, inviteID, userID string) (string, error)
+	UpsertOffer(ctx context.Context, offer *models.RideOffer) (bool, error)
+	GetOffer(ctx context.Context, inviteID, offerID string) (*models.RideOffer, error)
+	DeleteOffer(ctx context.Context, inviteID, offerID, driverID string) error
+	UpsertRequest(ctx context.Context, req *models.RideRequest) error
+	DeleteRequest(ctx context.Context, inviteID, riderID string) error
+	JoinOffer(ctx context.Context, req *models.RideRequest) (bool, error)
+	LeaveOffer(ctx context.Context, inviteID, offerID, riderID string) error
+	ListOffers(ctx context.Context, inviteID string) ([]models.RideOfferWithDriver, error)
+	ListRequests(ctx context.Context, inviteID string) ([]models.RideRequestWithRider, error)
+}
+
+type CheckInRepository interface {
+	GetUserRSVP(ctx context.Context, inviteID, userID string) (*models.RSVP, error)
+	GetEventEnd(ctx context.Context, inviteID string) (time.Time, error)
+	GetTicketHolder(ctx context.Context, inviteID, rsvpID string, guest bool) (*models.TicketHolder, error)
+	CreateCheckIn(ctx context.Context, checkIn *models.CheckIn) (bool, error)
+	ListCheckIns(ctx context.Context, inviteID string) ([]models.CheckIn, error)
+	GetAttendanceCounts(ctx context.Context, inviteID string) (*models.AttendanceCounts, error)
+}
+
+type TemplateRepository interface {
+	CreateTemplate(ctx context.Context, t *models.InviteTemplate) error
+	ListTemplates(ctx context.Context, userID string) ([]models.InviteTemplate, error)
+	GetTemplate(ctx context.Context, templateID, userID string) (*models.InviteTemplate, error)
+	DeleteTemplate(ctx context.Context, templateID, ownerID string) error
+	IsCircleMember(ctx context.Context, circleID, userID string) (bool, error)
+}
+
+type ReportRepository interface {
+	GetInvite(ctx context.Context, inviteID string) (*models.Invite, error)
+	ListReportGuests(ctx context.Context, inviteID string) ([]models.ReportGuest, error)
+}
+
+type VenueRepository interface {
+	CreateVenue(ctx context.Context, venue *models.Venue) error
+	GetVenue(ctx context.Context, circleID, venueID string) (*models.Venue, error)
+	UpdateVenue(ctx context.Context, venue *models.Venue) error
+	DeleteVenue(ctx context.Context, circleID, venueID string) error
+	ListVenues(ctx context.Context, circleID, search string) ([]models.VenueWithUsage, error)
+	GetMemberRole(ctx context.Context, circleID, userID string) (string, error)
+}
+
+type ShareRepository interface {
+	CreateShareLink(ctx context.Context, link *models.InviteShareLink) error
+	GetActiveShareLink(ctx context.Context, inviteID string) (*models.InviteShareLink, error)
+	RevokeShareLinks(ctx context.Context, inviteID string) error
+	GetPublicInvite(ctx context.Context, token string) (*models.PublicInviteView, error)
+	GetGuestPublicInvite(ctx context.Context, rsvpID string) (*models.PublicInviteView, error)
+	CreateGuestRSVP(ctx context.Context, rsvp *models.GuestRSVP, maxGuests int) (bool, error)
+	GetGuestRSVP(ctx context.Context, id string) (*models.GuestRSVP, error)
+	UpdateGuestRSVP(ctx context.Context, rsvp *models.GuestRSVP) error
+}
+
+type AccessRepository interface {
+	GetInviteAccess(ctx context.Context, inviteID, userID string) (*models.InviteAccess, error)
 }
 
 type FeedRepository interface {
-	CreatePost(ctx context.Context, item *models.EventFeedItem) error
-	GetFeed(ctx context.Context, inviteID string) ([]models.EventFeedItem, error)
+	CreatePost(ctx context.Context, item *models.EventFeedItem, mentions []models.FeedMention) error
+	EditPost(ctx context.Context, item *models.EventFeedItem, mentions []models.FeedMention) error
+	ListRevisions(ctx context.Context, postID string) ([]models.FeedPostRevision, error)
+	HidePost(ctx context.Context, postID, moderatorID, reason string, now time.Time) error
+	UnhidePost(ctx context.Context, postID string) error
+	RemovePost(ctx context.Context, postID, removedByID string, reason *string, now time.Time) error
+	GetPost(ctx context.Context, inviteID, postID string) (*models.EventFeedItem, error)
+	ListFeed(ctx context.Context, inviteID, viewerID string, filter models.FeedListFilter) ([]models.FeedPost, error)
+	ListReplies(ctx context.Context, postID, viewerID string) ([]models.FeedWithUser, error)
+	ListMentionCandidates(ctx context.Context, inviteID string) ([]models.MentionCandidate, error)
+	ListMentionsOfUser(ctx context.Context, userID string, filter models.FeedListFilter) ([]models.MentionedPost, error)
+	ClaimMentionNotifications(ctx context.Context, now time.Time) ([]models.MentionRecipient, error)
+	ReleaseMentionNotification(ctx context.Context, feedItemID, userID string) error
+	AddReaction(ctx context.Context, reaction *models.FeedReaction) error
+	RemoveReaction(ctx context.Context, postID, userID, emoji string) error
+	ListReactions(ctx context.Context, postID, viewerID string) ([]models.ReactionCount, error)
+	CreateCapsuleMessage(ctx context.Context, item *models.EventFeedItem) error
+	ListCapsuleMessages(ctx context.Context, inviteID, authorID string) ([]models.FeedWithUser, error)
+	CountCapsuleMessages(ctx context.Context, inviteID string) (int, error)
+	IsVaultUnlocked(ctx context.Context, inviteID string) (bool, error)
 }
 
 type AuthRepository interface {
@@ -53,4 +173,30 @@ type UserRepository interface {
 
 type MediaRepository interface {
 	CreateMedia(ctx context.Context, media *models.MediaItem) error
+	GetMediaByURL(ctx context.Context, url string) (*models.StoredMedia, error)
+}
+
+type ReminderRepository interface {
+	ListDueReminders(ctx context.Context, windowStart, windowEnd time.Time, offsetMinutes int) ([]models.ReminderTarget, error)
+	ClaimReminder(ctx context.Context, inviteID, userID string, offsetMinutes int) (bool, error)
+	ReleaseReminder(ctx context.Context, inviteID, userID string, offsetMinutes int) error
+}
+
+type VaultRepository interface {
+	ListVaultCandidates(ctx context.Context, now time.Time, limit int) ([]models.VaultCandidate, error)
+	UnlockVault(ctx context.Context, inviteID string, unlockDate time.Time) error
+	ListUnannouncedVaults(ctx context.Context) ([]string, error)
+	ClaimVaultDelivery(ctx context.Context, inviteID, userID string) (bool, error)
+	ReleaseVaultDelivery(ctx context.Context, inviteID, userID string) error
+	MarkVaultAnnounced(ctx context.Context, inviteID string, now time.Time) error
+	ListVaultRecipients(ctx context.Context, inviteID string) ([]models.VaultRecipient, error)
+}
+
+type RecapRepository interface {
+	ListPendingRecaps(ctx context.Context, limit int) ([]string, error)
+	CreateRecap(ctx context.Context, recap *models.InviteRecap) (bool, error)
+	GetRecap(ctx context.Context, inviteID string) (*models.InviteRecap, error)
+	IsAttendee(ctx context.Context, inviteID, userID string) (bool, error)
+	GetRecapByToken(ctx context.Context, token string) (*models.InviteRecap, error)
+	SetPublicToken(ctx context.Context, inviteID string, token *string) error
 }
@@ -2,12 +2,18 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"privo-club-backend/internal/geo"
 	"privo-club-backend/internal/models"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type inviteRepository struct {
@@ -18,22 +24,209 @@ func NewInviteRepository(db *sqlx.DB) InviteRepository {
 	return &inviteRepository{db: db}
 }
 
-func (r *inviteRepository) UpdateVaultStatus(ctx context.Context, inviteID string, isUnlocked bool, unlockDate time.Time) error {
-	_, err := r.db.ExecContext(ctx, QueryUpdateVaultStatus, inviteID, isUnlocked, unlockDate)
-	return err
+// UnlockVault opens a locked vault. It returns sql.ErrNoRows when the vault
+// was already open.
+func (r *inviteRepository) UnlockVault(ctx context.Context, inviteID string, unlockDate time.Time) error {
+	res, err := r.db.ExecContext(ctx, QueryUnlockVault, inviteID, unlockDate)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-func (r *inviteRepository) UpdateInvite(ctx context.Context, inviteID string, location, mapLink *string) error {
-	_, err := r.db.ExecContext(ctx, QueryUpdateInvite, inviteID, location, mapLink)
+func (r *inviteRepository) UpdateInvite(ctx context.Context, inviteID string, location, mapLink *string, place models.Place) error {
+	args := append([]interface{}{inviteID, location, mapLink}, placeArgs(place)...)
+	_, err := r.db.ExecContext(ctx, QueryUpdateInvite, args...)
 	return err
 }
 
 func (r *inviteRepository) CreateInvite(ctx context.Context, invite *models.Invite) error {
-	_, err := r.db.ExecContext(ctx, QueryCreateInvite, invite.ID, invite.Title, invite.Description, invite.Location, invite.MapLink, invite.EventDate, invite.SenderID, invite.CircleID, invite.IsVaultUnlocked, invite.VaultUnlockDate, invite.CreatedAt, invite.UpdatedAt)
+	_, err := r.db.ExecContext(ctx, QueryCreateInvite, createInviteArgs(invite)...)
+	return err
+}
+
+// createInviteArgs lists the QueryCreateInvite parameters in column order
+func createInviteArgs(invite *models.Invite) []interface{} {
+	return []interface{}{
+		invite.ID, invite.Title, invite.Description, invite.Location, invite.MapLink, invite.EventDate, invite.SenderID, invite.CircleID,
+		invite.IsVaultUnlocked, invite.VaultUnlockDate, invite.IsDateTBD, invite.VenueID, invite.EndDate,
+		invite.PlaceName, invite.AddressLine, invite.City, invite.Region, invite.PostalCode, invite.Country, invite.Latitude, invite.Longitude,
+		invite.UnlockPolicy, invite.UnlockAfterHours, invite.UnlockAt, invite.MinContributors,
+		invite.CreatedAt, invite.UpdatedAt,
+	}
+}
+
+// listInvitesQuery appends the filter conditions to QueryListInvites
+func listInvitesQuery(userID string, f models.InviteListFilter) (string, []interface{}) {
+	args := []interface{}{userID}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(QueryListInvites)
+
+	switch f.When {
+	case models.InvitesUpcoming:
+		sb.WriteString(` AND i."eventDate" >= ` + arg(f.Now))
+	case models.InvitesPast:
+		sb.WriteString(` AND i."eventDate" < ` + arg(f.Now))
+	}
+	if f.CircleID != nil {
+		sb.WriteString(` AND i."circleId" = ` + arg(*f.CircleID))
+	}
+	if f.HostedOnly {
+		sb.WriteString(` AND i."senderId" = $1`)
+	}
+	switch f.RSVPStatus {
+	case "":
+	case "NONE":
+		sb.WriteString(` AND NOT EXISTS (SELECT 1 FROM "RSVP" r WHERE r."inviteId" = i.id AND r."userId" = $1)`)
+	default:
+		sb.WriteString(` AND EXISTS (SELECT 1 FROM "RSVP" r WHERE r."inviteId" = i.id AND r."userId" = $1 AND r.status = ` + arg(f.RSVPStatus) + `)`)
+	}
+	if f.From != nil {
+		sb.WriteString(` AND i."eventDate" >= ` + arg(*f.From))
+	}
+	if f.To != nil {
+		sb.WriteString(` AND i."eventDate" < ` + arg(*f.To))
+	}
+
+	order, cmp := "ASC", ">"
+	if f.When == models.InvitesPast {
+		order, cmp = "DESC", "<"
+	}
+	if f.After != nil {
+		fmt.Fprintf(&sb, ` AND (i."eventDate", i.id) %s (%s, %s)`, cmp, arg(f.After.EventDate), arg(f.After.ID))
+	}
+	fmt.Fprintf(&sb, ` ORDER BY i."eventDate" %s, i.id %s LIMIT %s`, order, order, arg(f.Limit))
+
+	return sb.String(), args
+}
+
+// ListCalendarInvites returns visible invites overlapping [from, to).
+func (r *inviteRepository) ListCalendarInvites(ctx context.Context, userID string, from, to time.Time) ([]models.CalendarInvite, error) {
+	var invites []models.CalendarInvite
+	err := r.db.SelectContext(ctx, &invites, QueryListCalendarInvites, userID, from, to, models.DefaultEventDuration.Seconds())
+	return invites, err
+}
+
+// ListOverlappingInvites returns the user's other YES invites that overlap inviteID.
+func (r *inviteRepository) ListOverlappingInvites(ctx context.Context, inviteID, userID string) ([]models.CalendarInvite, error) {
+	var invites []models.CalendarInvite
+	err := r.db.SelectContext(ctx, &invites, QueryListOverlappingInvites, inviteID, userID, models.DefaultEventDuration.Seconds())
+	return invites, err
+}
+
+// placeArgs lists the place columns in the order the invite queries use them
+func placeArgs(p models.Place) []interface{} {
+	return []interface{}{p.PlaceName, p.AddressLine, p.City, p.Region, p.PostalCode, p.Country, p.Latitude, p.Longitude}
+}
+
+// ListNearbyInvites returns upcoming invites visible to userID within
+// radiusKm of center, closest first.
+func (r *inviteRepository) ListNearbyInvites(ctx context.Context, userID string, center geo.Point, radiusKm float64) ([]models.Invite, error) {
+	b := geo.BoundsAround(center, radiusKm)
+	var invites []models.Invite
+	err := r.db.SelectContext(ctx, &invites, QueryListNearbyInvites, userID, b.MinLat, b.MaxLat, b.MinLng, b.MaxLng, center.Lat, center.Lng, radiusKm)
+	return invites, err
+}
+
+// CreateInviteWithRelations creates the invite and its related rows in one transaction.
+func (r *inviteRepository) CreateInviteWithRelations(ctx context.Context, invite *models.Invite, rel models.InviteRelations) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryCreateInvite, createInviteArgs(invite)...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, invitee := range rel.Invitees {
+		_, err = tx.ExecContext(ctx, QueryAddInvitee, invitee.ID, invitee.InviteID, invitee.UserID, invitee.Email, invitee.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for _, option := range rel.DateOptions {
+		_, err = tx.ExecContext(ctx, QueryAddDatePollOption, option.ID, option.InviteID, option.StartsAt, option.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for _, sheet := range rel.SignupSheets {
+		_, err = tx.ExecContext(ctx, QueryCreateSignupSheet, sheet.ID, sheet.InviteID, sheet.Title, sheet.Description, sheet.CreatedByID, sheet.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for _, item := range rel.SignupItems {
+		_, err = tx.ExecContext(ctx, QueryCreateSignupItem, item.ID, item.SheetID, item.Name, item.Quantity, item.Position, item.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *inviteRepository) AddInvitees(ctx context.Context, invitees []models.InviteInvitee) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, invitee := range invitees {
+		_, err = tx.ExecContext(ctx, QueryAddInvitee, invitee.ID, invitee.InviteID, invitee.UserID, invitee.Email, invitee.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CountUsers returns how many of the given IDs belong to existing accounts
+func (r *inviteRepository) CountUsers(ctx context.Context, userIDs []string) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, QueryCountUsers, pq.Array(userIDs))
+	return count, err
+}
+
+// IsCircleMember reports whether the user is an ACTIVE member of the circle
+func (r *inviteRepository) IsCircleMember(ctx context.Context, circleID, userID string) (bool, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, QueryIsMember, circleID, userID)
+	return count > 0, err
+}
+
+func (r *inviteRepository) RemoveInvitee(ctx context.Context, inviteID, inviteeID string) error {
+	_, err := r.db.ExecContext(ctx, QueryRemoveInvitee, inviteeID, inviteID)
 	return err
 }
 
-func (r *inviteRepository) ListInvites(ctx context.Context, userID string) ([]models.InviteListResponse, error) {
+// ListInvites returns up to filter.Limit invites visible to userID, ordered
+// by date and then ID so the last row can serve as a cursor.
+func (r *inviteRepository) ListInvites(ctx context.Context, userID string, filter models.InviteListFilter) ([]models.InviteListResponse, error) {
 	type InviteRow struct {
 		models.Invite
 		SenderID    string  `db:"sender_id"`
@@ -47,7 +240,8 @@ func (r *inviteRepository) ListInvites(ctx context.Context, userID string) ([]mo
 		RSVPCount int `db:"rsvp_count"`
 	}
 
-	rows, err := r.db.QueryxContext(ctx, QueryListInvites, userID)
+	query, args := listInvitesQuery(userID, filter)
+	rows, err := r.db.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -105,11 +299,39 @@ func (r *inviteRepository) DeleteInvite(ctx context.Context, inviteID string) er
 }
 
 func (r *inviteRepository) UpsertRSVP(ctx context.Context, rsvp *models.RSVP) error {
-	_, err := r.db.ExecContext(ctx, QueryUpsertRSVP, rsvp.ID, rsvp.InviteID, rsvp.UserID, rsvp.Status, rsvp.GuestCount, rsvp.Dietary, rsvp.Note, rsvp.CreatedAt, rsvp.UpdatedAt)
-	return err
+	if rsvp.Status != "NO" {
+		_, err := r.db.ExecContext(ctx, QueryUpsertRSVP, rsvp.ID, rsvp.InviteID, rsvp.UserID, rsvp.Status, rsvp.GuestCount, rsvp.Dietary, rsvp.Note, rsvp.CreatedAt, rsvp.UpdatedAt)
+		return err
+	}
+
+	// Declining drops the user's ride offer (its riders go back to looking) and ride request
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryUpsertRSVP, rsvp.ID, rsvp.InviteID, rsvp.UserID, rsvp.Status, rsvp.GuestCount, rsvp.Dietary, rsvp.Note, rsvp.CreatedAt, rsvp.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryDeleteUserRideOffers, rsvp.InviteID, rsvp.UserID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryDeleteRideRequest, rsvp.InviteID, rsvp.UserID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *inviteRepository) GetInviteDetails(ctx context.Context, inviteID string) (*models.InviteDetails, error) {
+func (r *inviteRepository) GetInviteDetails(ctx context.Context, inviteID, viewerID string) (*models.InviteDetails, error) {
 	var details models.InviteDetails
 
 	// 1. Fetch Invite
@@ -128,26 +350,92 @@ func (r *inviteRepository) GetInviteDetails(ctx context.Context, inviteID string
 	if details.CircleID != nil {
 		var circle models.CircleWithMembers
 		err = r.db.GetContext(ctx, &circle.Circle, QueryGetInviteDetails_Circle, details.CircleID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
 		if err == nil {
 			// Fetch Members
-			err = r.db.SelectContext(ctx, &circle.Members, QueryGetInviteDetails_CircleMembers, circle.ID)
+			if err := r.db.SelectContext(ctx, &circle.Members, QueryGetInviteDetails_CircleMembers, circle.ID); err != nil {
+				return nil, err
+			}
 			details.Circle = &circle
 		}
 	}
 
 	// 4. Fetch RSVPs
 	err = r.db.SelectContext(ctx, &details.RSVPs, QueryGetInviteDetails_RSVPs, inviteID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Fetch Direct Invitees
+	type InviteeRow struct {
+		models.InviteInvitee
+		UserID    *string `db:"user.id"`
+		UserName  *string `db:"user.name"`
+		UserEmail *string `db:"user.email"`
+		UserImage *string `db:"user.image"`
+	}
+	var inviteeRows []InviteeRow
+	err = r.db.SelectContext(ctx, &inviteeRows, QueryGetInviteDetails_Invitees, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	details.Invitees = make([]models.InviteeWithUser, len(inviteeRows))
+	for i, row := range inviteeRows {
+		details.Invitees[i] = models.InviteeWithUser{InviteInvitee: row.InviteInvitee}
+		if row.UserID != nil {
+			details.Invitees[i].User = &models.User{
+				ID:    *row.UserID,
+				Name:  row.UserName,
+				Email: row.UserEmail,
+				Image: row.UserImage,
+			}
+		}
+	}
+
+	// 6. Fetch Guest RSVPs
+	err = r.db.SelectContext(ctx, &details.GuestRSVPs, QueryGetInviteDetails_GuestRSVPs, inviteID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 7. Fetch Date Poll while the date is undecided
+	if details.IsDateTBD {
+		details.DatePoll, err = listDatePoll(ctx, r.db, inviteID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 8. Fetch Sign-up Sheets
+	details.SignupSheets, err = listSignupSheets(ctx, r.db, inviteID)
+	if err != nil {
+		return nil, err
+	}
 
-	// 5. Fetch Feed Items
+	// 9. Fetch top-level Feed Items with their reply counts
 	err = r.db.SelectContext(ctx, &details.FeedItems, QueryGetInviteDetails_Feed, inviteID)
+	if err != nil {
+		return nil, err
+	}
 
-	// 6. Fetch Media Items
+	// 10. Fetch Media Items
 	err = r.db.SelectContext(ctx, &details.MediaItems, QueryGetInviteDetails_Media, inviteID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Ensure non-nil slices
 	if details.RSVPs == nil {
 		details.RSVPs = []models.RSVPWithUser{}
 	}
+	if details.GuestRSVPs == nil {
+		details.GuestRSVPs = []models.GuestRSVP{}
+	}
+	if details.SignupSheets == nil {
+		details.SignupSheets = []models.SignupSheetWithItems{}
+	}
 	if details.FeedItems == nil {
 		details.FeedItems = []models.FeedWithUser{}
 	}
@@ -155,5 +443,17 @@ func (r *inviteRepository) GetInviteDetails(ctx context.Context, inviteID string
 		details.MediaItems = []models.MediaItem{}
 	}
 
+	// 11. Reactions to the feed items, as seen by the viewer, and their mentions
+	items := make([]*models.FeedWithUser, len(details.FeedItems))
+	for i := range details.FeedItems {
+		items[i] = &details.FeedItems[i]
+	}
+	if err := attachReactions(ctx, r.db, viewerID, items); err != nil {
+		return nil, err
+	}
+	if err := attachMentions(ctx, r.db, items); err != nil {
+		return nil, err
+	}
+
 	return &details, nil
 }
@@ -19,3 +19,11 @@ func (r *mediaRepository) CreateMedia(ctx context.Context, media *models.MediaIt
 	_, err := r.db.ExecContext(ctx, QueryCreateMedia, media.ID, media.InviteID, media.UserID, media.URL, media.Type, media.Caption, media.CreatedAt)
 	return err
 }
+
+func (r *mediaRepository) GetMediaByURL(ctx context.Context, url string) (*models.StoredMedia, error) {
+	var media models.StoredMedia
+	if err := r.db.GetContext(ctx, &media, QueryGetMediaByURL, url); err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
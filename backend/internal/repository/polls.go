@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrUnknownOption is returned by UpsertVotes when an option is not one of the invite's
+	ErrUnknownOption = errors.New("date option does not belong to the invite")
+	// ErrPollClosed is returned by UpsertVotes once the invite's date has been set
+	ErrPollClosed = errors.New("date poll is already finalized")
+)
+
+type pollRepository struct {
+	db *sqlx.DB
+}
+
+func NewPollRepository(db *sqlx.DB) PollRepository {
+	return &pollRepository{db: db}
+}
+
+func (r *pollRepository) ListDatePoll(ctx context.Context, inviteID string) ([]models.DatePollOptionSummary, error) {
+	return listDatePoll(ctx, r.db, inviteID)
+}
+
+// listDatePoll loads the candidate times of an invite with their tallied votes.
+func listDatePoll(ctx context.Context, db *sqlx.DB, inviteID string) ([]models.DatePollOptionSummary, error) {
+	var options []models.DatePollOption
+	if err := db.SelectContext(ctx, &options, QueryListDatePollOptions, inviteID); err != nil {
+		return nil, err
+	}
+
+	type VoteRow struct {
+		models.DatePollVote
+		UserID    string  `db:"user.id"`
+		UserName  *string `db:"user.name"`
+		UserEmail *string `db:"user.email"`
+		UserImage *string `db:"user.image"`
+	}
+	var voteRows []VoteRow
+	if err := db.SelectContext(ctx, &voteRows, QueryListDatePollVotes, inviteID); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.DatePollOptionSummary, len(options))
+	index := make(map[string]*models.DatePollOptionSummary, len(options))
+	for i, option := range options {
+		summaries[i] = models.DatePollOptionSummary{
+			DatePollOption: option,
+			Votes:          []models.DatePollVoteWithUser{},
+		}
+		index[option.ID] = &summaries[i]
+	}
+
+	for _, row := range voteRows {
+		summary, ok := index[row.OptionID]
+		if !ok {
+			continue
+		}
+		switch row.Vote {
+		case "YES":
+			summary.Yes++
+		case "IF_NEEDED":
+			summary.IfNeeded++
+		case "NO":
+			summary.No++
+		}
+		summary.Votes = append(summary.Votes, models.DatePollVoteWithUser{
+			DatePollVote: row.DatePollVote,
+			User: models.User{
+				ID:    row.UserID,
+				Name:  row.UserName,
+				Email: row.UserEmail,
+				Image: row.UserImage,
+			},
+		})
+	}
+	return summaries, nil
+}
+
+// AddDateOptions stores new candidate times for one invite and moves its
+// placeholder eventDate to the earliest option.
+func (r *pollRepository) AddDateOptions(ctx context.Context, options []models.DatePollOption) error {
+	if len(options) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, option := range options {
+		_, err = tx.ExecContext(ctx, QueryAddDatePollOption, option.ID, option.InviteID, option.StartsAt, option.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, QueryMoveDatePollPlaceholder, options[0].InviteID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *pollRepository) UpsertVotes(ctx context.Context, inviteID string, votes []models.DatePollVote) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Holds off a concurrent Finalize until the votes are in
+	var isOpen bool
+	if err := tx.GetContext(ctx, &isOpen, QueryLockOpenDatePoll, inviteID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if !isOpen {
+		tx.Rollback()
+		return ErrPollClosed
+	}
+
+	for _, vote := range votes {
+		res, err := tx.ExecContext(ctx, QueryUpsertDatePollVote, vote.ID, vote.OptionID, vote.UserID, vote.Vote, vote.UpdatedAt, inviteID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			tx.Rollback()
+			return fmt.Errorf("%w: %s", ErrUnknownOption, vote.OptionID)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *pollRepository) GetDateOption(ctx context.Context, inviteID, optionID string) (*models.DatePollOption, error) {
+	var option models.DatePollOption
+	if err := r.db.GetContext(ctx, &option, QueryGetDatePollOption, optionID, inviteID); err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+// FinalizeDatePoll fixes the invite's date to the chosen option and turns the
+// votes for that option into initial RSVPs: YES stays YES, IF_NEEDED becomes
+// MAYBE and NO becomes NO. Existing RSVPs are left alone.
+func (r *pollRepository) FinalizeDatePoll(ctx context.Context, inviteID string, option *models.DatePollOption) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, QueryFinalizeDatePoll, inviteID, option.StartsAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return fmt.Errorf("date poll for invite %s is already finalized", inviteID)
+	}
+
+	var votes []models.DatePollVote
+	if err := tx.SelectContext(ctx, &votes, QueryListOptionVotes, option.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	now := time.Now()
+	for _, vote := range votes {
+		status := vote.Vote
+		if status == "IF_NEEDED" {
+			status = "MAYBE"
+		}
+		_, err = tx.ExecContext(ctx, QueryInsertRSVPIfMissing, utils.GenerateID("rsvp"), inviteID, vote.UserID, status, now)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
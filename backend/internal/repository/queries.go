@@ -10,7 +10,7 @@ const (
 		name = EXCLUDED.name,
 		email = EXCLUDED.email,
 		image = EXCLUDED.image,
-		"emailVerified" = EXCLUDED."emailVerified"
+		"emailVerified" = COALESCE(EXCLUDED."emailVerified", "User"."emailVerified")
 	`
 
 	// Circle Queries
@@ -72,35 +72,98 @@ const (
 
 	// Invite Queries
 	QueryCreateInvite = `
-		INSERT INTO "Invite" (id, title, description, location, map_link, "eventDate", "senderId", "circleId", "isVaultUnlocked", "vaultUnlockDate", "createdAt", "updatedAt")
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO "Invite" (
+			id, title, description, location, map_link, "eventDate", "senderId", "circleId", "isVaultUnlocked", "vaultUnlockDate", "isDateTBD", "venueId", "endDate",
+			"placeName", "addressLine", city, region, "postalCode", country, latitude, longitude,
+			"vaultPolicy", "vaultUnlockAfterHours", "vaultUnlockAt", "vaultMinContributors", "createdAt", "updatedAt"
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
 	`
+	// Invites $1 hosts, was invited to directly, or that belong to a circle $1 is an ACTIVE member of
+	// Great-circle distance in km from ($6, $7) to the invite, as geo.DistanceKm
+	queryDistanceKm = `(2 * 6371.0 * asin(least(1, sqrt(
+		power(sin(radians(i.latitude - $6) / 2), 2) +
+		cos(radians($6)) * cos(radians(i.latitude)) * power(sin(radians(i.longitude - $7) / 2), 2)))))`
+	queryInviteVisible = `
+		(
+			i."senderId" = $1
+			OR EXISTS (
+				SELECT 1 FROM "CircleMember" cm
+				WHERE cm."circleId" = i."circleId" AND cm."userId" = $1 AND cm.status = 'ACTIVE'
+			)
+			OR EXISTS (
+				SELECT 1 FROM "InviteInvitee" inv
+				WHERE inv."inviteId" = i.id AND inv."userId" = $1
+			)
+		)
+	`
+	// ListInvites appends its filters, ordering and limit
 	QueryListInvites = `
-		SELECT DISTINCT
-			i.id, i.title, i.description, i.location, i.map_link, i."eventDate", i."senderId", i."circleId", i."isVaultUnlocked", i."vaultUnlockDate", i."createdAt", i."updatedAt",
+		SELECT
+			i.id, i.title, i.description, i.location, i.map_link, i."eventDate", i."senderId", i."circleId", i."isVaultUnlocked", i."vaultUnlockDate", i."isDateTBD", i."venueId", i."endDate", i."createdAt", i."updatedAt",
+			i."placeName", i."addressLine", i.city, i.region, i."postalCode", i.country, i.latitude, i.longitude,
+			i."vaultPolicy", i."vaultUnlockAfterHours", i."vaultUnlockAt", i."vaultMinContributors",
 			sender.id as sender_id, sender.name as sender_name, sender.email as sender_email, sender.image as sender_image,
 			circle.id as circle_id, circle.name as circle_name,
 			(SELECT count(*)::int FROM "RSVP" WHERE "inviteId" = i.id) as rsvp_count
 		FROM "Invite" i
 		JOIN "User" sender ON i."senderId" = sender.id
 		LEFT JOIN "Circle" circle ON i."circleId" = circle.id
-		LEFT JOIN "CircleMember" cm ON i."circleId" = cm."circleId"
-		WHERE i."senderId" = $1 OR cm."userId" = $1
-		ORDER BY i."eventDate" ASC
+		WHERE ` + queryInviteVisible
+	// Invites visible to $1 that overlap [$2, $3); $4 is the default duration in seconds
+	QueryListCalendarInvites = `
+		SELECT
+			i.id, i.title, i."eventDate", COALESCE(i."endDate", i."eventDate" + make_interval(secs => $4)) AS "endDate",
+			i.location, i."senderId", i."circleId", i."isDateTBD",
+			(SELECT r.status FROM "RSVP" r WHERE r."inviteId" = i.id AND r."userId" = $1) AS "rsvpStatus"
+		FROM "Invite" i
+		WHERE i."eventDate" < $3
+		AND COALESCE(i."endDate", i."eventDate" + make_interval(secs => $4)) > $2
+		AND ` + queryInviteVisible + `
+		ORDER BY i."eventDate" ASC, i.id ASC
 	`
-	QueryGetInviteByID     = `SELECT * FROM "Invite" WHERE id = $1`
-	QueryGetSenderID       = `SELECT "senderId" FROM "Invite" WHERE id = $1`
-	QueryDeleteInvite      = `DELETE FROM "Invite" WHERE id = $1`
-	QueryUpdateVaultStatus = `
-		UPDATE "Invite" 
-		SET "isVaultUnlocked" = $2, "vaultUnlockDate" = $3, "updatedAt" = NOW() 
-		WHERE id = $1
+	// Other invites $2 said YES to that overlap invite $1; TBD dates are placeholders and never overlap
+	QueryListOverlappingInvites = `
+		SELECT
+			o.id, o.title, o."eventDate", COALESCE(o."endDate", o."eventDate" + make_interval(secs => $3)) AS "endDate",
+			o.location, o."senderId", o."circleId", o."isDateTBD", r.status AS "rsvpStatus"
+		FROM "Invite" i
+		JOIN "RSVP" r ON r."userId" = $2 AND r.status = 'YES' AND r."inviteId" <> i.id
+		JOIN "Invite" o ON o.id = r."inviteId"
+		WHERE i.id = $1 AND NOT i."isDateTBD" AND NOT o."isDateTBD"
+		AND o."eventDate" < COALESCE(i."endDate", i."eventDate" + make_interval(secs => $3))
+		AND COALESCE(o."endDate", o."eventDate" + make_interval(secs => $3)) > i."eventDate"
+		ORDER BY o."eventDate" ASC
+	`
+	QueryGetInviteByID = `SELECT * FROM "Invite" WHERE id = $1`
+	QueryGetSenderID   = `SELECT "senderId" FROM "Invite" WHERE id = $1`
+	QueryDeleteInvite  = `DELETE FROM "Invite" WHERE id = $1`
+	// Only set while still locked, so a vault is never unlocked twice
+	QueryUnlockVault = `
+		UPDATE "Invite"
+		SET "isVaultUnlocked" = TRUE, "vaultUnlockDate" = $2, "updatedAt" = NOW()
+		WHERE id = $1 AND NOT "isVaultUnlocked"
 	`
 	QueryUpdateInvite = `
 		UPDATE "Invite"
-		SET location = $2, map_link = $3, "updatedAt" = NOW()
+		SET location = $2, map_link = $3,
+			"placeName" = $4, "addressLine" = $5, city = $6, region = $7, "postalCode" = $8, country = $9, latitude = $10, longitude = $11,
+			"venueId" = NULL, -- An edited location no longer counts as a use of the venue
+			"updatedAt" = NOW()
 		WHERE id = $1
 	`
+	// Upcoming invites visible to $1 within $8 km of ($6, $7), closest first.
+	// The lat/lng box ($2-$5) lets the index narrow the rows before the
+	// haversine distance is computed, so the limit only cuts the farthest.
+	QueryListNearbyInvites = `
+		SELECT i.* FROM "Invite" i
+		WHERE i.latitude BETWEEN $2 AND $3 AND i.longitude BETWEEN $4 AND $5
+		AND ` + queryDistanceKm + ` <= $8
+		AND i."eventDate" >= NOW()
+		AND ` + queryInviteVisible + `
+		ORDER BY ` + queryDistanceKm + ` ASC, i."eventDate" ASC
+		LIMIT 200
+	`
 	QueryUpsertRSVP = `
         INSERT INTO "RSVP" (id, "inviteId", "userId", status, "guestCount", dietary, note, "createdAt", "updatedAt")
         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
@@ -125,30 +188,691 @@ const (
         FROM "RSVP" r
         JOIN "User" u ON r."userId" = u.id
         WHERE r."inviteId" = $1
+    `
+	QueryGetInviteDetails_Invitees = `
+        SELECT inv.*, u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
+        FROM "InviteInvitee" inv
+        LEFT JOIN "User" u ON inv."userId" = u.id
+        WHERE inv."inviteId" = $1
+        ORDER BY inv."createdAt" ASC
+    `
+	QueryGetInviteDetails_GuestRSVPs = `
+        SELECT * FROM "GuestRSVP"
+        WHERE "inviteId" = $1
+        ORDER BY "createdAt" ASC
     `
 	QueryGetInviteDetails_Feed = `
-        SELECT f.*, u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
+        SELECT f.*, ` + queryFeedReplyCount + ` AS "replyCount",
+            u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
         FROM "EventFeedItem" f
         JOIN "User" u ON f."userId" = u.id
-        WHERE f."inviteId" = $1
-        ORDER BY f."createdAt" DESC
+        JOIN "Invite" i ON i.id = f."inviteId"
+        WHERE f."inviteId" = $1 AND f."parentId" IS NULL AND ` + queryFeedReleased + `
+        ORDER BY ` + queryFeedPostedAt + ` DESC
     `
 	QueryGetInviteDetails_Media = `SELECT * FROM "MediaItem" WHERE "inviteId" = $1`
 
+	// Invitee Queries
+	// Email invitees are linked to an existing account straight away when one
+	// matches and its address is verified.
+	QueryAddInvitee = `
+		INSERT INTO "InviteInvitee" (id, "inviteId", "userId", email, "createdAt")
+		VALUES ($1, $2, COALESCE($3, (SELECT id FROM "User" WHERE lower(email) = lower($4) AND "emailVerified" IS NOT NULL LIMIT 1)), $4, $5)
+		ON CONFLICT DO NOTHING
+	`
+	QueryRemoveInvitee        = `DELETE FROM "InviteInvitee" WHERE id = $1 AND "inviteId" = $2`
+	QueryCountUsers           = `SELECT COUNT(*) FROM "User" WHERE id = ANY($1)`
+	QueryResolveInviteeEmails = `
+		UPDATE "InviteInvitee" inv
+		SET "userId" = $1
+		WHERE lower(inv.email) = lower($2) AND inv."userId" IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM "InviteInvitee" existing
+			WHERE existing."inviteId" = inv."inviteId" AND existing."userId" = $1
+		)
+	`
+
+	// Date Poll Queries
+	QueryAddDatePollOption = `
+		INSERT INTO "DatePollOption" (id, "inviteId", "startsAt", "createdAt")
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ("inviteId", "startsAt") DO NOTHING
+	`
+	// While the poll is open eventDate holds the earliest option, so the
+	// invite sorts and shows on the calendar where it might happen
+	QueryMoveDatePollPlaceholder = `
+		UPDATE "Invite" i
+		SET "eventDate" = o.first, "endDate" = o.first + (i."endDate" - i."eventDate"), "updatedAt" = NOW()
+		FROM (SELECT MIN("startsAt") AS first FROM "DatePollOption" WHERE "inviteId" = $1) o
+		WHERE i.id = $1 AND i."isDateTBD" AND o.first IS NOT NULL
+	`
+	QueryListDatePollOptions = `SELECT * FROM "DatePollOption" WHERE "inviteId" = $1 ORDER BY "startsAt" ASC`
+	QueryListDatePollVotes   = `
+		SELECT v.*, u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
+		FROM "DatePollVote" v
+		JOIN "DatePollOption" o ON o.id = v."optionId"
+		JOIN "User" u ON v."userId" = u.id
+		WHERE o."inviteId" = $1
+		ORDER BY v."createdAt" ASC
+	`
+	// Only options belonging to the invite can be voted on
+	QueryUpsertDatePollVote = `
+		INSERT INTO "DatePollVote" (id, "optionId", "userId", vote, "createdAt", "updatedAt")
+		SELECT $1, o.id, $3, $4, $5, $5 FROM "DatePollOption" o WHERE o.id = $2 AND o."inviteId" = $6
+		ON CONFLICT ("optionId", "userId") DO UPDATE SET
+		vote = EXCLUDED.vote,
+		"updatedAt" = EXCLUDED."updatedAt"
+	`
+	QueryLockOpenDatePoll  = `SELECT "isDateTBD" FROM "Invite" WHERE id = $1 FOR SHARE`
+	QueryGetDatePollOption = `SELECT * FROM "DatePollOption" WHERE id = $1 AND "inviteId" = $2`
+	QueryFinalizeDatePoll  = `
+		UPDATE "Invite"
+		SET "eventDate" = $2, "endDate" = $2 + ("endDate" - "eventDate"), "isDateTBD" = FALSE, "updatedAt" = NOW()
+		WHERE id = $1 AND "isDateTBD"
+	`
+	QueryListOptionVotes = `SELECT * FROM "DatePollVote" WHERE "optionId" = $1`
+	// Votes only seed RSVPs; answers people already gave are kept
+	QueryInsertRSVPIfMissing = `
+		INSERT INTO "RSVP" (id, "inviteId", "userId", status, "guestCount", "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, 1, $5, $5)
+		ON CONFLICT ("inviteId", "userId") DO NOTHING
+	`
+
+	// Sign-up Sheet Queries
+	QueryCreateSignupSheet = `
+		INSERT INTO "SignupSheet" (id, "inviteId", title, description, "createdById", "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	QueryCreateSignupItem = `
+		INSERT INTO "SignupItem" (id, "sheetId", name, quantity, position, "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	QueryNextSignupItemPosition = `SELECT COALESCE(MAX(position) + 1, 0) FROM "SignupItem" WHERE "sheetId" = $1`
+	QueryGetSignupSheet         = `SELECT * FROM "SignupSheet" WHERE id = $1 AND "inviteId" = $2`
+	QueryDeleteSignupSheet      = `DELETE FROM "SignupSheet" WHERE id = $1 AND "inviteId" = $2`
+	QueryListSignupSheets       = `SELECT * FROM "SignupSheet" WHERE "inviteId" = $1 ORDER BY "createdAt" ASC`
+	QueryListSignupItems        = `
+		SELECT it.* FROM "SignupItem" it
+		JOIN "SignupSheet" s ON s.id = it."sheetId"
+		WHERE s."inviteId" = $1
+		ORDER BY it.position ASC, it."createdAt" ASC
+	`
+	QueryListSignupClaims = `
+		SELECT c.*, u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
+		FROM "SignupClaim" c
+		JOIN "SignupItem" it ON it.id = c."itemId"
+		JOIN "SignupSheet" s ON s.id = it."sheetId"
+		JOIN "User" u ON c."userId" = u.id
+		WHERE s."inviteId" = $1
+		ORDER BY c."createdAt" ASC
+	`
+	// Locks the item so concurrent claims cannot overfill it
+	QueryLockSignupItem = `
+		SELECT it.quantity FROM "SignupItem" it
+		JOIN "SignupSheet" s ON s.id = it."sheetId"
+		WHERE it.id = $1 AND s."inviteId" = $2
+		FOR UPDATE OF it
+	`
+	QueryCountOtherSignupClaims = `SELECT COALESCE(SUM(quantity), 0) FROM "SignupClaim" WHERE "itemId" = $1 AND "userId" <> $2`
+	QueryUpsertSignupClaim      = `
+		INSERT INTO "SignupClaim" (id, "itemId", "userId", quantity, "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT ("itemId", "userId") DO UPDATE SET
+		quantity = EXCLUDED.quantity,
+		"updatedAt" = EXCLUDED."updatedAt"
+	`
+	QueryDeleteSignupClaim = `
+		DELETE FROM "SignupClaim" c
+		USING "SignupItem" it, "SignupSheet" s
+		WHERE c."itemId" = $1 AND c."userId" = $2
+		AND it.id = c."itemId" AND s.id = it."sheetId" AND s."inviteId" = $3
+	`
+	QueryGetRSVPStatus            = `SELECT status FROM "RSVP" WHERE "inviteId" = $1 AND "userId" = $2`
+	QueryListSignupSummaryTargets = `
+		SELECT s.*, i.title AS "inviteTitle"
+		FROM "SignupSheet" s
+		JOIN "Invite" i ON i.id = s."inviteId"
+		WHERE s."summaryPostedAt" IS NULL
+		AND NOT i."isDateTBD"
+		AND i."eventDate" > $1 AND i."eventDate" <= $2
+	`
+	QueryListUnclaimedSignupItems = `
+		SELECT it.id, it.name, it.quantity - COALESCE(SUM(c.quantity), 0) AS remaining
+		FROM "SignupItem" it
+		LEFT JOIN "SignupClaim" c ON c."itemId" = it.id
+		WHERE it."sheetId" = $1
+		GROUP BY it.id
+		HAVING it.quantity - COALESCE(SUM(c.quantity), 0) > 0
+		ORDER BY it.position ASC
+	`
+	QueryMarkSignupSummaryPosted = `UPDATE "SignupSheet" SET "summaryPostedAt" = $2 WHERE id = $1 AND "summaryPostedAt" IS NULL`
+
+	// Expense Queries
+	// Attendees are the host plus everyone who RSVP'd YES
+	QueryListAttendees = `
+		SELECT u.* FROM "User" u
+		WHERE u.id = (SELECT "senderId" FROM "Invite" WHERE id = $1)
+		OR u.id IN (SELECT "userId" FROM "RSVP" WHERE "inviteId" = $1 AND status = 'YES')
+		ORDER BY u.name ASC
+	`
+	QueryCreateExpense = `
+		INSERT INTO "Expense" (id, "inviteId", "paidById", description, "amountCents", "splitType", "createdById", "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	QueryCreateExpenseShare = `
+		INSERT INTO "ExpenseShare" (id, "expenseId", "userId", shares, "amountCents")
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	QueryGetExpense        = `SELECT * FROM "Expense" WHERE id = $1 AND "inviteId" = $2`
+	QueryDeleteExpense     = `DELETE FROM "Expense" WHERE id = $1 AND "inviteId" = $2`
+	QueryListExpenses      = `SELECT * FROM "Expense" WHERE "inviteId" = $1 ORDER BY "createdAt" ASC`
+	QueryListExpenseShares = `
+		SELECT es.* FROM "ExpenseShare" es
+		JOIN "Expense" e ON e.id = es."expenseId"
+		WHERE e."inviteId" = $1
+	`
+	QueryCreateSettlement = `
+		INSERT INTO "Settlement" (id, "inviteId", "fromUserId", "toUserId", "amountCents", "createdById", "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	QueryGetSettlement    = `SELECT * FROM "Settlement" WHERE id = $1 AND "inviteId" = $2`
+	QueryDeleteSettlement = `DELETE FROM "Settlement" WHERE id = $1 AND "inviteId" = $2`
+	QueryListSettlements  = `SELECT * FROM "Settlement" WHERE "inviteId" = $1 ORDER BY "createdAt" ASC`
+
+	// Ride Queries
+	QueryLockRideOfferByDriver = `SELECT id FROM "RideOffer" WHERE "inviteId" = $1 AND "driverId" = $2 FOR UPDATE`
+	QueryLockRideOffer         = `SELECT seats FROM "RideOffer" WHERE id = $1 AND "inviteId" = $2 FOR UPDATE`
+	QueryCountRideRiders       = `SELECT count(*) FROM "RideRequest" WHERE "offerId" = $1 AND "riderId" <> $2`
+	QueryCountJoinedRides      = `SELECT count(*) FROM "RideRequest" WHERE "inviteId" = $1 AND "riderId" = $2 AND "offerId" IS NOT NULL`
+	QueryUpsertRideOffer       = `
+		INSERT INTO "RideOffer" (id, "inviteId", "driverId", seats, "departureArea", "departureTime", notes, "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT ("inviteId", "driverId") DO UPDATE SET
+		seats = EXCLUDED.seats,
+		"departureArea" = EXCLUDED."departureArea",
+		"departureTime" = EXCLUDED."departureTime",
+		notes = EXCLUDED.notes,
+		"updatedAt" = EXCLUDED."updatedAt"
+	`
+	QueryGetRideOffer    = `SELECT * FROM "RideOffer" WHERE id = $1 AND "inviteId" = $2`
+	QueryDeleteRideOffer = `DELETE FROM "RideOffer" WHERE id = $1 AND "inviteId" = $2 AND "driverId" = $3`
+	QueryListRideOffers  = `
+		SELECT o.*, u.id "driver.id", u.name "driver.name", u.email "driver.email", u.image "driver.image"
+		FROM "RideOffer" o
+		JOIN "User" u ON o."driverId" = u.id
+		WHERE o."inviteId" = $1
+		ORDER BY o."departureTime" ASC
+	`
+	QueryUpsertRideRequest = `
+		INSERT INTO "RideRequest" (id, "inviteId", "riderId", "pickupArea", notes, "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT ("inviteId", "riderId") DO UPDATE SET
+		"pickupArea" = EXCLUDED."pickupArea",
+		notes = EXCLUDED.notes,
+		"updatedAt" = EXCLUDED."updatedAt"
+	`
+	QueryJoinRideOffer = `
+		INSERT INTO "RideRequest" (id, "inviteId", "riderId", "offerId", "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT ("inviteId", "riderId") DO UPDATE SET
+		"offerId" = EXCLUDED."offerId",
+		"updatedAt" = EXCLUDED."updatedAt"
+	`
+	QueryLeaveRideOffer    = `UPDATE "RideRequest" SET "offerId" = NULL, "updatedAt" = NOW() WHERE "inviteId" = $1 AND "riderId" = $2 AND "offerId" = $3`
+	QueryDeleteRideRequest = `DELETE FROM "RideRequest" WHERE "inviteId" = $1 AND "riderId" = $2`
+	QueryListRideRequests  = `
+		SELECT r.*, u.id "rider.id", u.name "rider.name", u.email "rider.email", u.image "rider.image"
+		FROM "RideRequest" r
+		JOIN "User" u ON r."riderId" = u.id
+		WHERE r."inviteId" = $1
+		ORDER BY r."createdAt" ASC
+	`
+	QueryDeleteUserRideOffers = `DELETE FROM "RideOffer" WHERE "inviteId" = $1 AND "driverId" = $2`
+
+	// Check-in Queries
+	QueryGetUserRSVP         = `SELECT * FROM "RSVP" WHERE "inviteId" = $1 AND "userId" = $2`
+	QueryGetInviteEventEnd   = `SELECT COALESCE("endDate", "eventDate") FROM "Invite" WHERE id = $1`
+	QueryGetRSVPTicketHolder = `
+		SELECT r."userId", NULL AS "guestRsvpId", u.name, r.status, r."guestCount"
+		FROM "RSVP" r
+		JOIN "User" u ON u.id = r."userId"
+		WHERE r.id = $1 AND r."inviteId" = $2
+	`
+	QueryGetGuestTicketHolder = `
+		SELECT NULL AS "userId", g.id AS "guestRsvpId", g.name, g.status, g."guestCount"
+		FROM "GuestRSVP" g
+		WHERE g.id = $1 AND g."inviteId" = $2
+	`
+	QueryCreateCheckIn = `
+		INSERT INTO "CheckIn" (id, "inviteId", "userId", "guestRsvpId", name, "guestCount", "checkedInById", "checkedInAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT DO NOTHING
+	`
+	QueryGetExistingCheckIn  = `SELECT * FROM "CheckIn" WHERE "inviteId" = $1 AND ("userId" = $2 OR "guestRsvpId" = $3)`
+	QueryListCheckIns        = `SELECT * FROM "CheckIn" WHERE "inviteId" = $1 ORDER BY "checkedInAt" DESC`
+	QueryGetAttendanceCounts = `
+		SELECT
+			(SELECT COALESCE(SUM("guestCount"), 0) FROM "RSVP" WHERE "inviteId" = $1 AND status = 'YES')
+			+ (SELECT COALESCE(SUM("guestCount"), 0) FROM "GuestRSVP" WHERE "inviteId" = $1 AND status = 'YES') AS expected,
+			(SELECT COALESCE(SUM("guestCount"), 0) FROM "CheckIn" WHERE "inviteId" = $1) AS "checkedIn",
+			(SELECT count(*) FROM "CheckIn" WHERE "inviteId" = $1 AND "userId" IS NULL AND "guestRsvpId" IS NULL) AS "walkIns"
+	`
+
+	// Report Queries
+	QueryListReportGuests = `
+		SELECT 'MEMBER' AS kind, COALESCE(u.name, u.email, 'Member') AS name, u.email,
+			r.status, r."guestCount", r.dietary, r.note, r."updatedAt" AS "respondedAt"
+		FROM "RSVP" r
+		JOIN "User" u ON u.id = r."userId"
+		WHERE r."inviteId" = $1
+		UNION ALL
+		SELECT 'GUEST' AS kind, g.name, g.email, g.status, g."guestCount", g.dietary, g.note, g."updatedAt" AS "respondedAt"
+		FROM "GuestRSVP" g
+		WHERE g."inviteId" = $1
+	`
+
+	// Template Queries
+	QueryCreateTemplate = `
+		INSERT INTO "InviteTemplate" (id, name, "ownerId", "circleId", title, description, location, "mapLink",
+			"placeName", "addressLine", city, region, "postalCode", country, latitude, longitude, "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $17)
+	`
+	// Own templates plus those shared with circles the user is an active member of
+	queryTemplateVisible = `
+		(t."ownerId" = $1 OR t."circleId" IN (
+			SELECT "circleId" FROM "CircleMember" WHERE "userId" = $1 AND status = 'ACTIVE'
+		))
+	`
+	QueryListTemplates  = `SELECT t.* FROM "InviteTemplate" t WHERE ` + queryTemplateVisible + ` ORDER BY t.name ASC`
+	QueryGetTemplate    = `SELECT t.* FROM "InviteTemplate" t WHERE t.id = $2 AND ` + queryTemplateVisible
+	QueryDeleteTemplate = `DELETE FROM "InviteTemplate" WHERE id = $1 AND "ownerId" = $2`
+
+	// Venue Queries
+	QueryCreateVenue = `
+		INSERT INTO "Venue" (id, "circleId", name, address, "mapLink", notes, capacity, latitude, longitude, "createdById", "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)
+	`
+	QueryGetVenue    = `SELECT * FROM "Venue" WHERE id = $1 AND "circleId" = $2`
+	QueryUpdateVenue = `
+		UPDATE "Venue"
+		SET name = $3, address = $4, "mapLink" = $5, notes = $6, capacity = $7, latitude = $8, longitude = $9, "updatedAt" = NOW()
+		WHERE id = $1 AND "circleId" = $2
+	`
+	QueryDeleteVenue = `DELETE FROM "Venue" WHERE id = $1 AND "circleId" = $2`
+	// Most used first so the top entries double as suggestions; $2 filters by
+	// name as a plain substring, so % and _ in the search match themselves
+	QueryListVenues = `
+		SELECT v.*, count(i.id)::int AS "useCount", max(i."eventDate") AS "lastUsedAt"
+		FROM "Venue" v
+		LEFT JOIN "Invite" i ON i."venueId" = v.id
+		WHERE v."circleId" = $1 AND ($2::text = '' OR strpos(lower(v.name), lower($2)) > 0)
+		GROUP BY v.id
+		ORDER BY "useCount" DESC, "lastUsedAt" DESC NULLS LAST, v.name ASC
+	`
+	QueryGetActiveMemberRole = `SELECT role FROM "CircleMember" WHERE "circleId" = $1 AND "userId" = $2 AND status = 'ACTIVE'`
+
+	// Share Link Queries
+	QueryRevokeShareLinks = `UPDATE "InviteShareLink" SET "revokedAt" = $2 WHERE "inviteId" = $1 AND "revokedAt" IS NULL`
+	QueryCreateShareLink  = `
+		INSERT INTO "InviteShareLink" (id, "inviteId", token, "createdById", "createdAt")
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	QueryGetActiveShareLink = `
+		SELECT * FROM "InviteShareLink"
+		WHERE "inviteId" = $1 AND "revokedAt" IS NULL
+		ORDER BY "createdAt" DESC
+		LIMIT 1
+	`
+	QueryGetPublicInvite = queryPublicInviteSelect + `
+		FROM "InviteShareLink" l
+		JOIN "Invite" i ON i.id = l."inviteId"
+		JOIN "User" sender ON sender.id = i."senderId"
+		WHERE l.token = $1 AND l."revokedAt" IS NULL
+	`
+	// Guests keep access only while the link they responded through is active
+	QueryGetGuestPublicInvite = queryPublicInviteSelect + `
+		FROM "GuestRSVP" g
+		JOIN "InviteShareLink" l ON l.id = g."shareLinkId" AND l."revokedAt" IS NULL
+		JOIN "Invite" i ON i.id = g."inviteId"
+		JOIN "User" sender ON sender.id = i."senderId"
+		WHERE g.id = $1
+	`
+	// Locks the invite so concurrent guest responses cannot exceed the cap
+	QueryLockInviteForGuests = `SELECT id FROM "Invite" WHERE id = $1 FOR NO KEY UPDATE`
+	QueryCountGuestRSVPs     = `SELECT count(*) FROM "GuestRSVP" WHERE "inviteId" = $1`
+	QueryCreateGuestRSVP     = `
+		INSERT INTO "GuestRSVP" (id, "inviteId", "shareLinkId", name, email, status, "guestCount", dietary, note, "createdAt", "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT ("inviteId", lower(email)) DO NOTHING
+	`
+	QueryGetGuestRSVP    = `SELECT * FROM "GuestRSVP" WHERE id = $1`
+	QueryUpdateGuestRSVP = `
+		UPDATE "GuestRSVP"
+		SET name = $2, status = $3, "guestCount" = $4, dietary = $5, note = $6, "updatedAt" = NOW()
+		WHERE id = $1
+	`
+
+	// Access Queries
+	QueryGetInviteAccess = `
+		SELECT
+			i.id AS "inviteId", i."senderId", i."circleId",
+			EXISTS (
+				SELECT 1 FROM "CircleMember" cm
+				WHERE cm."circleId" = i."circleId" AND cm."userId" = $2 AND cm.status = 'ACTIVE'
+			) AS "isActiveMember",
+			EXISTS (
+				SELECT 1 FROM "InviteInvitee" inv
+				WHERE inv."inviteId" = i.id AND inv."userId" = $2
+			) AS "isInvitee",
+			(
+				SELECT cm.role FROM "CircleMember" cm
+				WHERE cm."circleId" = i."circleId" AND cm."userId" = $2 AND cm.status = 'ACTIVE'
+			) AS "circleRole"
+		FROM "Invite" i
+		WHERE i.id = $1
+	`
+
 	// Feed Queries
 	QueryCreatePost = `
+		INSERT INTO "EventFeedItem" (id, "inviteId", "userId", content, type, "createdAt", "parentId")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	// Time-capsule messages are only visible once the vault is unlocked
+	queryFeedReleased = `(f.type <> 'CAPSULE' OR i."isVaultUnlocked")`
+	// Released capsule messages are shown together, at the moment the vault opened
+	queryFeedPostedAt   = `CASE WHEN f.type = 'CAPSULE' THEN COALESCE(i."vaultUnlockDate", f."createdAt") ELSE f."createdAt" END`
+	queryFeedReplyCount = `(SELECT COUNT(*) FROM "EventFeedItem" reply WHERE reply."parentId" = f.id)`
+	// Newest first, by posting time and then ID so the last row can serve as
+	// a cursor. $2 filters by type when set, $3/$4 is the cursor.
+	QueryListFeed = `
+		SELECT f.*, ` + queryFeedPostedAt + ` AS "postedAt", ` + queryFeedReplyCount + ` AS "replyCount",
+			u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
+		FROM "EventFeedItem" f
+		JOIN "Invite" i ON i.id = f."inviteId"
+		JOIN "User" u ON f."userId" = u.id
+		WHERE f."inviteId" = $1 AND f."parentId" IS NULL AND ` + queryFeedReleased + `
+		AND ($2::text = '' OR f.type = $2)
+		AND ($3::timestamp IS NULL OR (` + queryFeedPostedAt + `, f.id) < ($3, $4::text))
+		ORDER BY "postedAt" DESC, f.id DESC
+		LIMIT $5
+	`
+	// Inserts nothing once the vault is open, so no message can skip the seal
+	QueryCreateCapsuleMessage = `
 		INSERT INTO "EventFeedItem" (id, "inviteId", "userId", content, type, "createdAt")
-		VALUES ($1, $2, $3, $4, $5, $6)
+		SELECT $1, i.id, $3, $4, 'CAPSULE', $5
+		FROM "Invite" i
+		WHERE i.id = $2 AND NOT i."isVaultUnlocked"
 	`
-	QueryGetFeed = `
-        SELECT * FROM "EventFeedItem" 
-        WHERE "inviteId" = $1 
-        ORDER BY "createdAt" DESC
-    `
+	// $2 limits the list to one author; empty lists everyone's
+	QueryListCapsuleMessages = `
+		SELECT f.*, u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
+		FROM "EventFeedItem" f
+		JOIN "User" u ON f."userId" = u.id
+		WHERE f."inviteId" = $1 AND f.type = 'CAPSULE'
+		AND ($2::text = '' OR f."userId" = $2)
+		ORDER BY f."createdAt" ASC
+	`
+	// A post can only be replied or reacted to while it is visible
+	QueryGetFeedPost = `
+		SELECT f.* FROM "EventFeedItem" f
+		JOIN "Invite" i ON i.id = f."inviteId"
+		WHERE f.id = $1 AND f."inviteId" = $2 AND ` + queryFeedReleased + `
+	`
+	QueryListReplies = `
+		SELECT f.*, u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
+		FROM "EventFeedItem" f
+		JOIN "User" u ON f."userId" = u.id
+		WHERE f."parentId" = $1
+		ORDER BY f."createdAt" ASC, f.id ASC
+	`
+	QueryAddReaction = `
+		INSERT INTO "FeedReaction" (id, "feedItemId", "userId", emoji, "createdAt")
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ("feedItemId", "userId", emoji) DO NOTHING
+	`
+	QueryRemoveReaction = `DELETE FROM "FeedReaction" WHERE "feedItemId" = $1 AND "userId" = $2 AND emoji = $3`
+	// Reactions of several posts at once, in the order each emoji was first used.
+	// $2 is the viewer.
+	QueryListReactions = `
+		SELECT "feedItemId", emoji, COUNT(*) AS count, bool_or("userId" = $2) AS reacted
+		FROM "FeedReaction"
+		WHERE "feedItemId" = ANY($1)
+		GROUP BY "feedItemId", emoji
+		ORDER BY MIN("createdAt") ASC
+	`
+	// Everyone who can see the invite, the same people the access policy lets in
+	QueryListMentionCandidates = `
+		SELECT u.id AS "userId", u.name
+		FROM (
+			SELECT "senderId" AS id FROM "Invite" WHERE id = $1
+			UNION
+			SELECT cm."userId" FROM "Invite" i
+			JOIN "CircleMember" cm ON cm."circleId" = i."circleId" AND cm.status = 'ACTIVE'
+			WHERE i.id = $1
+			UNION
+			SELECT "userId" FROM "InviteInvitee" WHERE "inviteId" = $1
+		) people
+		JOIN "User" u ON u.id = people.id
+		WHERE u.name IS NOT NULL
+	`
+	QueryCreateMention = `
+		INSERT INTO "FeedMention" (id, "feedItemId", "userId", start, length, "createdAt", "notifiedAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	// Returns who was mentioned, and whether they were told, so an edit only
+	// notifies people it newly mentions
+	QueryDeleteMentions = `DELETE FROM "FeedMention" WHERE "feedItemId" = $1 RETURNING "userId", "notifiedAt"`
+	// Mentions of several posts at once, in reading order
+	QueryListMentions = `
+		SELECT "feedItemId", "userId", start, length
+		FROM "FeedMention"
+		WHERE "feedItemId" = ANY($1)
+		ORDER BY start ASC
+	`
+	// Posts mentioning $1 on invites they can still see, newest first; $2/$3
+	// is the cursor
+	QueryListMentionsOfUser = `
+		SELECT f.*, f."createdAt" AS "postedAt", ` + queryFeedReplyCount + ` AS "replyCount", i.title AS "inviteTitle",
+			u.id "user.id", u.name "user.name", u.email "user.email", u.image "user.image"
+		FROM "EventFeedItem" f
+		JOIN "Invite" i ON i.id = f."inviteId"
+		JOIN "User" u ON f."userId" = u.id
+		WHERE EXISTS (SELECT 1 FROM "FeedMention" m WHERE m."feedItemId" = f.id AND m."userId" = $1)
+		AND f."hiddenAt" IS NULL AND f."removedAt" IS NULL
+		AND ` + queryFeedReleased + `
+		AND ` + queryInviteVisible + `
+		AND ($2::timestamp IS NULL OR (f."createdAt", f.id) < ($2, $3::text))
+		ORDER BY f."createdAt" DESC, f.id DESC
+		LIMIT $4
+	`
+	// Claims every pending mention and returns one row per post and mentioned
+	// user. Claimed rows are skipped by concurrent runs; mentioning yourself,
+	// or in a post hidden since, sends nothing.
+	QueryClaimMentionNotifications = `
+		WITH claimed AS (
+			UPDATE "FeedMention" SET "notifiedAt" = $1
+			WHERE "notifiedAt" IS NULL
+			RETURNING "feedItemId", "userId"
+		)
+		SELECT DISTINCT i.id AS "inviteId", i.title, f.id AS "feedItemId", f.content, author.name AS "authorName",
+			u.id AS "userId", u.name, u.email
+		FROM claimed c
+		JOIN "EventFeedItem" f ON f.id = c."feedItemId"
+		JOIN "Invite" i ON i.id = f."inviteId"
+		JOIN "User" author ON author.id = f."userId"
+		JOIN "User" u ON u.id = c."userId"
+		WHERE c."userId" <> f."userId" AND f."hiddenAt" IS NULL
+	`
+	QueryReleaseMentionNotification = `UPDATE "FeedMention" SET "notifiedAt" = NULL WHERE "feedItemId" = $1 AND "userId" = $2`
+	// Keeps the current text as a revision before an edit replaces it
+	QueryCreatePostRevision = `
+		INSERT INTO "FeedPostRevision" (id, "feedItemId", content, "createdAt")
+		SELECT $1, f.id, f.content, $3 FROM "EventFeedItem" f
+		WHERE f.id = $2 AND f."userId" = $4 AND f."removedAt" IS NULL
+	`
+	QueryEditPost = `
+		UPDATE "EventFeedItem" SET content = $3, "editedAt" = $4
+		WHERE id = $1 AND "userId" = $2 AND "removedAt" IS NULL
+	`
+	QueryListPostRevisions = `SELECT * FROM "FeedPostRevision" WHERE "feedItemId" = $1 ORDER BY "createdAt" ASC, id ASC`
+	QueryHidePost          = `
+		UPDATE "EventFeedItem" SET "hiddenAt" = $2, "hiddenById" = $3, "moderationReason" = $4
+		WHERE id = $1 AND "hiddenAt" IS NULL AND "removedAt" IS NULL
+	`
+	QueryUnhidePost = `
+		UPDATE "EventFeedItem" SET "hiddenAt" = NULL, "hiddenById" = NULL, "moderationReason" = NULL
+		WHERE id = $1 AND "hiddenAt" IS NOT NULL AND "removedAt" IS NULL
+	`
+	// Leaves a tombstone: the row stays so replies keep their parent
+	QueryRemovePost = `
+		UPDATE "EventFeedItem"
+		SET content = '', "editedAt" = NULL, "hiddenAt" = NULL, "hiddenById" = NULL,
+			"removedAt" = $2, "removedById" = $3, "moderationReason" = $4
+		WHERE id = $1 AND "removedAt" IS NULL
+	`
+	QueryDeletePostRevisions  = `DELETE FROM "FeedPostRevision" WHERE "feedItemId" = $1`
+	QueryDeletePostReactions  = `DELETE FROM "FeedReaction" WHERE "feedItemId" = $1`
+	QueryGetVaultUnlocked     = `SELECT "isVaultUnlocked" FROM "Invite" WHERE id = $1`
+	QueryCountCapsuleMessages = `SELECT COUNT(*) FROM "EventFeedItem" WHERE "inviteId" = $1 AND type = 'CAPSULE'`
 
 	// Media Queries
 	QueryCreateMedia = `
 		INSERT INTO "MediaItem" (id, "inviteId", "userId", url, type, caption, "createdAt")
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
+	QueryGetMediaByURL = `
+		SELECT m.*, i."isVaultUnlocked"
+		FROM "MediaItem" m
+		JOIN "Invite" i ON i.id = m."inviteId"
+		WHERE m.url = $1
+	`
+
+	// Reminder Queries
+	// Recipients are YES/MAYBE RSVPs plus ACTIVE circle members and direct invitees who haven't answered.
+	// $1/$2 bound the event date window, $3 is the reminder offset in minutes.
+	QueryListDueReminders = `
+		SELECT
+			i.id AS "inviteId", i.title, i."eventDate", i.location,
+			u.id AS "userId", u.name, u.email, r.status AS "rsvpStatus"
+		FROM "Invite" i
+		CROSS JOIN LATERAL (
+			SELECT r."userId" FROM "RSVP" r
+			WHERE r."inviteId" = i.id AND r.status IN ('YES', 'MAYBE')
+			UNION
+			SELECT cm."userId" FROM "CircleMember" cm
+			WHERE cm."circleId" = i."circleId" AND cm.status = 'ACTIVE'
+			AND NOT EXISTS (SELECT 1 FROM "RSVP" r WHERE r."inviteId" = i.id AND r."userId" = cm."userId")
+			UNION
+			SELECT inv."userId" FROM "InviteInvitee" inv
+			WHERE inv."inviteId" = i.id AND inv."userId" IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM "RSVP" r WHERE r."inviteId" = i.id AND r."userId" = inv."userId")
+		) recipient
+		JOIN "User" u ON u.id = recipient."userId"
+		LEFT JOIN "RSVP" r ON r."inviteId" = i.id AND r."userId" = u.id
+		WHERE i."eventDate" > $1 AND i."eventDate" <= $2
+		AND NOT i."isDateTBD"
+		AND NOT EXISTS (
+			SELECT 1 FROM "ReminderDelivery" d
+			WHERE d."inviteId" = i.id AND d."userId" = u.id AND d."offsetMinutes" = $3
+		)
+		ORDER BY i."eventDate" ASC
+	`
+	QueryClaimReminder = `
+		INSERT INTO "ReminderDelivery" (id, "inviteId", "userId", "offsetMinutes", "sentAt")
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ("inviteId", "userId", "offsetMinutes") DO NOTHING
+	`
+	QueryReleaseReminder = `DELETE FROM "ReminderDelivery" WHERE "inviteId" = $1 AND "userId" = $2 AND "offsetMinutes" = $3`
+
+	// Vault Queries
+	// Locked vaults of events that have started and look due, at most $2 of
+	// them. The filter mirrors the vault package, which still has the final
+	// say; $3 is the default event length in seconds, $4 the default delay
+	// in hours. Manual vaults only open through the host.
+	QueryListVaultCandidates = `
+		SELECT i.*, COALESCE(c.contributors, 0) AS contributors
+		FROM "Invite" i
+		LEFT JOIN LATERAL (
+			SELECT COUNT(DISTINCT m."userId") AS contributors FROM "MediaItem" m
+			WHERE m."inviteId" = i.id AND i."vaultPolicy" = 'CONTRIBUTORS'
+		) c ON TRUE
+		WHERE NOT i."isVaultUnlocked" AND NOT i."isDateTBD"
+		AND i."vaultPolicy" <> 'MANUAL'
+		AND i."eventDate" <= $1
+		AND CASE i."vaultPolicy"
+			WHEN 'AFTER_EVENT' THEN COALESCE(i."endDate", i."eventDate" + make_interval(secs => $3)) <= $1
+			WHEN 'DELAY' THEN COALESCE(i."endDate", i."eventDate" + make_interval(secs => $3))
+				+ make_interval(hours => COALESCE(i."vaultUnlockAfterHours", $4)) <= $1
+			WHEN 'ON_DATE' THEN i."vaultUnlockAt" <= $1
+			WHEN 'CONTRIBUTORS' THEN c.contributors >= i."vaultMinContributors"
+			ELSE FALSE
+		END
+		ORDER BY i."eventDate" ASC
+		LIMIT $2
+	`
+	QueryListUnannouncedVaults = `SELECT id FROM "Invite" WHERE "isVaultUnlocked" AND "vaultNotifiedAt" IS NULL`
+	QueryMarkVaultAnnounced    = `
+		UPDATE "Invite" SET "vaultNotifiedAt" = $2
+		WHERE id = $1 AND "isVaultUnlocked" AND "vaultNotifiedAt" IS NULL
+	`
+	QueryClaimVaultDelivery = `
+		INSERT INTO "VaultDelivery" (id, "inviteId", "userId", "sentAt")
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ("inviteId", "userId") DO NOTHING
+	`
+	QueryReleaseVaultDelivery = `DELETE FROM "VaultDelivery" WHERE "inviteId" = $1 AND "userId" = $2`
+	// The host and everyone who said YES
+	QueryListVaultRecipients = `
+		SELECT i.id AS "inviteId", i.title, u.id AS "userId", u.name, u.email
+		FROM "Invite" i
+		JOIN "User" u ON u.id = i."senderId" OR u.id IN (
+			SELECT r."userId" FROM "RSVP" r WHERE r."inviteId" = i.id AND r.status = 'YES'
+		)
+		WHERE i.id = $1
+	`
+
+	// Recap Queries
+	QueryListPendingRecaps = `
+		SELECT i.id FROM "Invite" i
+		LEFT JOIN "InviteRecap" r ON r."inviteId" = i.id
+		WHERE i."isVaultUnlocked" AND (r."inviteId" IS NULL OR r."staleAt" IS NOT NULL)
+		ORDER BY i."vaultUnlockDate" ASC
+		LIMIT $1
+	`
+	// A stale recap is replaced; it stays stale if a post changed again after
+	// the new one was started
+	QueryCreateRecap = `
+		INSERT INTO "InviteRecap" ("inviteId", manifest, html, "generatedAt")
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ("inviteId") DO UPDATE SET
+			manifest = EXCLUDED.manifest,
+			html = EXCLUDED.html,
+			"generatedAt" = EXCLUDED."generatedAt",
+			"staleAt" = CASE WHEN "InviteRecap"."staleAt" > EXCLUDED."generatedAt" THEN "InviteRecap"."staleAt" END
+		WHERE "InviteRecap"."staleAt" IS NOT NULL
+	`
+	QueryGetRecap            = `SELECT * FROM "InviteRecap" WHERE "inviteId" = $1`
+	QueryGetRecapByToken     = `SELECT * FROM "InviteRecap" WHERE "publicToken" = $1`
+	QuerySetRecapPublicToken = `UPDATE "InviteRecap" SET "publicToken" = $2 WHERE "inviteId" = $1`
+	QueryIsRecapAttendee     = `
+		SELECT EXISTS(SELECT 1 FROM "CheckIn" WHERE "inviteId" = $1 AND "userId" = $2)
+			OR (NOT EXISTS(SELECT 1 FROM "CheckIn" WHERE "inviteId" = $1)
+				AND EXISTS(SELECT 1 FROM "RSVP" WHERE "inviteId" = $1 AND "userId" = $2 AND status = 'YES'))
+	`
 )
+
+// queryPublicInviteSelect lists the fields a share link exposes. Headcounts
+// include the extra guests brought along by both members and guests.
+const queryPublicInviteSelect = `
+		SELECT
+			i.id, i.title, i.description, i.location, i.map_link, i."eventDate", i."endDate",
+			sender.name AS "hostName",
+			l.id AS "shareLinkId",
+			(
+				COALESCE((SELECT sum("guestCount") FROM "RSVP" WHERE "inviteId" = i.id AND status = 'YES'), 0) +
+				COALESCE((SELECT sum("guestCount") FROM "GuestRSVP" WHERE "inviteId" = i.id AND status = 'YES'), 0)
+			)::int AS going,
+			(
+				COALESCE((SELECT sum("guestCount") FROM "RSVP" WHERE "inviteId" = i.id AND status = 'MAYBE'), 0) +
+				COALESCE((SELECT sum("guestCount") FROM "GuestRSVP" WHERE "inviteId" = i.id AND status = 'MAYBE'), 0)
+			)::int AS maybe`
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type recapRepository struct {
+	db *sqlx.DB
+}
+
+func NewRecapRepository(db *sqlx.DB) RecapRepository {
+	return &recapRepository{db: db}
+}
+
+// ListPendingRecaps returns the IDs of up to limit unlocked invites without a
+// recap yet or with a stale one, oldest unlock first
+func (r *recapRepository) ListPendingRecaps(ctx context.Context, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.SelectContext(ctx, &ids, QueryListPendingRecaps, limit)
+	return ids, err
+}
+
+// CreateRecap stores the recap, replacing a stale one. If the invite already
+// has an up-to-date recap it keeps it and returns false.
+func (r *recapRepository) CreateRecap(ctx context.Context, recap *models.InviteRecap) (bool, error) {
+	// Sent as text, the driver would encode []byte as bytea
+	res, err := r.db.ExecContext(ctx, QueryCreateRecap, recap.InviteID, string(recap.Manifest), recap.HTML, recap.GeneratedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *recapRepository) GetRecap(ctx context.Context, inviteID string) (*models.InviteRecap, error) {
+	var recap models.InviteRecap
+	err := r.db.GetContext(ctx, &recap, QueryGetRecap, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	return &recap, nil
+}
+
+// IsAttendee reports whether the user was checked in or, for events nobody
+// checked in at, said yes.
+func (r *recapRepository) IsAttendee(ctx context.Context, inviteID, userID string) (bool, error) {
+	var ok bool
+	err := r.db.GetContext(ctx, &ok, QueryIsRecapAttendee, inviteID, userID)
+	return ok, err
+}
+
+func (r *recapRepository) GetRecapByToken(ctx context.Context, token string) (*models.InviteRecap, error) {
+	var recap models.InviteRecap
+	err := r.db.GetContext(ctx, &recap, QueryGetRecapByToken, token)
+	if err != nil {
+		return nil, err
+	}
+	return &recap, nil
+}
+
+// SetPublicToken sets or, with a nil token, revokes the recap's public link.
+// It returns sql.ErrNoRows if the invite has no recap.
+func (r *recapRepository) SetPublicToken(ctx context.Context, inviteID string, token *string) error {
+	res, err := r.db.ExecContext(ctx, QuerySetRecapPublicToken, inviteID, token)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
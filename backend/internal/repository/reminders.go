@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type reminderRepository struct {
+	db *sqlx.DB
+}
+
+func NewReminderRepository(db *sqlx.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) ListDueReminders(ctx context.Context, windowStart, windowEnd time.Time, offsetMinutes int) ([]models.ReminderTarget, error) {
+	var targets []models.ReminderTarget
+	err := r.db.SelectContext(ctx, &targets, QueryListDueReminders, windowStart, windowEnd, offsetMinutes)
+	if targets == nil {
+		targets = []models.ReminderTarget{}
+	}
+	return targets, err
+}
+
+// ClaimReminder records the delivery before it is sent. The unique index on
+// (inviteId, userId, offsetMinutes) guarantees that only one caller, across
+// restarts and instances, gets true for a given reminder.
+func (r *reminderRepository) ClaimReminder(ctx context.Context, inviteID, userID string, offsetMinutes int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, QueryClaimReminder, utils.GenerateID("reminder"), inviteID, userID, offsetMinutes, time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseReminder forgets a claimed delivery so it is retried on the next run.
+func (r *reminderRepository) ReleaseReminder(ctx context.Context, inviteID, userID string, offsetMinutes int) error {
+	_, err := r.db.ExecContext(ctx, QueryReleaseReminder, inviteID, userID, offsetMinutes)
+	return err
+}
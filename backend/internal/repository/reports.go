@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type reportRepository struct {
+	db *sqlx.DB
+}
+
+func NewReportRepository(db *sqlx.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) GetInvite(ctx context.Context, inviteID string) (*models.Invite, error) {
+	var invite models.Invite
+	if err := r.db.GetContext(ctx, &invite, QueryGetInviteByID, inviteID); err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// ListReportGuests returns member and share-link guest responses together.
+func (r *reportRepository) ListReportGuests(ctx context.Context, inviteID string) ([]models.ReportGuest, error) {
+	var guests []models.ReportGuest
+	err := r.db.SelectContext(ctx, &guests, QueryListReportGuests, inviteID)
+	return guests, err
+}
@@ -3,21 +3,47 @@ package repository
 import "github.com/jmoiron/sqlx"
 
 type Repository struct {
-	Circles CircleRepository
-	Invites InviteRepository
-	Feed    FeedRepository
-	Auth    AuthRepository
-	User    UserRepository
-	Media   MediaRepository
+	Circles   CircleRepository
+	Invites   InviteRepository
+	Access    AccessRepository
+	Share     ShareRepository
+	Polls     PollRepository
+	Signups   SignupRepository
+	Expenses  ExpenseRepository
+	Rides     RideRepository
+	CheckIns  CheckInRepository
+	Templates TemplateRepository
+	Venues    VenueRepository
+	Reports   ReportRepository
+	Feed      FeedRepository
+	Auth      AuthRepository
+	User      UserRepository
+	Media     MediaRepository
+	Reminders ReminderRepository
+	Vaults    VaultRepository
+	Recaps    RecapRepository
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
 	return &Repository{
-		Circles: NewCircleRepository(db),
-		Invites: NewInviteRepository(db),
-		Feed:    NewFeedRepository(db),
-		Auth:    NewAuthRepository(db),
-		User:    NewUserRepository(db),
-		Media:   NewMediaRepository(db),
+		Circles:   NewCircleRepository(db),
+		Invites:   NewInviteRepository(db),
+		Access:    NewAccessRepository(db),
+		Share:     NewShareRepository(db),
+		Polls:     NewPollRepository(db),
+		Signups:   NewSignupRepository(db),
+		Expenses:  NewExpenseRepository(db),
+		Rides:     NewRideRepository(db),
+		CheckIns:  NewCheckInRepository(db),
+		Templates: NewTemplateRepository(db),
+		Venues:    NewVenueRepository(db),
+		Reports:   NewReportRepository(db),
+		Feed:      NewFeedRepository(db),
+		Auth:      NewAuthRepository(db),
+		User:      NewUserRepository(db),
+		Media:     NewMediaRepository(db),
+		Reminders: NewReminderRepository(db),
+		Vaults:    NewVaultRepository(db),
+		Recaps:    NewRecapRepository(db),
 	}
 }
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrDriverCannotRide is returned by JoinOffer when the rider offers a ride themselves
+	ErrDriverCannotRide = errors.New("drivers cannot join another ride")
+	// ErrRiderCannotDrive is returned by UpsertOffer when the driver has joined someone else's ride
+	ErrRiderCannotDrive = errors.New("riders cannot offer a ride")
+)
+
+type rideRepository struct {
+	db *sqlx.DB
+}
+
+func NewRideRepository(db *sqlx.DB) RideRepository {
+	return &rideRepository{db: db}
+}
+
+func (r *rideRepository) GetRSVPStatus(ctx context.Context, inviteID, userID string) (string, error) {
+	var status string
+	err := r.db.GetContext(ctx, &status, QueryGetRSVPStatus, inviteID, userID)
+	return status, err
+}
+
+// UpsertOffer creates or updates the driver's offer for an invite. It returns
+// false when the new seat count is lower than the riders already on board, and
+// ErrRiderCannotDrive while the driver has a seat in another ride.
+func (r *rideRepository) UpsertOffer(ctx context.Context, offer *models.RideOffer) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var existingID string
+	err = tx.GetContext(ctx, &existingID, QueryLockRideOfferByDriver, offer.InviteID, offer.DriverID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return false, err
+	}
+	if existingID != "" {
+		offer.ID = existingID
+
+		var riders int
+		if err := tx.GetContext(ctx, &riders, QueryCountRideRiders, existingID, offer.DriverID); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+		if riders > offer.Seats {
+			tx.Rollback()
+			return false, nil
+		}
+	} else {
+		var joined int
+		if err := tx.GetContext(ctx, &joined, QueryCountJoinedRides, offer.InviteID, offer.DriverID); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+		if joined > 0 {
+			tx.Rollback()
+			return false, ErrRiderCannotDrive
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, QueryUpsertRideOffer, offer.ID, offer.InviteID, offer.DriverID, offer.Seats, offer.DepartureArea, offer.DepartureTime, offer.Notes, offer.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *rideRepository) GetOffer(ctx context.Context, inviteID, offerID string) (*models.RideOffer, error) {
+	var offer models.RideOffer
+	if err := r.db.GetContext(ctx, &offer, QueryGetRideOffer, offerID, inviteID); err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// DeleteOffer removes the driver's offer; riders on it go back to looking.
+func (r *rideRepository) DeleteOffer(ctx context.Context, inviteID, offerID, driverID string) error {
+	res, err := r.db.ExecContext(ctx, QueryDeleteRideOffer, offerID, inviteID, driverID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *rideRepository) UpsertRequest(ctx context.Context, req *models.RideRequest) error {
+	_, err := r.db.ExecContext(ctx, QueryUpsertRideRequest, req.ID, req.InviteID, req.RiderID, req.PickupArea, req.Notes, req.UpdatedAt)
+	return err
+}
+
+func (r *rideRepository) DeleteRequest(ctx context.Context, inviteID, riderID string) error {
+	_, err := r.db.ExecContext(ctx, QueryDeleteRideRequest, inviteID, riderID)
+	return err
+}
+
+// JoinOffer puts the rider on an offer, creating their request if needed.
+// The offer row is locked while seats are counted so it can't be overbooked;
+// false means the ride is full. Drivers get ErrDriverCannotRide.
+func (r *rideRepository) JoinOffer(ctx context.Context, req *models.RideRequest) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var ownOfferID string
+	err = tx.GetContext(ctx, &ownOfferID, QueryLockRideOfferByDriver, req.InviteID, req.RiderID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return false, err
+	}
+	if ownOfferID != "" {
+		tx.Rollback()
+		return false, ErrDriverCannotRide
+	}
+
+	var seats int
+	if err := tx.GetContext(ctx, &seats, QueryLockRideOffer, *req.OfferID, req.InviteID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	var riders int
+	if err := tx.GetContext(ctx, &riders, QueryCountRideRiders, *req.OfferID, req.RiderID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if riders >= seats {
+		tx.Rollback()
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, QueryJoinRideOffer, req.ID, req.InviteID, req.RiderID, req.OfferID, req.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *rideRepository) LeaveOffer(ctx context.Context, inviteID, offerID, riderID string) error {
+	_, err := r.db.ExecContext(ctx, QueryLeaveRideOffer, inviteID, riderID, offerID)
+	return err
+}
+
+func (r *rideRepository) ListOffers(ctx context.Context, inviteID string) ([]models.RideOfferWithDriver, error) {
+	type OfferRow struct {
+		models.RideOffer
+		DriverID    string  `db:"driver.id"`
+		DriverName  *string `db:"driver.name"`
+		DriverEmail *string `db:"driver.email"`
+		DriverImage *string `db:"driver.image"`
+	}
+	var rows []OfferRow
+	if err := r.db.SelectContext(ctx, &rows, QueryListRideOffers, inviteID); err != nil {
+		return nil, err
+	}
+
+	offers := make([]models.RideOfferWithDriver, len(rows))
+	for i, row := range rows {
+		offers[i] = models.RideOfferWithDriver{
+			RideOffer: row.RideOffer,
+			Driver: models.User{
+				ID:    row.DriverID,
+				Name:  row.DriverName,
+				Email: row.DriverEmail,
+				Image: row.DriverImage,
+			},
+		}
+	}
+	return offers, nil
+}
+
+func (r *rideRepository) ListRequests(ctx context.Context, inviteID string) ([]models.RideRequestWithRider, error) {
+	type RequestRow struct {
+		models.RideRequest
+		RiderID    string  `db:"rider.id"`
+		RiderName  *string `db:"rider.name"`
+		RiderEmail *string `db:"rider.email"`
+		RiderImage *string `db:"rider.image"`
+	}
+	var rows []RequestRow
+	if err := r.db.SelectContext(ctx, &rows, QueryListRideRequests, inviteID); err != nil {
+		return nil, err
+	}
+
+	requests := make([]models.RideRequestWithRider, len(rows))
+	for i, row := range rows {
+		requests[i] = models.RideRequestWithRider{
+			RideRequest: row.RideRequest,
+			Rider: models.User{
+				ID:    row.RiderID,
+				Name:  row.RiderName,
+				Email: row.RiderEmail,
+				Image: row.RiderImage,
+			},
+		}
+	}
+	return requests, nil
+}
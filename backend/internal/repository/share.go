@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrGuestListFull is returned by CreateGuestRSVP when the invite already
+// has as many guest responses as it takes
+var ErrGuestListFull = errors.New("guest list is full")
+
+type shareRepository struct {
+	db *sqlx.DB
+}
+
+func NewShareRepository(db *sqlx.DB) ShareRepository {
+	return &shareRepository{db: db}
+}
+
+// CreateShareLink revokes any active link for the invite and stores the new one,
+// so there is at most one working public link per invite.
+func (r *shareRepository) CreateShareLink(ctx context.Context, link *models.InviteShareLink) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryRevokeShareLinks, link.InviteID, link.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryCreateShareLink, link.ID, link.InviteID, link.Token, link.CreatedByID, link.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *shareRepository) GetActiveShareLink(ctx context.Context, inviteID string) (*models.InviteShareLink, error) {
+	var link models.InviteShareLink
+	if err := r.db.GetContext(ctx, &link, QueryGetActiveShareLink, inviteID); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *shareRepository) RevokeShareLinks(ctx context.Context, inviteID string) error {
+	_, err := r.db.ExecContext(ctx, QueryRevokeShareLinks, inviteID, time.Now())
+	return err
+}
+
+func (r *shareRepository) GetPublicInvite(ctx context.Context, token string) (*models.PublicInviteView, error) {
+	var view models.PublicInviteView
+	if err := r.db.GetContext(ctx, &view, QueryGetPublicInvite, token); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// GetGuestPublicInvite returns the invite a guest responded to, or
+// sql.ErrNoRows once the share link they used has been revoked.
+func (r *shareRepository) GetGuestPublicInvite(ctx context.Context, rsvpID string) (*models.PublicInviteView, error) {
+	var view models.PublicInviteView
+	if err := r.db.GetContext(ctx, &view, QueryGetGuestPublicInvite, rsvpID); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// CreateGuestRSVP returns false if this email has already responded to the
+// invite, and ErrGuestListFull once it has maxGuests guest responses.
+func (r *shareRepository) CreateGuestRSVP(ctx context.Context, rsvp *models.GuestRSVP, maxGuests int) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var inviteID string
+	if err := tx.GetContext(ctx, &inviteID, QueryLockInviteForGuests, rsvp.InviteID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	var count int
+	if err := tx.GetContext(ctx, &count, QueryCountGuestRSVPs, rsvp.InviteID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if count >= maxGuests {
+		tx.Rollback()
+		return false, ErrGuestListFull
+	}
+
+	res, err := tx.ExecContext(ctx, QueryCreateGuestRSVP, rsvp.ID, rsvp.InviteID, rsvp.ShareLinkID, rsvp.Name, rsvp.Email, rsvp.Status, rsvp.GuestCount, rsvp.Dietary, rsvp.Note, rsvp.CreatedAt, rsvp.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return n == 1, tx.Commit()
+}
+
+func (r *shareRepository) GetGuestRSVP(ctx context.Context, id string) (*models.GuestRSVP, error) {
+	var rsvp models.GuestRSVP
+	if err := r.db.GetContext(ctx, &rsvp, QueryGetGuestRSVP, id); err != nil {
+		return nil, err
+	}
+	return &rsvp, nil
+}
+
+func (r *shareRepository) UpdateGuestRSVP(ctx context.Context, rsvp *models.GuestRSVP) error {
+	_, err := r.db.ExecContext(ctx, QueryUpdateGuestRSVP, rsvp.ID, rsvp.Name, rsvp.Status, rsvp.GuestCount, rsvp.Dietary, rsvp.Note)
+	return err
+}
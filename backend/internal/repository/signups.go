@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type signupRepository struct {
+	db *sqlx.DB
+}
+
+func NewSignupRepository(db *sqlx.DB) SignupRepository {
+	return &signupRepository{db: db}
+}
+
+func (r *signupRepository) CreateSheet(ctx context.Context, sheet *models.SignupSheet, items []models.SignupItem) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QueryCreateSignupSheet, sheet.ID, sheet.InviteID, sheet.Title, sheet.Description, sheet.CreatedByID, sheet.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, item := range items {
+		_, err = tx.ExecContext(ctx, QueryCreateSignupItem, item.ID, item.SheetID, item.Name, item.Quantity, item.Position, item.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *signupRepository) GetSheet(ctx context.Context, inviteID, sheetID string) (*models.SignupSheet, error) {
+	var sheet models.SignupSheet
+	if err := r.db.GetContext(ctx, &sheet, QueryGetSignupSheet, sheetID, inviteID); err != nil {
+		return nil, err
+	}
+	return &sheet, nil
+}
+
+// AddItems appends items after the sheet's current last position.
+func (r *signupRepository) AddItems(ctx context.Context, sheetID string, items []models.SignupItem) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var position int
+	if err := tx.GetContext(ctx, &position, QueryNextSignupItemPosition, sheetID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i, item := range items {
+		_, err = tx.ExecContext(ctx, QueryCreateSignupItem, item.ID, sheetID, item.Name, item.Quantity, position+i, item.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *signupRepository) DeleteSheet(ctx context.Context, inviteID, sheetID string) error {
+	res, err := r.db.ExecContext(ctx, QueryDeleteSignupSheet, sheetID, inviteID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *signupRepository) ListSheets(ctx context.Context, inviteID string) ([]models.SignupSheetWithItems, error) {
+	return listSignupSheets(ctx, r.db, inviteID)
+}
+
+// listSignupSheets loads every sheet of an invite with its items and claims.
+func listSignupSheets(ctx context.Context, db *sqlx.DB, inviteID string) ([]models.SignupSheetWithItems, error) {
+	var sheets []models.SignupSheet
+	if err := db.SelectContext(ctx, &sheets, QueryListSignupSheets, inviteID); err != nil {
+		return nil, err
+	}
+
+	var items []models.SignupItem
+	if err := db.SelectContext(ctx, &items, QueryListSignupItems, inviteID); err != nil {
+		return nil, err
+	}
+
+	type ClaimRow struct {
+		models.SignupClaim
+		UserID    string  `db:"user.id"`
+		UserName  *string `db:"user.name"`
+		UserEmail *string `db:"user.email"`
+		UserImage *string `db:"user.image"`
+	}
+	var claimRows []ClaimRow
+	if err := db.SelectContext(ctx, &claimRows, QueryListSignupClaims, inviteID); err != nil {
+		return nil, err
+	}
+
+	claimsByItem := make(map[string][]models.SignupClaimWithUser)
+	for _, row := range claimRows {
+		claimsByItem[row.ItemID] = append(claimsByItem[row.ItemID], models.SignupClaimWithUser{
+			SignupClaim: row.SignupClaim,
+			User: models.User{
+				ID:    row.UserID,
+				Name:  row.UserName,
+				Email: row.UserEmail,
+				Image: row.UserImage,
+			},
+		})
+	}
+
+	itemsBySheet := make(map[string][]models.SignupItemWithClaims)
+	for _, item := range items {
+		withClaims := models.SignupItemWithClaims{
+			SignupItem: item,
+			Claims:     claimsByItem[item.ID],
+		}
+		if withClaims.Claims == nil {
+			withClaims.Claims = []models.SignupClaimWithUser{}
+		}
+		for _, claim := range withClaims.Claims {
+			withClaims.Claimed += claim.Quantity
+		}
+		itemsBySheet[item.SheetID] = append(itemsBySheet[item.SheetID], withClaims)
+	}
+
+	result := make([]models.SignupSheetWithItems, len(sheets))
+	for i, sheet := range sheets {
+		result[i] = models.SignupSheetWithItems{SignupSheet: sheet, Items: itemsBySheet[sheet.ID]}
+		if result[i].Items == nil {
+			result[i].Items = []models.SignupItemWithClaims{}
+		}
+	}
+	return result, nil
+}
+
+// ClaimItem sets the user's claim on an item to claim.Quantity. It returns
+// false when the item does not have that many portions left; the item row is
+// locked so concurrent claims cannot overfill it.
+func (r *signupRepository) ClaimItem(ctx context.Context, inviteID string, claim *models.SignupClaim) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var quantity int
+	if err := tx.GetContext(ctx, &quantity, QueryLockSignupItem, claim.ItemID, inviteID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	var claimedByOthers int
+	if err := tx.GetContext(ctx, &claimedByOthers, QueryCountOtherSignupClaims, claim.ItemID, claim.UserID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if claimedByOthers+claim.Quantity > quantity {
+		tx.Rollback()
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, QueryUpsertSignupClaim, claim.ID, claim.ItemID, claim.UserID, claim.Quantity, claim.UpdatedAt)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *signupRepository) UnclaimItem(ctx context.Context, inviteID, itemID, userID string) error {
+	_, err := r.db.ExecContext(ctx, QueryDeleteSignupClaim, itemID, userID, inviteID)
+	return err
+}
+
+func (r *signupRepository) GetRSVPStatus(ctx context.Context, inviteID, userID string) (string, error) {
+	var status string
+	err := r.db.GetContext(ctx, &status, QueryGetRSVPStatus, inviteID, userID)
+	return status, err
+}
+
+func (r *signupRepository) ListSummaryTargets(ctx context.Context, windowStart, windowEnd time.Time) ([]models.SignupSummaryTarget, error) {
+	var targets []models.SignupSummaryTarget
+	err := r.db.SelectContext(ctx, &targets, QueryListSignupSummaryTargets, windowStart, windowEnd)
+	if targets == nil {
+		targets = []models.SignupSummaryTarget{}
+	}
+	return targets, err
+}
+
+func (r *signupRepository) ListUnclaimedItems(ctx context.Context, sheetID string) ([]models.UnclaimedSignupItem, error) {
+	var items []models.UnclaimedSignupItem
+	err := r.db.SelectContext(ctx, &items, QueryListUnclaimedSignupItems, sheetID)
+	return items, err
+}
+
+// PostSummary marks the sheet's summary as posted and, when post is not nil,
+// adds it to the feed in the same transaction. It returns false if another
+// run already posted it.
+func (r *signupRepository) PostSummary(ctx context.Context, sheetID string, post *models.EventFeedItem) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := tx.ExecContext(ctx, QueryMarkSignupSummaryPosted, sheetID, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return false, nil
+	}
+
+	if post != nil {
+		_, err = tx.ExecContext(ctx, QueryCreatePost, post.ID, post.InviteID, post.UserID, post.Content, post.Type, post.CreatedAt, post.ParentID)
+		if err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type templateRepository struct {
+	db *sqlx.DB
+}
+
+func NewTemplateRepository(db *sqlx.DB) TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+func (r *templateRepository) CreateTemplate(ctx context.Context, t *models.InviteTemplate) error {
+	_, err := r.db.ExecContext(ctx, QueryCreateTemplate, t.ID, t.Name, t.OwnerID, t.CircleID, t.Title, t.Description, t.Location, t.MapLink,
+		t.PlaceName, t.AddressLine, t.City, t.Region, t.PostalCode, t.Country, t.Latitude, t.Longitude, t.CreatedAt)
+	return err
+}
+
+func (r *templateRepository) ListTemplates(ctx context.Context, userID string) ([]models.InviteTemplate, error) {
+	var templates []models.InviteTemplate
+	err := r.db.SelectContext(ctx, &templates, QueryListTemplates, userID)
+	if templates == nil {
+		templates = []models.InviteTemplate{}
+	}
+	return templates, err
+}
+
+// GetTemplate returns the template only if userID may use it.
+func (r *templateRepository) GetTemplate(ctx context.Context, templateID, userID string) (*models.InviteTemplate, error) {
+	var t models.InviteTemplate
+	if err := r.db.GetContext(ctx, &t, QueryGetTemplate, userID, templateID); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *templateRepository) DeleteTemplate(ctx context.Context, templateID, ownerID string) error {
+	res, err := r.db.ExecContext(ctx, QueryDeleteTemplate, templateID, ownerID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *templateRepository) IsCircleMember(ctx context.Context, circleID, userID string) (bool, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, QueryIsMember, circleID, userID)
+	return count > 0, err
+}
@@ -57,7 +57,8 @@ func (r *userRepository) GetUserStats(ctx context.Context, userID string) (*mode
 		return nil, err
 	}
 	
-	// Get RSVP statistics (both attended events and total responses) in one query
+	// Get RSVP statistics (both attended events and total responses) in one query.
+	// Events with check-ins count real attendance, the rest fall back to YES RSVPs.
 	var rsvpStats struct {
 		EventsAttended int `db:"events_attended"`
 		TotalResponses int `db:"total_responses"`
@@ -65,7 +66,13 @@ func (r *userRepository) GetUserStats(ctx context.Context, userID string) (*mode
 	
 	err = r.DB.GetContext(ctx, &rsvpStats,
 		`SELECT 
-			COUNT(CASE WHEN status = 'YES' THEN 1 END) as events_attended,
+			(SELECT COUNT(*) FROM (
+				SELECT c."inviteId" FROM "CheckIn" c WHERE c."userId" = $1
+				UNION
+				SELECT r."inviteId" FROM "RSVP" r
+				WHERE r."userId" = $1 AND r.status = 'YES'
+				AND NOT EXISTS (SELECT 1 FROM "CheckIn" c WHERE c."inviteId" = r."inviteId")
+			) attended) as events_attended,
 			COUNT(*) as total_responses
 		 FROM "RSVP" WHERE "userId" = $1`, userID)
 	if err != nil && err != sql.ErrNoRows {
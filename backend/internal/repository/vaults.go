@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/utils"
+	"privo-club-backend/internal/vault"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type vaultRepository struct {
+	db *sqlx.DB
+}
+
+func NewVaultRepository(db *sqlx.DB) VaultRepository {
+	return &vaultRepository{db: db}
+}
+
+// ListVaultCandidates returns up to limit locked vaults that look due at now,
+// oldest events first.
+func (r *vaultRepository) ListVaultCandidates(ctx context.Context, now time.Time, limit int) ([]models.VaultCandidate, error) {
+	var candidates []models.VaultCandidate
+	err := r.db.SelectContext(ctx, &candidates, QueryListVaultCandidates, now, limit, models.DefaultEventDuration.Seconds(), vault.DefaultDelayHours)
+	return candidates, err
+}
+
+// UnlockVault opens a locked vault. It returns sql.ErrNoRows when the vault
+// was already open, e.g. because another instance got there first.
+func (r *vaultRepository) UnlockVault(ctx context.Context, inviteID string, unlockDate time.Time) error {
+	res, err := r.db.ExecContext(ctx, QueryUnlockVault, inviteID, unlockDate)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListUnannouncedVaults returns the IDs of open vaults whose attendees have
+// not been notified yet, whether the worker or the host opened them.
+func (r *vaultRepository) ListUnannouncedVaults(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.SelectContext(ctx, &ids, QueryListUnannouncedVaults)
+	return ids, err
+}
+
+// ClaimVaultDelivery records that userID is being told the vault opened. Only
+// one caller, across instances, gets true for a given person and vault.
+func (r *vaultRepository) ClaimVaultDelivery(ctx context.Context, inviteID, userID string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, QueryClaimVaultDelivery, utils.GenerateID("vaultdelivery"), inviteID, userID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseVaultDelivery forgets a claimed delivery so it is retried on the next run.
+func (r *vaultRepository) ReleaseVaultDelivery(ctx context.Context, inviteID, userID string) error {
+	_, err := r.db.ExecContext(ctx, QueryReleaseVaultDelivery, inviteID, userID)
+	return err
+}
+
+// MarkVaultAnnounced stops the vault from being listed by ListUnannouncedVaults.
+func (r *vaultRepository) MarkVaultAnnounced(ctx context.Context, inviteID string, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, QueryMarkVaultAnnounced, inviteID, now)
+	return err
+}
+
+func (r *vaultRepository) ListVaultRecipients(ctx context.Context, inviteID string) ([]models.VaultRecipient, error) {
+	var recipients []models.VaultRecipient
+	err := r.db.SelectContext(ctx, &recipients, QueryListVaultRecipients, inviteID)
+	return recipients, err
+}
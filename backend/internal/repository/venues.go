@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type venueRepository struct {
+	db *sqlx.DB
+}
+
+func NewVenueRepository(db *sqlx.DB) VenueRepository {
+	return &venueRepository{db: db}
+}
+
+func (r *venueRepository) CreateVenue(ctx context.Context, v *models.Venue) error {
+	_, err := r.db.ExecContext(ctx, QueryCreateVenue,
+		v.ID, v.CircleID, v.Name, v.Address, v.MapLink, v.Notes, v.Capacity, v.Latitude, v.Longitude, v.CreatedByID, v.CreatedAt)
+	return err
+}
+
+func (r *venueRepository) GetVenue(ctx context.Context, circleID, venueID string) (*models.Venue, error) {
+	var v models.Venue
+	if err := r.db.GetContext(ctx, &v, QueryGetVenue, venueID, circleID); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *venueRepository) UpdateVenue(ctx context.Context, v *models.Venue) error {
+	res, err := r.db.ExecContext(ctx, QueryUpdateVenue,
+		v.ID, v.CircleID, v.Name, v.Address, v.MapLink, v.Notes, v.Capacity, v.Latitude, v.Longitude)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteVenue removes the venue; invites that used it keep their copied location.
+func (r *venueRepository) DeleteVenue(ctx context.Context, circleID, venueID string) error {
+	res, err := r.db.ExecContext(ctx, QueryDeleteVenue, venueID, circleID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *venueRepository) ListVenues(ctx context.Context, circleID, search string) ([]models.VenueWithUsage, error) {
+	var venues []models.VenueWithUsage
+	err := r.db.SelectContext(ctx, &venues, QueryListVenues, circleID, search)
+	if venues == nil {
+		venues = []models.VenueWithUsage{}
+	}
+	return venues, err
+}
+
+// GetMemberRole returns sql.ErrNoRows unless userID is an active member.
+func (r *venueRepository) GetMemberRole(ctx context.Context, circleID, userID string) (string, error) {
+	var role string
+	err := r.db.GetContext(ctx, &role, QueryGetActiveMemberRole, circleID, userID)
+	return role, err
+}
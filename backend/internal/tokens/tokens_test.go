@@ -0,0 +1,51 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	signer := NewSigner("secret")
+	token := signer.Sign("guest-rsvp", "guest-abc123")
+
+	payload, ok := signer.Verify("guest-rsvp", token)
+	assert.True(t, ok)
+	assert.Equal(t, "guest-abc123", payload)
+
+	// Wrong purpose
+	_, ok = signer.Verify("check-in", token)
+	assert.False(t, ok)
+
+	// Wrong secret
+	_, ok = NewSigner("other").Verify("guest-rsvp", token)
+	assert.False(t, ok)
+
+	// Tampered payload
+	_, ok = signer.Verify("guest-rsvp", "Z3Vlc3QteHl6"+token[len("Z3Vlc3QteHl6"):])
+	assert.False(t, ok)
+
+	// Malformed
+	_, ok = signer.Verify("guest-rsvp", "no-signature")
+	assert.False(t, ok)
+}
+
+func TestSignUntil(t *testing.T) {
+	signer := NewSigner("secret")
+	expiresAt := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	token := signer.SignUntil("check-in", "guest-abc123", expiresAt)
+
+	payload, ok := signer.VerifyAt("check-in", token, expiresAt)
+	assert.True(t, ok)
+	assert.Equal(t, "guest-abc123", payload)
+
+	// Expired
+	_, ok = signer.VerifyAt("check-in", token, expiresAt.Add(time.Second))
+	assert.False(t, ok)
+
+	// Tokens without an expiry are not accepted
+	_, ok = signer.VerifyAt("check-in", signer.Sign("check-in", "guest-abc123"), expiresAt)
+	assert.False(t, ok)
+}
@@ -0,0 +1,126 @@
+// Package vault decides when an invite's Memory Vault opens.
+package vault
+
+import (
+	"errors"
+	"time"
+
+	"privo-club-backend/internal/models"
+)
+
+// Unlock policies
+const (
+	AfterEvent   = "AFTER_EVENT"  // As soon as the event ends
+	Delay        = "DELAY"        // UnlockAfterHours after the event ends
+	OnDate       = "ON_DATE"      // At UnlockAt
+	Manual       = "MANUAL"       // Only when the host opens it
+	Contributors = "CONTRIBUTORS" // Once MinContributors people have added media
+)
+
+const (
+	DefaultDelayHours = 24
+	maxDelayHours     = 24 * 365
+	maxContributors   = 1000
+)
+
+// Normalize fills in the default policy and drops settings the chosen policy
+// does not use, so stale values never end up in the database.
+func Normalize(p models.VaultPolicy) models.VaultPolicy {
+	out := models.VaultPolicy{UnlockPolicy: p.UnlockPolicy}
+	switch p.UnlockPolicy {
+	case "":
+		out.UnlockPolicy = Delay
+		hours := DefaultDelayHours
+		out.UnlockAfterHours = &hours
+	case Delay:
+		out.UnlockAfterHours = p.UnlockAfterHours
+	case OnDate:
+		out.UnlockAt = p.UnlockAt
+	case Contributors:
+		out.MinContributors = p.MinContributors
+	}
+	return out
+}
+
+// Validate checks a normalized policy against the invite's start
+func Validate(p models.VaultPolicy, eventDate time.Time) error {
+	switch p.UnlockPolicy {
+	case AfterEvent, Manual:
+		return nil
+	case Delay:
+		if p.UnlockAfterHours == nil || *p.UnlockAfterHours < 1 || *p.UnlockAfterHours > maxDelayHours {
+			return errors.New("vaultUnlockAfterHours must be between 1 and 8760")
+		}
+		return nil
+	case OnDate:
+		if p.UnlockAt == nil {
+			return errors.New("vaultUnlockAt is required")
+		}
+		if p.UnlockAt.Before(eventDate) {
+			return errors.New("the vault cannot unlock before the event starts")
+		}
+		return nil
+	case Contributors:
+		if p.MinContributors == nil || *p.MinContributors < 1 || *p.MinContributors > maxContributors {
+			return errors.New("vaultMinContributors must be between 1 and 1000")
+		}
+		return nil
+	default:
+		return errors.New("vaultPolicy must be AFTER_EVENT, DELAY, ON_DATE, MANUAL or CONTRIBUTORS")
+	}
+}
+
+// UnlockTime returns when a time-based policy opens the vault. It reports
+// false for manual and contributor policies and for invites whose date is
+// still being polled.
+func UnlockTime(invite models.Invite) (time.Time, bool) {
+	if invite.IsDateTBD {
+		return time.Time{}, false
+	}
+	switch invite.UnlockPolicy {
+	case AfterEvent:
+		return invite.Ends(), true
+	case Delay:
+		hours := DefaultDelayHours
+		if invite.UnlockAfterHours != nil {
+			hours = *invite.UnlockAfterHours
+		}
+		return invite.Ends().Add(time.Duration(hours) * time.Hour), true
+	case OnDate:
+		if invite.UnlockAt == nil {
+			return time.Time{}, false
+		}
+		return *invite.UnlockAt, true
+	}
+	return time.Time{}, false
+}
+
+// ShouldUnlock reports whether a locked vault opens at now and the unlock date
+// to record. contributors is the number of distinct people who added media and
+// only matters for the CONTRIBUTORS policy.
+func ShouldUnlock(invite models.Invite, contributors int, now time.Time) (time.Time, bool) {
+	if invite.IsVaultUnlocked || invite.IsDateTBD {
+		return time.Time{}, false
+	}
+	if invite.UnlockPolicy == Contributors {
+		if invite.MinContributors != nil && contributors >= *invite.MinContributors {
+			return now, true
+		}
+		return time.Time{}, false
+	}
+	unlockAt, ok := UnlockTime(invite)
+	if !ok || now.Before(unlockAt) {
+		return time.Time{}, false
+	}
+	return unlockAt, true
+}
+
+// Shift moves an ON_DATE unlock along with an event that moved by d, used
+// when an invite is duplicated to a new date.
+func Shift(p models.VaultPolicy, d time.Duration) models.VaultPolicy {
+	if p.UnlockAt != nil {
+		at := p.UnlockAt.Add(d)
+		p.UnlockAt = &at
+	}
+	return p
+}
@@ -0,0 +1,136 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestShouldUnlock(t *testing.T) {
+	start := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	end := start.Add(5 * time.Hour)
+	onDate := start.Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		invite       models.Invite
+		contributors int
+		now          time.Time
+		unlockDate   time.Time
+		unlocked     bool
+	}{
+		{
+			name:     "After event waits for the default duration",
+			invite:   models.Invite{EventDate: start, VaultPolicy: models.VaultPolicy{UnlockPolicy: AfterEvent}},
+			now:      start.Add(2 * time.Hour),
+			unlocked: false,
+		},
+		{
+			name:       "After event uses the end date",
+			invite:     models.Invite{EventDate: start, EndDate: &end, VaultPolicy: models.VaultPolicy{UnlockPolicy: AfterEvent}},
+			now:        end.Add(time.Minute),
+			unlockDate: end,
+			unlocked:   true,
+		},
+		{
+			name:       "Delay counts from the end",
+			invite:     models.Invite{EventDate: start, VaultPolicy: models.VaultPolicy{UnlockPolicy: Delay, UnlockAfterHours: intPtr(48)}},
+			now:        start.Add(52 * time.Hour),
+			unlockDate: start.Add(models.DefaultEventDuration + 48*time.Hour),
+			unlocked:   true,
+		},
+		{
+			name:     "Delay not reached",
+			invite:   models.Invite{EventDate: start, VaultPolicy: models.VaultPolicy{UnlockPolicy: Delay, UnlockAfterHours: intPtr(48)}},
+			now:      start.Add(50 * time.Hour),
+			unlocked: false,
+		},
+		{
+			name:       "On date",
+			invite:     models.Invite{EventDate: start, VaultPolicy: models.VaultPolicy{UnlockPolicy: OnDate, UnlockAt: &onDate}},
+			now:        onDate,
+			unlockDate: onDate,
+			unlocked:   true,
+		},
+		{
+			name:     "Manual never opens on its own",
+			invite:   models.Invite{EventDate: start, VaultPolicy: models.VaultPolicy{UnlockPolicy: Manual}},
+			now:      start.AddDate(1, 0, 0),
+			unlocked: false,
+		},
+		{
+			name:         "Enough contributors",
+			invite:       models.Invite{EventDate: start, VaultPolicy: models.VaultPolicy{UnlockPolicy: Contributors, MinContributors: intPtr(3)}},
+			contributors: 3,
+			now:          start.Add(time.Hour),
+			unlockDate:   start.Add(time.Hour),
+			unlocked:     true,
+		},
+		{
+			name:         "Too few contributors",
+			invite:       models.Invite{EventDate: start, VaultPolicy: models.VaultPolicy{UnlockPolicy: Contributors, MinContributors: intPtr(3)}},
+			contributors: 2,
+			now:          start.AddDate(1, 0, 0),
+			unlocked:     false,
+		},
+		{
+			name:     "Date still being polled",
+			invite:   models.Invite{EventDate: start, IsDateTBD: true, VaultPolicy: models.VaultPolicy{UnlockPolicy: AfterEvent}},
+			now:      start.AddDate(1, 0, 0),
+			unlocked: false,
+		},
+		{
+			name:     "Already unlocked",
+			invite:   models.Invite{EventDate: start, IsVaultUnlocked: true, VaultPolicy: models.VaultPolicy{UnlockPolicy: AfterEvent}},
+			now:      start.AddDate(1, 0, 0),
+			unlocked: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unlockDate, unlocked := ShouldUnlock(tt.invite, tt.contributors, tt.now)
+			assert.Equal(t, tt.unlocked, unlocked)
+			if tt.unlocked {
+				assert.Equal(t, tt.unlockDate, unlockDate)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	start := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		policy models.VaultPolicy
+		valid  bool
+	}{
+		{"Default", Normalize(models.VaultPolicy{}), true},
+		{"Manual", models.VaultPolicy{UnlockPolicy: Manual}, true},
+		{"Delay without hours", models.VaultPolicy{UnlockPolicy: Delay}, false},
+		{"Delay too long", models.VaultPolicy{UnlockPolicy: Delay, UnlockAfterHours: intPtr(9000)}, false},
+		{"Date before event", models.VaultPolicy{UnlockPolicy: OnDate, UnlockAt: &before}, false},
+		{"Zero contributors", models.VaultPolicy{UnlockPolicy: Contributors, MinContributors: intPtr(0)}, false},
+		{"Unknown policy", models.VaultPolicy{UnlockPolicy: "SOMETIME"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.policy, start)
+			assert.Equal(t, tt.valid, err == nil, "error: %v", err)
+		})
+	}
+}
+
+func TestNormalizeDropsUnusedSettings(t *testing.T) {
+	at := time.Now()
+	p := Normalize(models.VaultPolicy{UnlockPolicy: Manual, UnlockAfterHours: intPtr(5), UnlockAt: &at, MinContributors: intPtr(2)})
+	assert.Equal(t, models.VaultPolicy{UnlockPolicy: Manual}, p)
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/notify"
+	"privo-club-backend/internal/repository"
+)
+
+// Long posts are cut in the notification body; the post itself is a tap away
+const maxMentionExcerpt = 140
+
+// MentionJob tells people they were @mentioned in a feed post.
+type MentionJob struct {
+	Repo     repository.FeedRepository
+	Notifier notify.Notifier
+	Now      func() time.Time
+}
+
+func NewMentionJob(repo repository.FeedRepository, notifier notify.Notifier) *MentionJob {
+	return &MentionJob{Repo: repo, Notifier: notifier, Now: time.Now}
+}
+
+func (j *MentionJob) Name() string {
+	return "mentions"
+}
+
+// Run claims the pending mentions and sends one notification per post and
+// mentioned person. Mentions are claimed in the database, so concurrent
+// instances never send the same one twice. A failed send is released and
+// retried on the next run.
+func (j *MentionJob) Run(ctx context.Context) error {
+	recipients, err := j.Repo.ClaimMentionNotifications(ctx, j.Now())
+	if err != nil {
+		return fmt.Errorf("claim mention notifications: %w", err)
+	}
+
+	for _, recipient := range recipients {
+		if err := j.Notifier.Notify(ctx, mentionNotification(recipient)); err != nil {
+			slog.Error("Failed to send mention notification",
+				"invite_id", recipient.InviteID,
+				"feed_item_id", recipient.FeedItemID,
+				"user_id", recipient.UserID,
+				"error", err,
+			)
+			// Release the claim so the next run retries
+			if err := j.Repo.ReleaseMentionNotification(ctx, recipient.FeedItemID, recipient.UserID); err != nil {
+				return fmt.Errorf("release mention notification: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func mentionNotification(recipient models.MentionRecipient) notify.Notification {
+	author := "Someone"
+	if recipient.AuthorName != nil && *recipient.AuthorName != "" {
+		author = *recipient.AuthorName
+	}
+
+	excerpt := []rune(recipient.Content)
+	body := string(excerpt)
+	if len(excerpt) > maxMentionExcerpt {
+		body = string(excerpt[:maxMentionExcerpt]) + "…"
+	}
+
+	return notify.Notification{
+		Kind:     notify.KindMention,
+		UserID:   recipient.UserID,
+		Name:     recipient.Name,
+		Email:    recipient.Email,
+		InviteID: recipient.InviteID,
+		Subject:  fmt.Sprintf("%s mentioned you in %s", author, recipient.Title),
+		Body:     body,
+	}
+}
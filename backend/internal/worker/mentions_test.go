@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/notify"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMentionJobRun(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recipientColumns := []string{"inviteId", "title", "feedItemId", "content", "authorName", "userId", "name", "email"}
+	long := strings.Repeat("a", 200)
+
+	tests := []struct {
+		name         string
+		notifierErr  error
+		mockBehavior func(mock sqlmock.Sqlmock)
+		expectedSent int
+	}{
+		{
+			name: "Notifies everyone mentioned",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH claimed AS \(\s*UPDATE "FeedMention" SET "notifiedAt" = \$1`).
+					WithArgs(now).
+					WillReturnRows(sqlmock.NewRows(recipientColumns).
+						AddRow("invite-1", "Dinner", "feed-1", "@Ann can you bring ice?", "Hana", "user-1", "Ann", "ann@example.com").
+						AddRow("invite-1", "Dinner", "feed-2", long, nil, "user-2", "Bob", nil))
+			},
+			expectedSent: 2,
+		},
+		{
+			name:        "Failed deliveries are released for the next run",
+			notifierErr: errors.New("webhook down"),
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH claimed AS`).
+					WithArgs(now).
+					WillReturnRows(sqlmock.NewRows(recipientColumns).
+						AddRow("invite-1", "Dinner", "feed-1", "@Ann hi", "Hana", "user-1", "Ann", "ann@example.com"))
+				mock.ExpectExec(`UPDATE "FeedMention" SET "notifiedAt" = NULL WHERE "feedItemId" = \$1 AND "userId" = \$2`).
+					WithArgs("feed-1", "user-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Nothing pending",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`WITH claimed AS`).
+					WithArgs(now).
+					WillReturnRows(sqlmock.NewRows(recipientColumns))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error stubbing db: %s", err)
+			}
+			defer mockDB.Close()
+			repo := repository.NewFeedRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+			notifier := &fakeNotifier{err: tt.notifierErr}
+			job := NewMentionJob(repo, notifier)
+			job.Now = func() time.Time { return now }
+
+			tt.mockBehavior(mock)
+			assert.NoError(t, job.Run(context.Background()))
+			assert.Len(t, notifier.sent, tt.expectedSent)
+			if tt.expectedSent > 0 {
+				assert.Equal(t, notify.KindMention, notifier.sent[0].Kind)
+				assert.Equal(t, "Hana mentioned you in Dinner", notifier.sent[0].Subject)
+				assert.Equal(t, "@Ann can you bring ice?", notifier.sent[0].Body)
+				assert.Equal(t, "Someone mentioned you in Dinner", notifier.sent[1].Subject)
+				assert.Equal(t, strings.Repeat("a", maxMentionExcerpt)+"…", notifier.sent[1].Body)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
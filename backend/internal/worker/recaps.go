@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/recap"
+	"privo-club-backend/internal/repository"
+)
+
+// recapBatch caps how many recaps one run generates; the rest wait for the
+// next run.
+const recapBatch = 50
+
+// RecapJob generates the recap page of every invite whose vault has
+// unlocked, so attendees get a summary once the memories are out. Recaps are
+// built again when a post they may quote is edited or moderated.
+type RecapJob struct {
+	Repo     repository.RecapRepository
+	Invites  repository.InviteRepository
+	CheckIns repository.CheckInRepository
+	Expenses repository.ExpenseRepository
+	Now      func() time.Time
+}
+
+func NewRecapJob(repo repository.RecapRepository, invites repository.InviteRepository, checkIns repository.CheckInRepository, expenses repository.ExpenseRepository) *RecapJob {
+	return &RecapJob{Repo: repo, Invites: invites, CheckIns: checkIns, Expenses: expenses, Now: time.Now}
+}
+
+func (j *RecapJob) Name() string {
+	return "recaps"
+}
+
+// Run generates each recap once, and again each time it goes stale. If
+// several instances build the same recap, the first one stored wins. An
+// invite that fails is logged and retried on the next run without holding up
+// the others.
+func (j *RecapJob) Run(ctx context.Context) error {
+	inviteIDs, err := j.Repo.ListPendingRecaps(ctx, recapBatch)
+	if err != nil {
+		return fmt.Errorf("list pending recaps: %w", err)
+	}
+
+	for _, inviteID := range inviteIDs {
+		if err := j.generate(ctx, inviteID); err != nil {
+			slog.Error("Failed to generate recap", "invite_id", inviteID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (j *RecapJob) generate(ctx context.Context, inviteID string) error {
+	// Taken first, so posts changed while building mark the recap stale again
+	now := j.Now()
+
+	// No viewer: the recap is the same for everyone
+	details, err := j.Invites.GetInviteDetails(ctx, inviteID, "")
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted since it was listed
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get invite details: %w", err)
+	}
+	checkIns, err := j.CheckIns.ListCheckIns(ctx, inviteID)
+	if err != nil {
+		return fmt.Errorf("list check-ins: %w", err)
+	}
+	expenses, err := j.Expenses.ListExpenses(ctx, inviteID)
+	if err != nil {
+		return fmt.Errorf("list expenses: %w", err)
+	}
+
+	manifest := recap.Build(details, checkIns, expenses, now)
+	body, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("encode recap: %w", err)
+	}
+	html, err := recap.Render(manifest)
+	if err != nil {
+		return err
+	}
+
+	_, err = j.Repo.CreateRecap(ctx, &models.InviteRecap{
+		InviteID:    inviteID,
+		Manifest:    body,
+		HTML:        html,
+		GeneratedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("create recap: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+// recapManifest checks the stored manifest without pinning its exact encoding
+type recapManifest func(models.RecapManifest) bool
+
+func (m recapManifest) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	var manifest models.RecapManifest
+	if err := json.Unmarshal([]byte(s), &manifest); err != nil {
+		return false
+	}
+	return m(manifest)
+}
+
+type recapPage string
+
+func (p recapPage) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	return ok && strings.Contains(s, string(p))
+}
+
+func expectRecapSources(mock sqlmock.Sqlmock, inviteID string, eventDate time.Time) {
+	mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "eventDate", "senderId", "circleId", "isVaultUnlocked"}).
+			AddRow(inviteID, "Dinner", eventDate, "user-host", nil, true))
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+		WithArgs("user-host").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("user-host", "Hana"))
+	mock.ExpectQuery(`SELECT r\.\*, .* FROM "RSVP"`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "userId", "status", "guestCount", "user.id", "user.name"}).
+			AddRow("rsvp-1", inviteID, "user-1", "YES", 2, "user-1", "Ann").
+			AddRow("rsvp-2", inviteID, "user-2", "NO", 1, "user-2", "Bob"))
+	mock.ExpectQuery(`SELECT inv\.\*, .* FROM "InviteInvitee"`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectQuery(`SELECT \* FROM "GuestRSVP"`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectQuery(`SELECT \* FROM "SignupSheet"`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectQuery(`SELECT it\.\* FROM "SignupItem"`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectQuery(`SELECT c\.\*, .* FROM "SignupClaim"`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectQuery(`SELECT f\.\*, .* FROM "EventFeedItem"`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectQuery(`SELECT \* FROM "MediaItem"`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "userId", "url", "type"}).
+			AddRow("media-1", inviteID, "user-1", "/uploads/"+inviteID+"/a.jpg", "IMAGE"))
+	mock.ExpectQuery(`SELECT \* FROM "CheckIn" WHERE "inviteId" = \$1`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectQuery(`SELECT \* FROM "Expense" WHERE "inviteId" = \$1`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inviteId", "amountCents"}).AddRow("expense-1", inviteID, 4200))
+	mock.ExpectQuery(`SELECT es\.\* FROM "ExpenseShare" es`).
+		WithArgs(inviteID).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+}
+
+func TestRecapJobRun(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	eventDate := now.Add(-30 * time.Hour)
+
+	tests := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "Generates recaps for unlocked vaults",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT i.id FROM "Invite" i LEFT JOIN "InviteRecap" r`).
+					WithArgs(recapBatch).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invite-1").AddRow("invite-gone"))
+				expectRecapSources(mock, "invite-1", eventDate)
+				mock.ExpectExec(`INSERT INTO "InviteRecap" (.+) ON CONFLICT \("inviteId"\) DO UPDATE .+ WHERE "InviteRecap"."staleAt" IS NOT NULL`).
+					WithArgs("invite-1", recapManifest(func(m models.RecapManifest) bool {
+						return m.Title == "Dinner" && m.Headcount == 2 && len(m.Media) == 1 &&
+							m.Expenses != nil && m.Expenses.TotalCents == 4200 && m.Signups == nil
+					}), recapPage("Hosted by Hana"), now).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				// Deleted after it was listed
+				mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+					WithArgs("invite-gone").
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name: "A failing invite does not hold up the others",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT i.id FROM "Invite" i LEFT JOIN "InviteRecap" r`).
+					WithArgs(recapBatch).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invite-broken").AddRow("invite-1"))
+				mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+					WithArgs("invite-broken").
+					WillReturnError(sql.ErrConnDone)
+				expectRecapSources(mock, "invite-1", eventDate)
+				mock.ExpectExec(`INSERT INTO "InviteRecap" (.+) ON CONFLICT \("inviteId"\) DO UPDATE .+ WHERE "InviteRecap"."staleAt" IS NOT NULL`).
+					WithArgs("invite-1", sqlmock.AnyArg(), sqlmock.AnyArg(), now).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			// A recap is stored for good, so it must not be built from partial details
+			name: "Stores nothing when part of the details fails to load",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT i.id FROM "Invite" i LEFT JOIN "InviteRecap" r`).
+					WithArgs(recapBatch).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invite-1"))
+				mock.ExpectQuery(`SELECT \* FROM "Invite" WHERE id = \$1`).
+					WithArgs("invite-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "eventDate", "senderId", "circleId", "isVaultUnlocked"}).
+						AddRow("invite-1", "Dinner", eventDate, "user-host", nil, true))
+				mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+					WithArgs("user-host").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("user-host", "Hana"))
+				mock.ExpectQuery(`SELECT r\.\*, .* FROM "RSVP"`).
+					WithArgs("invite-1").
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+		{
+			name: "Nothing to do",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT i.id FROM "Invite" i LEFT JOIN "InviteRecap" r`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error stubbing db: %s", err)
+			}
+			defer mockDB.Close()
+			db := sqlx.NewDb(mockDB, "sqlmock")
+
+			job := NewRecapJob(repository.NewRecapRepository(db), repository.NewInviteRepository(db), repository.NewCheckInRepository(db), repository.NewExpenseRepository(db))
+			job.Now = func() time.Time { return now }
+
+			tt.mockBehavior(mock)
+			assert.NoError(t, job.Run(context.Background()))
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
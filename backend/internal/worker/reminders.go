@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/notify"
+	"privo-club-backend/internal/repository"
+)
+
+// ReminderJob sends "your event is coming up" reminders at each configured
+// offset before an invite's eventDate.
+type ReminderJob struct {
+	Repo     repository.ReminderRepository
+	Notifier notify.Notifier
+	Offsets  []time.Duration // Sorted longest first
+	Now      func() time.Time
+}
+
+func NewReminderJob(repo repository.ReminderRepository, notifier notify.Notifier, offsets []time.Duration) *ReminderJob {
+	sorted := append([]time.Duration(nil), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+
+	return &ReminderJob{
+		Repo:     repo,
+		Notifier: notifier,
+		Offsets:  sorted,
+		Now:      time.Now,
+	}
+}
+
+func (j *ReminderJob) Name() string {
+	return "event-reminders"
+}
+
+// Run sends every reminder that is due. Each offset owns the window between
+// itself and the next shorter offset. An invite created two days before the
+// event falls in the 1-week window, so it gets that reminder right away,
+// worded "in 2 days", and the 1-day one later. One created twelve hours
+// before skips the 1-week reminder, whose window has already passed.
+func (j *ReminderJob) Run(ctx context.Context) error {
+	now := j.Now()
+
+	for i, offset := range j.Offsets {
+		var next time.Duration
+		if i+1 < len(j.Offsets) {
+			next = j.Offsets[i+1]
+		}
+		offsetMinutes := int(offset / time.Minute)
+
+		targets, err := j.Repo.ListDueReminders(ctx, now.Add(next), now.Add(offset), offsetMinutes)
+		if err != nil {
+			return fmt.Errorf("list due reminders (%s): %w", offset, err)
+		}
+
+		for _, target := range targets {
+			if err := j.deliver(ctx, target, offsetMinutes, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (j *ReminderJob) deliver(ctx context.Context, target models.ReminderTarget, offsetMinutes int, now time.Time) error {
+	claimed, err := j.Repo.ClaimReminder(ctx, target.InviteID, target.UserID, offsetMinutes)
+	if err != nil {
+		return fmt.Errorf("claim reminder: %w", err)
+	}
+	if !claimed {
+		// Another instance already sent it
+		return nil
+	}
+
+	if err := j.Notifier.Notify(ctx, reminderNotification(target, now)); err != nil {
+		slog.Error("Failed to send reminder",
+			"invite_id", target.InviteID,
+			"user_id", target.UserID,
+			"error", err,
+		)
+		// Release the claim so the next run retries
+		if err := j.Repo.ReleaseReminder(ctx, target.InviteID, target.UserID, offsetMinutes); err != nil {
+			return fmt.Errorf("release reminder: %w", err)
+		}
+	}
+	return nil
+}
+
+func reminderNotification(target models.ReminderTarget, now time.Time) notify.Notification {
+	subject := fmt.Sprintf("%s starts %s", target.Title, humanizeUntil(target.EventDate.Sub(now)))
+
+	body := fmt.Sprintf("%s is on %s.", target.Title, target.EventDate.Format("Mon, Jan 2 at 3:04 PM"))
+	if target.Location != nil && *target.Location != "" {
+		body += fmt.Sprintf(" Location: %s.", *target.Location)
+	}
+	if target.RSVPStatus == nil {
+		body += " You haven't responded yet, let the host know if you can make it."
+	} else if *target.RSVPStatus == "MAYBE" {
+		body += " You said maybe, let the host know if your plans have changed."
+	}
+
+	return notify.Notification{
+		Kind:     notify.KindEventReminder,
+		UserID:   target.UserID,
+		Name:     target.Name,
+		Email:    target.Email,
+		InviteID: target.InviteID,
+		Subject:  subject,
+		Body:     body,
+	}
+}
+
+// humanizeUntil renders a duration as "in 3 days", "in 2 hours" or "soon".
+func humanizeUntil(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("in %d days", int(d.Round(24*time.Hour)/(24*time.Hour)))
+	case d >= 24*time.Hour:
+		return "tomorrow"
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("in %d hours", int(d.Round(time.Hour)/time.Hour))
+	case d >= time.Hour:
+		return "in an hour"
+	default:
+		return "soon"
+	}
+}
@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/notify"
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNotifier struct {
+	sent []notify.Notification
+	err  error
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, n notify.Notification) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func TestReminderJobRun(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	eventDate := now.Add(20 * time.Hour)
+	reminderColumns := []string{"inviteId", "title", "eventDate", "location", "userId", "name", "email", "rsvpStatus"}
+
+	tests := []struct {
+		name         string
+		notifierErr  error
+		mockBehavior func(mock sqlmock.Sqlmock)
+		expectedSent int
+	}{
+		{
+			name: "Sends claimed reminder once",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				// 24h window is (now+2h, now+24h]
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+					WithArgs(now.Add(2*time.Hour), now.Add(24*time.Hour), 1440).
+					WillReturnRows(sqlmock.NewRows(reminderColumns).
+						AddRow("invite-1", "Dinner", eventDate, nil, "user-1", "Ann", "ann@example.com", nil).
+						AddRow("invite-1", "Dinner", eventDate, nil, "user-2", "Bob", "bob@example.com", "YES"))
+				mock.ExpectExec(`INSERT INTO "ReminderDelivery"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-1", 1440, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				// Already claimed by another instance
+				mock.ExpectExec(`INSERT INTO "ReminderDelivery"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-2", 1440, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				// 2h window is (now, now+2h]
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+					WithArgs(now, now.Add(2*time.Hour), 120).
+					WillReturnRows(sqlmock.NewRows(reminderColumns))
+			},
+			expectedSent: 1,
+		},
+		{
+			name:        "Releases claim when delivery fails",
+			notifierErr: errors.New("smtp down"),
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+					WithArgs(now.Add(2*time.Hour), now.Add(24*time.Hour), 1440).
+					WillReturnRows(sqlmock.NewRows(reminderColumns).
+						AddRow("invite-1", "Dinner", eventDate, nil, "user-1", "Ann", "ann@example.com", nil))
+				mock.ExpectExec(`INSERT INTO "ReminderDelivery"`).
+					WithArgs(sqlmock.AnyArg(), "invite-1", "user-1", 1440, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`DELETE FROM "ReminderDelivery"`).
+					WithArgs("invite-1", "user-1", 1440).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+					WithArgs(now, now.Add(2*time.Hour), 120).
+					WillReturnRows(sqlmock.NewRows(reminderColumns))
+			},
+			expectedSent: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error stubbing db: %s", err)
+			}
+			defer mockDB.Close()
+			repo := repository.NewReminderRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+			notifier := &fakeNotifier{err: tt.notifierErr}
+			job := NewReminderJob(repo, notifier, []time.Duration{2 * time.Hour, 24 * time.Hour})
+			job.Now = func() time.Time { return now }
+
+			tt.mockBehavior(mock)
+			assert.NoError(t, job.Run(context.Background()))
+			assert.Len(t, notifier.sent, tt.expectedSent)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestReminderJobRunInsideLongerWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	eventDate := now.Add(48 * time.Hour) // Created two days before the event
+	reminderColumns := []string{"inviteId", "title", "eventDate", "location", "userId", "name", "email", "rsvpStatus"}
+
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+	repo := repository.NewReminderRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	notifier := &fakeNotifier{}
+	job := NewReminderJob(repo, notifier, []time.Duration{24 * time.Hour, 7 * 24 * time.Hour})
+	job.Now = func() time.Time { return now }
+
+	// The 1-week window (now+24h, now+168h] still holds the event
+	mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+		WithArgs(now.Add(24*time.Hour), now.Add(7*24*time.Hour), 10080).
+		WillReturnRows(sqlmock.NewRows(reminderColumns).
+			AddRow("invite-1", "Dinner", eventDate, nil, "user-1", "Ann", "ann@example.com", "YES"))
+	mock.ExpectExec(`INSERT INTO "ReminderDelivery"`).
+		WithArgs(sqlmock.AnyArg(), "invite-1", "user-1", 10080, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// The 1-day reminder waits for its own window
+	mock.ExpectQuery(`SELECT .* FROM "Invite" i`).
+		WithArgs(now, now.Add(24*time.Hour), 1440).
+		WillReturnRows(sqlmock.NewRows(reminderColumns))
+
+	assert.NoError(t, job.Run(context.Background()))
+	if assert.Len(t, notifier.sent, 1) {
+		assert.Equal(t, "Dinner starts in 2 days", notifier.sent[0].Subject)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestHumanizeUntil(t *testing.T) {
+	assert.Equal(t, "in 7 days", humanizeUntil(167*time.Hour))
+	assert.Equal(t, "tomorrow", humanizeUntil(24*time.Hour))
+	assert.Equal(t, "in 2 hours", humanizeUntil(2*time.Hour))
+	assert.Equal(t, "soon", humanizeUntil(30*time.Minute))
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Job is a unit of background work that runs on a fixed interval.
+// Jobs must be safe to run concurrently on several API instances; any
+// coordination happens in the database, not in memory.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type entry struct {
+	job      Job
+	interval time.Duration
+}
+
+// Scheduler runs registered jobs periodically until its context is cancelled.
+type Scheduler struct {
+	entries []entry
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers job to run once at start-up and then every interval.
+func (s *Scheduler) Every(interval time.Duration, job Job) {
+	s.entries = append(s.entries, entry{job: job, interval: interval})
+}
+
+// Start launches one goroutine per job and returns immediately.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, e := range s.entries {
+		go run(ctx, e)
+	}
+}
+
+func run(ctx context.Context, e entry) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		runOnce(ctx, e.job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Error("Job panicked", "job", job.Name(), "panic", p)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		slog.Error("Job failed", "job", job.Name(), "error", err)
+		return
+	}
+	slog.Debug("Job finished", "job", job.Name(), "duration", time.Since(start))
+}
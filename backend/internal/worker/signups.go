@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/utils"
+)
+
+// SignupSummaryJob posts a list of still-unclaimed sign-up items to the
+// event feed once the event is within Lead of starting.
+type SignupSummaryJob struct {
+	Repo repository.SignupRepository
+	Lead time.Duration
+	Now  func() time.Time
+}
+
+func NewSignupSummaryJob(repo repository.SignupRepository, lead time.Duration) *SignupSummaryJob {
+	return &SignupSummaryJob{Repo: repo, Lead: lead, Now: time.Now}
+}
+
+func (j *SignupSummaryJob) Name() string {
+	return "signup-summaries"
+}
+
+// Run posts at most one summary per sheet. Sheets where everything is claimed
+// are marked as done without a post.
+func (j *SignupSummaryJob) Run(ctx context.Context) error {
+	now := j.Now()
+
+	targets, err := j.Repo.ListSummaryTargets(ctx, now, now.Add(j.Lead))
+	if err != nil {
+		return fmt.Errorf("list signup summary targets: %w", err)
+	}
+
+	for _, target := range targets {
+		items, err := j.Repo.ListUnclaimedItems(ctx, target.ID)
+		if err != nil {
+			return fmt.Errorf("list unclaimed items: %w", err)
+		}
+
+		var post *models.EventFeedItem
+		if len(items) > 0 {
+			post = &models.EventFeedItem{
+				ID:        utils.GenerateID("feed"),
+				InviteID:  target.InviteID,
+				UserID:    target.CreatedByID,
+				Content:   signupSummaryContent(target.Title, items),
+				Type:      "UPDATE",
+				CreatedAt: now,
+			}
+		}
+
+		if _, err := j.Repo.PostSummary(ctx, target.ID, post); err != nil {
+			return fmt.Errorf("post signup summary: %w", err)
+		}
+	}
+	return nil
+}
+
+func signupSummaryContent(sheetTitle string, items []models.UnclaimedSignupItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprintf("%s (%d left)", item.Name, item.Remaining)
+	}
+	return fmt.Sprintf("Still needed for %s: %s", sheetTitle, strings.Join(parts, ", "))
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignupSummaryJobRun(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error stubbing db: %s", err)
+	}
+	defer mockDB.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	job := NewSignupSummaryJob(repository.NewSignupRepository(sqlx.NewDb(mockDB, "sqlmock")), 24*time.Hour)
+	job.Now = func() time.Time { return now }
+
+	targetColumns := []string{"id", "inviteId", "title", "description", "createdById", "summaryPostedAt", "createdAt", "inviteTitle"}
+	mock.ExpectQuery(`SELECT s\.\*, .* FROM "SignupSheet" s`).
+		WithArgs(now, now.Add(24*time.Hour)).
+		WillReturnRows(sqlmock.NewRows(targetColumns).
+			AddRow("sheet-1", "invite-1", "Potluck", nil, "user-host", nil, now, "Dinner").
+			AddRow("sheet-2", "invite-1", "Cleanup", nil, "user-host", nil, now, "Dinner"))
+
+	// Sheet with open items gets a feed post
+	mock.ExpectQuery(`SELECT it\.id, it\.name`).
+		WithArgs("sheet-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "remaining"}).
+			AddRow("item-1", "Salad", 2).
+			AddRow("item-2", "Chips", 1))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "SignupSheet" SET "summaryPostedAt"`).
+		WithArgs("sheet-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "EventFeedItem"`).
+		WithArgs(sqlmock.AnyArg(), "invite-1", "user-host", "Still needed for Potluck: Salad (2 left), Chips (1 left)", "UPDATE", now, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Fully claimed sheet is only marked as done
+	mock.ExpectQuery(`SELECT it\.id, it\.name`).
+		WithArgs("sheet-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "remaining"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "SignupSheet" SET "summaryPostedAt"`).
+		WithArgs("sheet-2", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, job.Run(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/notify"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/vault"
+)
+
+// vaultCandidateBatch caps how many vaults one run unlocks; the rest wait for
+// the next run.
+const vaultCandidateBatch = 200
+
+// VaultUnlockJob opens Memory Vaults whose unlock policy is due and tells
+// attendees their memories are ready.
+type VaultUnlockJob struct {
+	Repo     repository.VaultRepository
+	Notifier notify.Notifier
+	Now      func() time.Time
+}
+
+func NewVaultUnlockJob(repo repository.VaultRepository, notifier notify.Notifier) *VaultUnlockJob {
+	return &VaultUnlockJob{Repo: repo, Notifier: notifier, Now: time.Now}
+}
+
+func (j *VaultUnlockJob) Name() string {
+	return "vault-unlocks"
+}
+
+// Run unlocks due vaults, then announces every open vault that has not been
+// announced yet, which also covers vaults the host opened by hand. Both steps
+// are guarded in the database so concurrent instances never repeat them, and
+// attendees whose notification failed are retried on the next run.
+func (j *VaultUnlockJob) Run(ctx context.Context) error {
+	now := j.Now()
+
+	candidates, err := j.Repo.ListVaultCandidates(ctx, now, vaultCandidateBatch)
+	if err != nil {
+		return fmt.Errorf("list vault candidates: %w", err)
+	}
+	for _, c := range candidates {
+		unlockDate, ok := vault.ShouldUnlock(c.Invite, c.Contributors, now)
+		if !ok {
+			continue
+		}
+		if err := j.Repo.UnlockVault(ctx, c.ID, unlockDate); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("unlock vault: %w", err)
+		}
+	}
+
+	inviteIDs, err := j.Repo.ListUnannouncedVaults(ctx)
+	if err != nil {
+		return fmt.Errorf("list unannounced vaults: %w", err)
+	}
+	for _, inviteID := range inviteIDs {
+		if err := j.announce(ctx, inviteID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// announce tells each recipient who has not been told yet. The vault is only
+// marked as announced once nobody is left, so failed sends are retried.
+func (j *VaultUnlockJob) announce(ctx context.Context, inviteID string, now time.Time) error {
+	recipients, err := j.Repo.ListVaultRecipients(ctx, inviteID)
+	if err != nil {
+		return fmt.Errorf("list vault recipients: %w", err)
+	}
+
+	done := true
+	for _, recipient := range recipients {
+		sent, err := j.deliver(ctx, recipient)
+		if err != nil {
+			return err
+		}
+		done = done && sent
+	}
+	if !done {
+		return nil
+	}
+
+	if err := j.Repo.MarkVaultAnnounced(ctx, inviteID, now); err != nil {
+		return fmt.Errorf("mark vault announced: %w", err)
+	}
+	return nil
+}
+
+// deliver reports false when the notification failed and has to be retried.
+func (j *VaultUnlockJob) deliver(ctx context.Context, recipient models.VaultRecipient) (bool, error) {
+	claimed, err := j.Repo.ClaimVaultDelivery(ctx, recipient.InviteID, recipient.UserID)
+	if err != nil {
+		return false, fmt.Errorf("claim vault notification: %w", err)
+	}
+	if !claimed {
+		// Already told, by this or another instance
+		return true, nil
+	}
+
+	if err := j.Notifier.Notify(ctx, vaultNotification(recipient)); err != nil {
+		slog.Error("Failed to send vault notification",
+			"invite_id", recipient.InviteID,
+			"user_id", recipient.UserID,
+			"error", err,
+		)
+		// Release the claim so the next run retries
+		if err := j.Repo.ReleaseVaultDelivery(ctx, recipient.InviteID, recipient.UserID); err != nil {
+			return false, fmt.Errorf("release vault notification: %w", err)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func vaultNotification(recipient models.VaultRecipient) notify.Notification {
+	return notify.Notification{
+		Kind:     notify.KindVaultUnlocked,
+		UserID:   recipient.UserID,
+		Name:     recipient.Name,
+		Email:    recipient.Email,
+		InviteID: recipient.InviteID,
+		Subject:  fmt.Sprintf("Your memories from %s are unlocked", recipient.Title),
+		Body:     fmt.Sprintf("The Memory Vault for %s is open. Relive the photos and moments everyone shared.", recipient.Title),
+	}
+}
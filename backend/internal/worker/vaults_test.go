@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"privo-club-backend/internal/models"
+	"privo-club-backend/internal/notify"
+	"privo-club-backend/internal/repository"
+	"privo-club-backend/internal/vault"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultUnlockJobRun(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	candidateColumns := []string{"id", "title", "eventDate", "senderId", "isVaultUnlocked", "isDateTBD", "vaultPolicy", "vaultUnlockAfterHours", "vaultMinContributors", "contributors"}
+	recipientColumns := []string{"inviteId", "title", "userId", "name", "email"}
+
+	expectClaim := func(mock sqlmock.Sqlmock, inviteID, userID string, claimed bool) {
+		var rows int64
+		if claimed {
+			rows = 1
+		}
+		mock.ExpectExec(`INSERT INTO "VaultDelivery"`).
+			WithArgs(sqlmock.AnyArg(), inviteID, userID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+	}
+
+	tests := []struct {
+		name         string
+		notifierErr  error
+		mockBehavior func(mock sqlmock.Sqlmock)
+		expectedSent int
+	}{
+		{
+			name: "Unlocks due vaults and announces them once",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT i\.\*, (.+) FROM "Invite" i`).
+					WithArgs(now, vaultCandidateBatch, models.DefaultEventDuration.Seconds(), vault.DefaultDelayHours).
+					WillReturnRows(sqlmock.NewRows(candidateColumns).
+						// Ended 3h after the start, 24h delay has passed
+						AddRow("invite-due", "Dinner", now.Add(-30*time.Hour), "user-host", false, false, "DELAY", 24, nil, 0).
+						// Delay not reached yet
+						AddRow("invite-later", "Brunch", now.Add(-5*time.Hour), "user-host", false, false, "DELAY", 24, nil, 0).
+						// Enough people shared photos
+						AddRow("invite-shared", "Hike", now.Add(-2*time.Hour), "user-host", false, false, "CONTRIBUTORS", nil, 2, 2))
+				mock.ExpectExec(`UPDATE "Invite" SET "isVaultUnlocked" = TRUE`).
+					WithArgs("invite-due", now.Add(-3*time.Hour)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				// Another instance unlocked it first
+				mock.ExpectExec(`UPDATE "Invite" SET "isVaultUnlocked" = TRUE`).
+					WithArgs("invite-shared", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectQuery(`SELECT id FROM "Invite" WHERE "isVaultUnlocked" AND "vaultNotifiedAt" IS NULL`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invite-due").AddRow("invite-shared"))
+				mock.ExpectQuery(`SELECT (.+) FROM "Invite" i JOIN "User" u`).
+					WithArgs("invite-due").
+					WillReturnRows(sqlmock.NewRows(recipientColumns).
+						AddRow("invite-due", "Dinner", "user-host", "Hana", "hana@example.com").
+						AddRow("invite-due", "Dinner", "user-1", "Ann", "ann@example.com"))
+				expectClaim(mock, "invite-due", "user-host", true)
+				expectClaim(mock, "invite-due", "user-1", true)
+				mock.ExpectExec(`UPDATE "Invite" SET "vaultNotifiedAt"`).
+					WithArgs("invite-due", now).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				// Another instance already told the host
+				mock.ExpectQuery(`SELECT (.+) FROM "Invite" i JOIN "User" u`).
+					WithArgs("invite-shared").
+					WillReturnRows(sqlmock.NewRows(recipientColumns).
+						AddRow("invite-shared", "Hike", "user-host", "Hana", "hana@example.com"))
+				expectClaim(mock, "invite-shared", "user-host", false)
+				mock.ExpectExec(`UPDATE "Invite" SET "vaultNotifiedAt"`).
+					WithArgs("invite-shared", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedSent: 2,
+		},
+		{
+			name:        "Failed sends are released and the vault stays unannounced",
+			notifierErr: errors.New("smtp down"),
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT i\.\*, (.+) FROM "Invite" i`).
+					WillReturnRows(sqlmock.NewRows(candidateColumns))
+				mock.ExpectQuery(`SELECT id FROM "Invite" WHERE "isVaultUnlocked" AND "vaultNotifiedAt" IS NULL`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invite-due"))
+				mock.ExpectQuery(`SELECT (.+) FROM "Invite" i JOIN "User" u`).
+					WithArgs("invite-due").
+					WillReturnRows(sqlmock.NewRows(recipientColumns).
+						AddRow("invite-due", "Dinner", "user-1", "Ann", "ann@example.com"))
+				expectClaim(mock, "invite-due", "user-1", true)
+				mock.ExpectExec(`DELETE FROM "VaultDelivery"`).
+					WithArgs("invite-due", "user-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedSent: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error stubbing db: %s", err)
+			}
+			defer mockDB.Close()
+			repo := repository.NewVaultRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+			notifier := &fakeNotifier{err: tt.notifierErr}
+			job := NewVaultUnlockJob(repo, notifier)
+			job.Now = func() time.Time { return now }
+
+			tt.mockBehavior(mock)
+			assert.NoError(t, job.Run(context.Background()))
+			assert.Len(t, notifier.sent, tt.expectedSent)
+			for _, n := range notifier.sent {
+				assert.Equal(t, notify.KindVaultUnlocked, n.Kind)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
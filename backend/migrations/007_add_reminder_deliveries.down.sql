@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "Invite_eventDate_idx";
+DROP TABLE IF EXISTS "ReminderDelivery";
@@ -0,0 +1,15 @@
+-- Tracks which scheduled reminders have already been sent so that restarts and
+-- multiple API instances never deliver the same reminder twice.
+CREATE TABLE IF NOT EXISTS "ReminderDelivery" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "offsetMinutes" INTEGER NOT NULL,
+    "sentAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "ReminderDelivery_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "ReminderDelivery_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "ReminderDelivery_inviteId_userId_offsetMinutes_key" ON "ReminderDelivery"("inviteId", "userId", "offsetMinutes");
+CREATE INDEX IF NOT EXISTS "Invite_eventDate_idx" ON "Invite"("eventDate");
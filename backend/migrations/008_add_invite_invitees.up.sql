@@ -0,0 +1,19 @@
+-- Users invited directly to an event, independent of any circle.
+-- An invitee is either a known user or an email address that is resolved to a
+-- user the first time someone with that email signs in.
+CREATE TABLE IF NOT EXISTS "InviteInvitee" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "userId" TEXT,
+    "email" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "InviteInvitee_target_check" CHECK ("userId" IS NOT NULL OR "email" IS NOT NULL),
+    CONSTRAINT "InviteInvitee_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "InviteInvitee_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "InviteInvitee_inviteId_userId_key" ON "InviteInvitee"("inviteId", "userId") WHERE "userId" IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "InviteInvitee_inviteId_email_key" ON "InviteInvitee"("inviteId", lower("email")) WHERE "email" IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "InviteInvitee_userId_idx" ON "InviteInvitee"("userId");
+CREATE INDEX IF NOT EXISTS "InviteInvitee_email_idx" ON "InviteInvitee"(lower("email")) WHERE "userId" IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "GuestRSVP";
+DROP TABLE IF EXISTS "InviteShareLink";
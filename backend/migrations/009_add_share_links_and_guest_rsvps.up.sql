@@ -0,0 +1,34 @@
+-- Revocable public links that let people without an account view an invite and RSVP
+CREATE TABLE IF NOT EXISTS "InviteShareLink" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "token" TEXT NOT NULL UNIQUE,
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "revokedAt" TIMESTAMP(3),
+
+    CONSTRAINT "InviteShareLink_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "InviteShareLink_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "InviteShareLink_inviteId_idx" ON "InviteShareLink"("inviteId");
+
+-- RSVPs from guests who answered through a share link
+CREATE TABLE IF NOT EXISTS "GuestRSVP" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "shareLinkId" TEXT,
+    "name" TEXT NOT NULL,
+    "email" TEXT NOT NULL,
+    "status" TEXT NOT NULL,
+    "guestCount" INTEGER NOT NULL DEFAULT 1,
+    "dietary" TEXT,
+    "note" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "GuestRSVP_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "GuestRSVP_shareLinkId_fkey" FOREIGN KEY ("shareLinkId") REFERENCES "InviteShareLink"("id") ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "GuestRSVP_inviteId_email_key" ON "GuestRSVP"("inviteId", lower("email"));
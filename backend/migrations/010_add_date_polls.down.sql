@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "DatePollVote";
+DROP TABLE IF EXISTS "DatePollOption";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "isDateTBD";
@@ -0,0 +1,28 @@
+-- Invites whose date is being decided by a poll. While "isDateTBD" is set,
+-- "eventDate" holds the earliest candidate as a placeholder.
+ALTER TABLE "Invite" ADD COLUMN "isDateTBD" BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS "DatePollOption" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "startsAt" TIMESTAMP(3) NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "DatePollOption_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "DatePollOption_inviteId_startsAt_key" ON "DatePollOption"("inviteId", "startsAt");
+
+CREATE TABLE IF NOT EXISTS "DatePollVote" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "optionId" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "vote" TEXT NOT NULL, -- YES, IF_NEEDED, NO
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "DatePollVote_optionId_fkey" FOREIGN KEY ("optionId") REFERENCES "DatePollOption"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "DatePollVote_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "DatePollVote_optionId_userId_key" ON "DatePollVote"("optionId", "userId");
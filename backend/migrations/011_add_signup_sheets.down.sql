@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "SignupClaim";
+DROP TABLE IF EXISTS "SignupItem";
+DROP TABLE IF EXISTS "SignupSheet";
@@ -0,0 +1,43 @@
+-- Potluck / volunteer sign-up sheets attached to an invite. Each item has a
+-- quantity that attendees claim a share of.
+CREATE TABLE IF NOT EXISTS "SignupSheet" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "title" TEXT NOT NULL,
+    "description" TEXT,
+    "createdById" TEXT NOT NULL,
+    "summaryPostedAt" TIMESTAMP(3), -- Set once the unclaimed summary went to the feed
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "SignupSheet_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "SignupSheet_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "SignupSheet_inviteId_idx" ON "SignupSheet"("inviteId");
+
+CREATE TABLE IF NOT EXISTS "SignupItem" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "sheetId" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "quantity" INTEGER NOT NULL CHECK ("quantity" > 0),
+    "position" INTEGER NOT NULL DEFAULT 0,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "SignupItem_sheetId_fkey" FOREIGN KEY ("sheetId") REFERENCES "SignupSheet"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "SignupItem_sheetId_idx" ON "SignupItem"("sheetId");
+
+CREATE TABLE IF NOT EXISTS "SignupClaim" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "itemId" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "quantity" INTEGER NOT NULL CHECK ("quantity" > 0),
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "SignupClaim_itemId_fkey" FOREIGN KEY ("itemId") REFERENCES "SignupItem"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "SignupClaim_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "SignupClaim_itemId_userId_key" ON "SignupClaim"("itemId", "userId");
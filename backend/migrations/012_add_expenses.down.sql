@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "Settlement";
+DROP TABLE IF EXISTS "ExpenseShare";
+DROP TABLE IF EXISTS "Expense";
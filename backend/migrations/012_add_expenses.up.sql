@@ -0,0 +1,48 @@
+-- Shared expense ledger per invite. Amounts are stored in cents.
+CREATE TABLE IF NOT EXISTS "Expense" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "paidById" TEXT NOT NULL,
+    "description" TEXT NOT NULL,
+    "amountCents" BIGINT NOT NULL CHECK ("amountCents" > 0),
+    "splitType" TEXT NOT NULL, -- EVEN, SHARES, EXACT
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "Expense_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "Expense_paidById_fkey" FOREIGN KEY ("paidById") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "Expense_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "Expense_inviteId_idx" ON "Expense"("inviteId");
+
+-- What each participant owes for an expense, already split
+CREATE TABLE IF NOT EXISTS "ExpenseShare" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "expenseId" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "shares" INTEGER, -- Only for SHARES splits
+    "amountCents" BIGINT NOT NULL CHECK ("amountCents" >= 0),
+
+    CONSTRAINT "ExpenseShare_expenseId_fkey" FOREIGN KEY ("expenseId") REFERENCES "Expense"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "ExpenseShare_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "ExpenseShare_expenseId_userId_key" ON "ExpenseShare"("expenseId", "userId");
+
+CREATE TABLE IF NOT EXISTS "Settlement" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "fromUserId" TEXT NOT NULL,
+    "toUserId" TEXT NOT NULL,
+    "amountCents" BIGINT NOT NULL CHECK ("amountCents" > 0),
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "Settlement_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "Settlement_fromUserId_fkey" FOREIGN KEY ("fromUserId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "Settlement_toUserId_fkey" FOREIGN KEY ("toUserId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "Settlement_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "Settlement_inviteId_idx" ON "Settlement"("inviteId");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "RideRequest";
+DROP TABLE IF EXISTS "RideOffer";
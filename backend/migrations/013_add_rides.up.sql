@@ -0,0 +1,36 @@
+-- Carpool coordination per invite. A rider joins an offer by pointing their
+-- request at it; the number of requests on an offer is capped by its seats.
+CREATE TABLE IF NOT EXISTS "RideOffer" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "driverId" TEXT NOT NULL,
+    "seats" INTEGER NOT NULL CHECK ("seats" > 0),
+    "departureArea" TEXT NOT NULL,
+    "departureTime" TIMESTAMP(3) NOT NULL,
+    "notes" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "RideOffer_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "RideOffer_driverId_fkey" FOREIGN KEY ("driverId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "RideOffer_inviteId_driverId_key" ON "RideOffer"("inviteId", "driverId");
+
+CREATE TABLE IF NOT EXISTS "RideRequest" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "riderId" TEXT NOT NULL,
+    "offerId" TEXT, -- Set once the rider joined an offer
+    "pickupArea" TEXT,
+    "notes" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "RideRequest_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "RideRequest_riderId_fkey" FOREIGN KEY ("riderId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "RideRequest_offerId_fkey" FOREIGN KEY ("offerId") REFERENCES "RideOffer"("id") ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "RideRequest_inviteId_riderId_key" ON "RideRequest"("inviteId", "riderId");
+CREATE INDEX IF NOT EXISTS "RideRequest_offerId_idx" ON "RideRequest"("offerId");
@@ -0,0 +1,21 @@
+-- Actual attendance. A check-in belongs to a member RSVP (userId), a guest
+-- RSVP (guestRsvpId) or is a walk-in recorded by name only.
+CREATE TABLE IF NOT EXISTS "CheckIn" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "userId" TEXT,
+    "guestRsvpId" TEXT,
+    "name" TEXT,
+    "guestCount" INTEGER NOT NULL DEFAULT 1 CHECK ("guestCount" > 0),
+    "checkedInById" TEXT NOT NULL,
+    "checkedInAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "CheckIn_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "CheckIn_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "CheckIn_guestRsvpId_fkey" FOREIGN KEY ("guestRsvpId") REFERENCES "GuestRSVP"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "CheckIn_checkedInById_fkey" FOREIGN KEY ("checkedInById") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "CheckIn_inviteId_userId_key" ON "CheckIn"("inviteId", "userId") WHERE "userId" IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "CheckIn_inviteId_guestRsvpId_key" ON "CheckIn"("inviteId", "guestRsvpId") WHERE "guestRsvpId" IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "CheckIn_userId_idx" ON "CheckIn"("userId");
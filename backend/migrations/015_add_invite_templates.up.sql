@@ -0,0 +1,21 @@
+-- Reusable invite templates. A template with a circleId is shared with the
+-- circle's active members and creates invites in that circle; otherwise it is
+-- private to its owner.
+CREATE TABLE IF NOT EXISTS "InviteTemplate" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "name" TEXT NOT NULL,
+    "ownerId" TEXT NOT NULL,
+    "circleId" TEXT,
+    "title" TEXT NOT NULL,
+    "description" TEXT,
+    "location" TEXT,
+    "mapLink" TEXT,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "InviteTemplate_ownerId_fkey" FOREIGN KEY ("ownerId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "InviteTemplate_circleId_fkey" FOREIGN KEY ("circleId") REFERENCES "Circle"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "InviteTemplate_ownerId_idx" ON "InviteTemplate"("ownerId");
+CREATE INDEX IF NOT EXISTS "InviteTemplate_circleId_idx" ON "InviteTemplate"("circleId");
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "Invite_latitude_longitude_idx";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "longitude";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "latitude";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "country";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "postalCode";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "region";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "city";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "addressLine";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "placeName";
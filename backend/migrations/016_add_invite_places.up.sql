@@ -0,0 +1,14 @@
+-- Structured location for invites. "location" stays the free text shown to
+-- guests; these columns hold its components and coordinates, which come from
+-- the client, the pasted map link or the geocoder.
+ALTER TABLE "Invite" ADD COLUMN "placeName" TEXT;
+ALTER TABLE "Invite" ADD COLUMN "addressLine" TEXT;
+ALTER TABLE "Invite" ADD COLUMN "city" TEXT;
+ALTER TABLE "Invite" ADD COLUMN "region" TEXT;
+ALTER TABLE "Invite" ADD COLUMN "postalCode" TEXT;
+ALTER TABLE "Invite" ADD COLUMN "country" TEXT;
+ALTER TABLE "Invite" ADD COLUMN "latitude" DOUBLE PRECISION;
+ALTER TABLE "Invite" ADD COLUMN "longitude" DOUBLE PRECISION;
+
+-- Bounding-box prefilter for "events near me"
+CREATE INDEX IF NOT EXISTS "Invite_latitude_longitude_idx" ON "Invite"("latitude", "longitude") WHERE "latitude" IS NOT NULL;
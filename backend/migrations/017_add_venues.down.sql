@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "Invite_venueId_idx";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "venueId";
+DROP TABLE IF EXISTS "Venue";
@@ -0,0 +1,24 @@
+-- Saved venues shared by a circle's members. Invites created at a venue keep
+-- a reference to it, which is what usage counts and suggestions are based on.
+CREATE TABLE IF NOT EXISTS "Venue" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "circleId" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "address" TEXT,
+    "mapLink" TEXT,
+    "notes" TEXT, -- Parking, entrance, accessibility...
+    "capacity" INTEGER,
+    "latitude" DOUBLE PRECISION,
+    "longitude" DOUBLE PRECISION,
+    "createdById" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "Venue_circleId_fkey" FOREIGN KEY ("circleId") REFERENCES "Circle"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "Venue_createdById_fkey" FOREIGN KEY ("createdById") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "Venue_circleId_idx" ON "Venue"("circleId");
+
+ALTER TABLE "Invite" ADD COLUMN "venueId" TEXT REFERENCES "Venue"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+CREATE INDEX IF NOT EXISTS "Invite_venueId_idx" ON "Invite"("venueId");
@@ -0,0 +1 @@
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "endDate";
@@ -0,0 +1,3 @@
+-- Optional end time. Invites without one are treated as lasting the default
+-- duration (models.DefaultEventDuration) for calendars and overlap checks.
+ALTER TABLE "Invite" ADD COLUMN "endDate" TIMESTAMP(3);
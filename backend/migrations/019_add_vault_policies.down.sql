@@ -0,0 +1,5 @@
+ALTER TABLE "Invite" DROP CONSTRAINT IF EXISTS "Invite_vaultPolicy_check";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "vaultMinContributors";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "vaultUnlockAt";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "vaultUnlockAfterHours";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "vaultPolicy";
@@ -0,0 +1,10 @@
+-- How the Memory Vault opens. Existing invites get DELAY with 24 hours, close
+-- to the old hard-coded rule; delays now count from the event's end rather
+-- than its start.
+ALTER TABLE "Invite" ADD COLUMN "vaultPolicy" TEXT NOT NULL DEFAULT 'DELAY';
+ALTER TABLE "Invite" ADD COLUMN "vaultUnlockAfterHours" INTEGER DEFAULT 24;
+ALTER TABLE "Invite" ADD COLUMN "vaultUnlockAt" TIMESTAMP(3);
+ALTER TABLE "Invite" ADD COLUMN "vaultMinContributors" INTEGER;
+
+ALTER TABLE "Invite" ADD CONSTRAINT "Invite_vaultPolicy_check"
+    CHECK ("vaultPolicy" IN ('AFTER_EVENT', 'DELAY', 'ON_DATE', 'MANUAL', 'CONTRIBUTORS'));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "Invite_vault_locked_idx";
+ALTER TABLE "Invite" DROP COLUMN IF EXISTS "vaultNotifiedAt";
@@ -0,0 +1,17 @@
+-- Set once the "your memories are unlocked" notification has been claimed, so
+-- several worker instances never notify attendees of the same vault twice.
+ALTER TABLE "Invite" ADD COLUMN "vaultNotifiedAt" TIMESTAMP(3);
+
+-- Vaults used to unlock only when someone opened the invite, so past events
+-- nobody revisited are still locked. Open the ones whose DELAY has run out
+-- here, so attendees are not emailed about events that may be years old.
+UPDATE "Invite"
+SET "isVaultUnlocked" = TRUE,
+    "vaultUnlockDate" = COALESCE("endDate", "eventDate" + INTERVAL '3 hours') + make_interval(hours => COALESCE("vaultUnlockAfterHours", 24))
+WHERE NOT "isVaultUnlocked" AND NOT "isDateTBD" AND "vaultPolicy" = 'DELAY'
+  AND COALESCE("endDate", "eventDate" + INTERVAL '3 hours') + make_interval(hours => COALESCE("vaultUnlockAfterHours", 24)) <= NOW();
+
+-- Vaults that are already open have nothing left to announce
+UPDATE "Invite" SET "vaultNotifiedAt" = COALESCE("vaultUnlockDate", NOW()) WHERE "isVaultUnlocked";
+
+CREATE INDEX IF NOT EXISTS "Invite_vault_locked_idx" ON "Invite"("eventDate") WHERE NOT "isVaultUnlocked";
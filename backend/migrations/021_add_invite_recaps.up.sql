@@ -0,0 +1,12 @@
+-- Static summary of an event, generated once its Memory Vault unlocks. The
+-- manifest is the JSON the app renders; html is a standalone page for the
+-- optional public link, which only works while publicToken is set.
+CREATE TABLE IF NOT EXISTS "InviteRecap" (
+    "inviteId" TEXT NOT NULL PRIMARY KEY,
+    "manifest" JSONB NOT NULL,
+    "html" TEXT NOT NULL,
+    "publicToken" TEXT UNIQUE,
+    "generatedAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "InviteRecap_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
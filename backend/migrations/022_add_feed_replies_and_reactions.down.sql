@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "FeedReaction";
+DROP INDEX IF EXISTS "EventFeedItem_parentId_idx";
+ALTER TABLE "EventFeedItem" DROP COLUMN IF EXISTS "parentId";
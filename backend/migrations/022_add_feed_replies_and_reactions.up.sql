@@ -0,0 +1,17 @@
+-- Replies hang off a top-level post; threads are one level deep
+ALTER TABLE "EventFeedItem" ADD COLUMN "parentId" TEXT REFERENCES "EventFeedItem"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+CREATE INDEX IF NOT EXISTS "EventFeedItem_parentId_idx" ON "EventFeedItem"("parentId");
+
+-- One row per user and emoji, so the same reaction can't be counted twice
+CREATE TABLE IF NOT EXISTS "FeedReaction" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "feedItemId" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "emoji" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "FeedReaction_feedItemId_fkey" FOREIGN KEY ("feedItemId") REFERENCES "EventFeedItem"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "FeedReaction_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "FeedReaction_feedItemId_userId_emoji_key" ON "FeedReaction"("feedItemId", "userId", "emoji");
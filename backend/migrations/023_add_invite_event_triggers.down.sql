@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS "Invite_vault_unlocked_notify" ON "Invite";
+DROP TRIGGER IF EXISTS "MediaItem_notify" ON "MediaItem";
+DROP TRIGGER IF EXISTS "GuestRSVP_notify" ON "GuestRSVP";
+DROP TRIGGER IF EXISTS "RSVP_notify" ON "RSVP";
+DROP TRIGGER IF EXISTS "EventFeedItem_notify" ON "EventFeedItem";
+DROP FUNCTION IF EXISTS notify_invite_event();
@@ -0,0 +1,32 @@
+-- Invite activity is announced on the invite_events channel so every API
+-- instance can push it to its open event streams. Payloads only carry IDs;
+-- clients fetch the data itself through the regular, access-checked endpoints.
+CREATE OR REPLACE FUNCTION notify_invite_event() RETURNS trigger AS $$
+DECLARE
+    data JSONB := to_jsonb(NEW);
+BEGIN
+    PERFORM pg_notify('invite_events', json_build_object(
+        'type', TG_ARGV[0],
+        'inviteId', COALESCE(data->>'inviteId', data->>'id'),
+        'id', data->>'id'
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Sealed capsules are announced by the vault unlock instead
+CREATE TRIGGER "EventFeedItem_notify" AFTER INSERT ON "EventFeedItem"
+    FOR EACH ROW WHEN (NEW.type <> 'CAPSULE') EXECUTE FUNCTION notify_invite_event('feed.post');
+
+CREATE TRIGGER "RSVP_notify" AFTER INSERT OR UPDATE ON "RSVP"
+    FOR EACH ROW EXECUTE FUNCTION notify_invite_event('rsvp');
+
+CREATE TRIGGER "GuestRSVP_notify" AFTER INSERT OR UPDATE ON "GuestRSVP"
+    FOR EACH ROW EXECUTE FUNCTION notify_invite_event('rsvp');
+
+CREATE TRIGGER "MediaItem_notify" AFTER INSERT ON "MediaItem"
+    FOR EACH ROW EXECUTE FUNCTION notify_invite_event('media');
+
+CREATE TRIGGER "Invite_vault_unlocked_notify" AFTER UPDATE OF "isVaultUnlocked" ON "Invite"
+    FOR EACH ROW WHEN (NEW."isVaultUnlocked" AND NOT OLD."isVaultUnlocked")
+    EXECUTE FUNCTION notify_invite_event('vault.unlocked');
@@ -0,0 +1,19 @@
+-- One row per "@name" in a post. start/length locate it in the content in
+-- UTF-16 code units, the way clients index strings.
+CREATE TABLE IF NOT EXISTS "FeedMention" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "feedItemId" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "start" INTEGER NOT NULL,
+    "length" INTEGER NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Set once the notification has been claimed, so it is sent only once
+    "notifiedAt" TIMESTAMP(3),
+
+    CONSTRAINT "FeedMention_feedItemId_fkey" FOREIGN KEY ("feedItemId") REFERENCES "EventFeedItem"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "FeedMention_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "FeedMention_feedItemId_idx" ON "FeedMention"("feedItemId");
+CREATE INDEX IF NOT EXISTS "FeedMention_userId_idx" ON "FeedMention"("userId");
+CREATE INDEX IF NOT EXISTS "FeedMention_unnotified_idx" ON "FeedMention"("createdAt") WHERE "notifiedAt" IS NULL;
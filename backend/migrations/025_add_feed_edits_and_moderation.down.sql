@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS "EventFeedItem_update_notify" ON "EventFeedItem";
+DROP TABLE IF EXISTS "FeedPostRevision";
+ALTER TABLE "EventFeedItem" DROP COLUMN IF EXISTS "moderationReason";
+ALTER TABLE "EventFeedItem" DROP COLUMN IF EXISTS "removedById";
+ALTER TABLE "EventFeedItem" DROP COLUMN IF EXISTS "removedAt";
+ALTER TABLE "EventFeedItem" DROP COLUMN IF EXISTS "hiddenById";
+ALTER TABLE "EventFeedItem" DROP COLUMN IF EXISTS "hiddenAt";
+ALTER TABLE "EventFeedItem" DROP COLUMN IF EXISTS "editedAt";
@@ -0,0 +1,26 @@
+-- Authors can edit their posts; every edit keeps the previous text
+ALTER TABLE "EventFeedItem" ADD COLUMN "editedAt" TIMESTAMP(3);
+
+-- Hidden posts keep their content for the author and moderators. Removed
+-- posts lose it and stay behind as tombstones, so replies keep their parent.
+ALTER TABLE "EventFeedItem" ADD COLUMN "hiddenAt" TIMESTAMP(3);
+ALTER TABLE "EventFeedItem" ADD COLUMN "hiddenById" TEXT REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+ALTER TABLE "EventFeedItem" ADD COLUMN "removedAt" TIMESTAMP(3);
+ALTER TABLE "EventFeedItem" ADD COLUMN "removedById" TEXT REFERENCES "User"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+ALTER TABLE "EventFeedItem" ADD COLUMN "moderationReason" TEXT;
+
+-- The text a post had before each edit; createdAt is when it was replaced
+CREATE TABLE IF NOT EXISTS "FeedPostRevision" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "feedItemId" TEXT NOT NULL,
+    "content" TEXT NOT NULL,
+    "createdAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "FeedPostRevision_feedItemId_fkey" FOREIGN KEY ("feedItemId") REFERENCES "EventFeedItem"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "FeedPostRevision_feedItemId_idx" ON "FeedPostRevision"("feedItemId");
+
+-- Open event streams refetch edited, hidden and removed posts
+CREATE TRIGGER "EventFeedItem_update_notify" AFTER UPDATE OF content, "hiddenAt", "removedAt" ON "EventFeedItem"
+    FOR EACH ROW WHEN (NEW.type <> 'CAPSULE') EXECUTE FUNCTION notify_invite_event('feed.updated');
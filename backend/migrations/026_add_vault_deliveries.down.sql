@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "MediaItem_inviteId_userId_idx";
+DROP INDEX IF EXISTS "Invite_vault_pending_idx";
+CREATE INDEX IF NOT EXISTS "Invite_vault_locked_idx" ON "Invite"("eventDate") WHERE NOT "isVaultUnlocked";
+DROP TABLE IF EXISTS "VaultDelivery";
@@ -0,0 +1,22 @@
+-- One row per attendee told that a vault opened. Claims are per person, so a
+-- failed send can be released and retried without notifying everyone else
+-- again. "vaultNotifiedAt" is now only set once every attendee has been told.
+CREATE TABLE IF NOT EXISTS "VaultDelivery" (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "inviteId" TEXT NOT NULL,
+    "userId" TEXT NOT NULL,
+    "sentAt" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "VaultDelivery_inviteId_fkey" FOREIGN KEY ("inviteId") REFERENCES "Invite"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "VaultDelivery_userId_fkey" FOREIGN KEY ("userId") REFERENCES "User"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "VaultDelivery_inviteId_userId_key" ON "VaultDelivery"("inviteId", "userId");
+
+-- Matches the unlock worker's scan, which skips manual and polled vaults
+DROP INDEX IF EXISTS "Invite_vault_locked_idx";
+CREATE INDEX IF NOT EXISTS "Invite_vault_pending_idx" ON "Invite"("eventDate")
+    WHERE NOT "isVaultUnlocked" AND NOT "isDateTBD" AND "vaultPolicy" <> 'MANUAL';
+
+-- Contributor counts for CONTRIBUTORS vaults
+CREATE INDEX IF NOT EXISTS "MediaItem_inviteId_userId_idx" ON "MediaItem"("inviteId", "userId");
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "EventFeedItem_recap_stale_delete" ON "EventFeedItem";
+DROP TRIGGER IF EXISTS "EventFeedItem_recap_stale" ON "EventFeedItem";
+DROP FUNCTION IF EXISTS mark_recap_stale();
+DROP INDEX IF EXISTS "InviteRecap_stale_idx";
+ALTER TABLE "InviteRecap" DROP COLUMN IF EXISTS "staleAt";
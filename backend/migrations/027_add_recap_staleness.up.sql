@@ -0,0 +1,33 @@
+-- Set when a post the recap may quote is edited, hidden, unhidden or removed.
+-- The post is dropped from the manifest at once, and stale recaps are shown
+-- from the manifest until the recap job builds them again, keeping the
+-- public link.
+ALTER TABLE "InviteRecap" ADD COLUMN "staleAt" TIMESTAMP(3);
+
+CREATE INDEX IF NOT EXISTS "InviteRecap_stale_idx" ON "InviteRecap"("inviteId") WHERE "staleAt" IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION mark_recap_stale() RETURNS trigger AS $$
+BEGIN
+    UPDATE "InviteRecap" SET
+        "staleAt" = CURRENT_TIMESTAMP,
+        manifest = CASE WHEN jsonb_typeof(manifest->'highlights') = 'array'
+            THEN jsonb_set(manifest, '{highlights}', COALESCE((
+                SELECT jsonb_agg(h) FROM jsonb_array_elements(manifest->'highlights') h
+                WHERE h->>'type' <> OLD.type OR h->>'content' <> OLD.content
+            ), '[]'::jsonb))
+            ELSE manifest END
+    WHERE "inviteId" = OLD."inviteId";
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "EventFeedItem_recap_stale" AFTER UPDATE OF content, "hiddenAt", "removedAt" ON "EventFeedItem"
+    FOR EACH ROW WHEN (NEW.type IN ('UPDATE', 'CAPSULE')
+        AND (NEW.content IS DISTINCT FROM OLD.content
+            OR NEW."hiddenAt" IS DISTINCT FROM OLD."hiddenAt"
+            OR NEW."removedAt" IS DISTINCT FROM OLD."removedAt"))
+    EXECUTE FUNCTION mark_recap_stale();
+
+CREATE TRIGGER "EventFeedItem_recap_stale_delete" AFTER DELETE ON "EventFeedItem"
+    FOR EACH ROW WHEN (OLD.type IN ('UPDATE', 'CAPSULE'))
+    EXECUTE FUNCTION mark_recap_stale();
@@ -0,0 +1,8 @@
+ALTER TABLE "InviteTemplate" DROP COLUMN IF EXISTS "longitude";
+ALTER TABLE "InviteTemplate" DROP COLUMN IF EXISTS "latitude";
+ALTER TABLE "InviteTemplate" DROP COLUMN IF EXISTS "country";
+ALTER TABLE "InviteTemplate" DROP COLUMN IF EXISTS "postalCode";
+ALTER TABLE "InviteTemplate" DROP COLUMN IF EXISTS "region";
+ALTER TABLE "InviteTemplate" DROP COLUMN IF EXISTS "city";
+ALTER TABLE "InviteTemplate" DROP COLUMN IF EXISTS "addressLine";
+ALTER TABLE "InviteTemplate" DROP COLUMN IF EXISTS "placeName";
@@ -0,0 +1,10 @@
+-- Templates keep the structured place of their location, like invites, so
+-- invites made from them have coordinates and show up in nearby search.
+ALTER TABLE "InviteTemplate" ADD COLUMN "placeName" TEXT;
+ALTER TABLE "InviteTemplate" ADD COLUMN "addressLine" TEXT;
+ALTER TABLE "InviteTemplate" ADD COLUMN "city" TEXT;
+ALTER TABLE "InviteTemplate" ADD COLUMN "region" TEXT;
+ALTER TABLE "InviteTemplate" ADD COLUMN "postalCode" TEXT;
+ALTER TABLE "InviteTemplate" ADD COLUMN "country" TEXT;
+ALTER TABLE "InviteTemplate" ADD COLUMN "latitude" DOUBLE PRECISION;
+ALTER TABLE "InviteTemplate" ADD COLUMN "longitude" DOUBLE PRECISION;
@@ -2,21 +2,91 @@
 
 import { revalidatePath } from "next/cache";
 import { fetchFromBackend } from "@/lib/api";
+import { FeedPostRevision, MentionPage } from "@/types";
 
 export async function createFeedItem(
   inviteId: string,
   content: string,
   type: string = "UPDATE",
+  parentId?: string,
 ) {
-  const result = await fetchFromBackend("/feed", {
+  const result = await fetchFromBackend(`/invites/${inviteId}/feed`, {
     method: "POST",
     body: JSON.stringify({
-      inviteId,
       content,
-      type
+      type,
+      parentId
     })
   });
 
   revalidatePath(`/event/${inviteId}`);
   return result;
 }
+
+export async function editFeedItem(inviteId: string, postId: string, content: string) {
+  const result = await fetchFromBackend(`/invites/${inviteId}/feed/${postId}`, {
+    method: "PATCH",
+    body: JSON.stringify({ content })
+  });
+
+  revalidatePath(`/event/${inviteId}`);
+  return result;
+}
+
+export async function deleteFeedItem(inviteId: string, postId: string) {
+  const result = await fetchFromBackend(`/invites/${inviteId}/feed/${postId}`, {
+    method: "DELETE"
+  });
+
+  revalidatePath(`/event/${inviteId}`);
+  return result;
+}
+
+export async function getFeedItemRevisions(inviteId: string, postId: string): Promise<FeedPostRevision[]> {
+  return fetchFromBackend(`/invites/${inviteId}/feed/${postId}/revisions`);
+}
+
+export async function moderateFeedItem(
+  inviteId: string,
+  postId: string,
+  action: "HIDE" | "UNHIDE" | "REMOVE",
+  reason?: string,
+) {
+  const result = await fetchFromBackend(`/invites/${inviteId}/feed/${postId}/moderation`, {
+    method: "POST",
+    body: JSON.stringify({ action, reason })
+  });
+
+  revalidatePath(`/event/${inviteId}`);
+  return result;
+}
+
+export async function addReaction(inviteId: string, postId: string, emoji: string) {
+  const result = await fetchFromBackend(`/invites/${inviteId}/feed/${postId}/reactions`, {
+    method: "POST",
+    body: JSON.stringify({ emoji })
+  });
+
+  revalidatePath(`/event/${inviteId}`);
+  return result;
+}
+
+export async function removeReaction(inviteId: string, postId: string, emoji: string) {
+  const result = await fetchFromBackend(
+    `/invites/${inviteId}/feed/${postId}/reactions?emoji=${encodeURIComponent(emoji)}`,
+    { method: "DELETE" }
+  );
+
+  revalidatePath(`/event/${inviteId}`);
+  return result;
+}
+
+export async function getMyMentions(cursor?: string): Promise<MentionPage> {
+  const query = cursor ? `?cursor=${encodeURIComponent(cursor)}` : "";
+  try {
+    return await fetchFromBackend(`/users/me/mentions${query}`);
+  } catch (err) {
+    console.error("Failed to fetch mentions:", err);
+    return { items: [], nextCursor: null };
+  }
+}
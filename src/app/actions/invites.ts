@@ -2,7 +2,7 @@
 
 import { revalidatePath } from "next/cache";
 import { fetchFromBackend } from "@/lib/api";
-import { InviteDetails, Invite } from "@/types";
+import { InviteDetails, InvitePage, InviteWithRelations } from "@/types";
 
 export async function createInvite(formData: FormData) {
   const title = formData.get("title") as string;
@@ -73,13 +73,38 @@ export async function deleteInvite(inviteId: string) {
   return result;
 }
 
-export async function getMyInvites(): Promise<Invite[]> {
+// getMyInvites loads the dashboard: every upcoming invite plus the most recent
+// page of past ones. nextCursor pages further into the past via getPastInvites.
+export async function getMyInvites(): Promise<InvitePage> {
   try {
-    const res = await fetchFromBackend("/invites");
-    return res || [];
+    const invites: InviteWithRelations[] = [];
+    let endpoint = "/invites?when=upcoming&limit=100";
+    for (;;) {
+      const res = await fetchFromBackend(endpoint);
+      invites.push(...(res?.invites || []));
+      if (!res?.nextCursor) {
+        break;
+      }
+      endpoint = `/invites?when=upcoming&limit=100&cursor=${encodeURIComponent(res.nextCursor)}`;
+    }
+
+    const past = await getPastInvites();
+    return { invites: [...invites, ...past.invites], nextCursor: past.nextCursor };
   } catch (err) {
     console.error("Failed to fetch invites:", err);
-    return [];
+    return { invites: [], nextCursor: null };
+  }
+}
+
+// getPastInvites returns one page of past invites, most recent first
+export async function getPastInvites(cursor?: string): Promise<InvitePage> {
+  const query = cursor ? `&cursor=${encodeURIComponent(cursor)}` : "";
+  try {
+    const res = await fetchFromBackend(`/invites?when=past${query}`);
+    return { invites: res?.invites || [], nextCursor: res?.nextCursor ?? null };
+  } catch (err) {
+    console.error("Failed to fetch past invites:", err);
+    return { invites: [], nextCursor: null };
   }
 }
 
@@ -0,0 +1,41 @@
+import { cookies } from "next/headers";
+
+const BACKEND_URL = process.env.BACKEND_URL || "http://localhost:8080/api";
+
+// EventSource can't send an Authorization header, so the browser streams
+// through here and the session cookie is forwarded as a bearer token.
+export async function GET(
+  request: Request,
+  { params }: { params: Promise<{ id: string }> },
+) {
+  const { id } = await params;
+  const cookieStore = await cookies();
+  const token = (
+    cookieStore.get("__Secure-authjs.session-token") ||
+    cookieStore.get("authjs.session-token") ||
+    cookieStore.get("__Secure-next-auth.session-token") ||
+    cookieStore.get("next-auth.session-token")
+  )?.value;
+
+  if (!token) {
+    return new Response("Unauthorized", { status: 401 });
+  }
+
+  const response = await fetch(`${BACKEND_URL}/invites/${encodeURIComponent(id)}/events`, {
+    headers: { Authorization: `Bearer ${token}`, Accept: "text/event-stream" },
+    signal: request.signal,
+    cache: "no-store",
+  });
+
+  if (!response.ok || !response.body) {
+    return new Response(await response.text(), { status: response.status });
+  }
+
+  return new Response(response.body, {
+    headers: {
+      "Content-Type": "text/event-stream",
+      "Cache-Control": "no-cache, no-transform",
+      "X-Accel-Buffering": "no",
+    },
+  });
+}
@@ -12,7 +12,7 @@ declare module "next-auth" {
 
 export const { handlers, auth, signIn, signOut } = NextAuth({
 	callbacks: {
-		async jwt({ token, user, trigger }) {
+		async jwt({ token, user, trigger, profile }) {
 			// When user signs in, sync with backend
 			if (user && trigger === "signIn") {
 				try {
@@ -23,7 +23,14 @@ export const { handlers, auth, signIn, signOut } = NextAuth({
 					const { SignJWT } = await import("jose");
 					const secret = process.env.NEXTAUTH_SECRET;
 					const alg = "HS256";
-					const syncToken = await new SignJWT({ sub: user.id || token.sub })
+					// Only the provider can vouch for the address; the backend links
+					// pending email invites on this claim alone
+					const emailVerified = profile?.email_verified === true;
+					const syncToken = await new SignJWT({
+						sub: user.id || token.sub,
+						email: user.email,
+						email_verified: emailVerified,
+					})
 						.setProtectedHeader({ alg })
 						.setIssuedAt()
 						.setExpirationTime("15m") // Short lived
@@ -39,7 +46,6 @@ export const { handlers, auth, signIn, signOut } = NextAuth({
 							name: user.name,
 							email: user.email,
 							image: user.image,
-							emailVerified: null,
 						}),
 					});
 				} catch (e: any) {
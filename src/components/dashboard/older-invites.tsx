@@ -0,0 +1,48 @@
+"use client";
+
+import { useState } from "react";
+import { getPastInvites } from "@/app/actions/invites";
+import { Button } from "@/components/ui/button";
+import { InviteRow } from "@/components/dashboard/invite-row";
+import { InviteWithRelations } from "@/types";
+import { toast } from "sonner";
+
+// OlderInvites pages further into past invites on demand, starting at cursor
+export function OlderInvites({ cursor }: { cursor: string | null }) {
+  const [invites, setInvites] = useState<InviteWithRelations[]>([]);
+  const [nextCursor, setNextCursor] = useState(cursor);
+  const [loading, setLoading] = useState(false);
+
+  async function loadMore() {
+    if (!nextCursor) return;
+    setLoading(true);
+    try {
+      const page = await getPastInvites(nextCursor);
+      setInvites((prev) => [...prev, ...page.invites]);
+      setNextCursor(page.nextCursor);
+    } catch {
+      toast.error("Failed to load older invites.");
+    } finally {
+      setLoading(false);
+    }
+  }
+
+  return (
+    <>
+      {invites.map((invite) => (
+        <InviteRow key={invite.id} invite={invite} />
+      ))}
+      {nextCursor && (
+        <Button
+          variant="ghost"
+          size="sm"
+          className="w-full text-muted-foreground"
+          disabled={loading}
+          onClick={loadMore}
+        >
+          {loading ? "Loading..." : "Show older invites"}
+        </Button>
+      )}
+    </>
+  );
+}
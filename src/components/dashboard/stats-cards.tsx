@@ -1,10 +1,10 @@
-import { CirclePreview, InviteWithRelations } from "@/types";
+import { CirclePreview, InvitePage } from "@/types";
 import { Calendar, CircleUser } from "lucide-react";
 import { use } from "react";
 
 interface StatsCardsProps {
 	circlesPromise: Promise<CirclePreview[]>;
-	invitesPromise: Promise<InviteWithRelations[]>;
+	invitesPromise: Promise<InvitePage>;
 }
 
 export function StatsCards({
@@ -12,7 +12,7 @@ export function StatsCards({
 	invitesPromise,
 }: StatsCardsProps) {
 	const circles = use(circlesPromise);
-	const invites = use(invitesPromise);
+	const { invites } = use(invitesPromise);
 
 	const upcomingInvites = invites.filter(
 		(i) => new Date(i.eventDate) > new Date(),
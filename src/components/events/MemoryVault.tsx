@@ -29,7 +29,7 @@ export function MemoryVault({ invite }: MemoryVaultProps) {
           </h4>
           
           <p className="text-xs text-purple-200/70 mb-6 font-medium uppercase tracking-widest">
-            {invite.mediaItems?.length || 0} Memories Inside
+            {invite.vault?.itemCount ?? invite.mediaItems?.length ?? 0} Memories Inside
           </p>
 
           <Link href={`/event/${invite.id}/gallery`} className="w-full">
@@ -45,9 +45,9 @@ export function MemoryVault({ invite }: MemoryVaultProps) {
     );
   }
 
-  // If locked, calculate unlock time (24h after event start)
-  const eventDate = new Date(invite.eventDate);
-  const unlockDate = new Date(eventDate.getTime() + 24 * 60 * 60 * 1000);
+  // If locked, the server tells us when (manual and contributor policies have no date)
+  const unlockDate = invite.vault?.unlocksAt ? new Date(invite.vault.unlocksAt) : null;
+  const sealedCount = invite.vault?.itemCount ?? 0;
   
   // Simple countdown text
   const [timeUntilUnlock, setTimeUntilUnlock] = useState("");
@@ -55,7 +55,13 @@ export function MemoryVault({ invite }: MemoryVaultProps) {
   useEffect(() => {
     const updateTime = () => {
       const now = new Date();
-      if (now < unlockDate) {
+      if (!unlockDate) {
+        setTimeUntilUnlock(
+          invite.vaultPolicy === "CONTRIBUTORS"
+            ? `once ${invite.vaultMinContributors} people share memories`
+            : "when the host opens it"
+        );
+      } else if (now < unlockDate) {
         setTimeUntilUnlock(formatDistance(unlockDate, now, { addSuffix: true }));
       } else {
         setTimeUntilUnlock("soon..."); // Should refrain via server revalidation usually
@@ -65,7 +71,7 @@ export function MemoryVault({ invite }: MemoryVaultProps) {
     updateTime();
     const timer = setInterval(updateTime, 60000); // Update every minute
     return () => clearInterval(timer);
-  }, [unlockDate]);
+  }, [invite.vault?.unlocksAt]);
 
   return (
     <div className="glass p-8 rounded-[2.5rem] border-white/10 bg-linear-to-br from-blue-500/5 to-purple-500/5 items-center justify-center flex flex-col text-center shadow-lg relative overflow-hidden">
@@ -83,7 +89,9 @@ export function MemoryVault({ invite }: MemoryVaultProps) {
 
       <div className="w-full px-4 py-3 rounded-2xl bg-white/5 border border-white/5 text-xs text-white/40 flex items-center justify-center gap-2">
         <Clock className="w-3.5 h-3.5" />
-        <span>{format(unlockDate, "MMM d, h:mm a")}</span>
+        <span>
+          {unlockDate ? format(unlockDate, "MMM d, h:mm a") : `${sealedCount} sealed`}
+        </span>
       </div>
     </div>
   );
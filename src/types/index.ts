@@ -28,6 +28,8 @@ export interface Invite {
   mapLink?: string;
   isVaultUnlocked: boolean;
   vaultUnlockDate?: string; // ISO string
+  vaultPolicy?: "AFTER_EVENT" | "DELAY" | "ON_DATE" | "MANUAL" | "CONTRIBUTORS";
+  vaultMinContributors?: number;
   createdAt: string;
   updatedAt: string;
 }
@@ -46,8 +48,64 @@ export interface FeedItem {
   inviteId: string;
   userId: string;
   content: string;
-  type: "MESSAGE" | "ANNOUNCEMENT";
+  type: "UPDATE" | "CHAT" | "CAPSULE";
+  parentId?: string; // Set on replies
   createdAt: string;
+  mentions?: MentionSpan[];
+  editedAt?: string;
+  hiddenAt?: string; // Content is withheld from other members while hidden
+  removedAt?: string; // A tombstone: content is empty
+  moderationReason?: string; // Only sent to the author and moderators
+}
+
+// An earlier text of an edited post
+export interface FeedPostRevision {
+  id: string;
+  feedItemId: string;
+  content: string;
+  replacedAt: string;
+}
+
+// Locates an "@name" in a post: content.slice(start, start + length)
+export interface MentionSpan {
+  userId: string;
+  start: number;
+  length: number;
+}
+
+export interface ReactionCount {
+  emoji: string;
+  count: number;
+  reacted: boolean; // Whether the current user is one of them
+}
+
+export type FeedPost = FeedItem & {
+  user: User;
+  replyCount: number;
+  reactions: ReactionCount[];
+};
+
+// A page of GET /api/invites; nextCursor continues the same listing
+export interface InvitePage {
+  invites: InviteWithRelations[];
+  nextCursor: string | null;
+}
+
+export interface FeedPage {
+  items: Array<FeedPost & { postedAt: string }>;
+  nextCursor: string | null;
+}
+
+export interface MentionPage {
+  items: Array<FeedPost & { postedAt: string; inviteTitle: string }>;
+  nextCursor: string | null;
+}
+
+// Pushed on /api/invites/{id}/events; refetch what the event points at
+export interface InviteEvent {
+  type: "feed.post" | "feed.updated" | "rsvp" | "media" | "vault.unlocked" | "resync";
+  inviteId: string;
+  id?: string;
 }
 
 export interface MediaItem {
@@ -97,8 +155,16 @@ export interface InviteDetails extends Invite {
   sender: User;
   circle?: CircleWithMembers;
   rsvps: Array<RSVP & { user: User }>;
-  feedItems: Array<FeedItem & { user: User }>;
-  mediaItems: MediaItem[];
+  feedItems: FeedPost[];
+  mediaItems: MediaItem[]; // Only your own uploads while the vault is sealed
+  vault?: VaultSummary;
+}
+
+export interface VaultSummary {
+  sealed: boolean;
+  itemCount: number;
+  contributorCount: number;
+  unlocksAt?: string; // ISO string, only for time-based unlock policies
 }
 
 export interface CirclePreview extends Circle {